@@ -1,6 +1,6 @@
 module github.com/nametake/protoc-gen-gohttp/_examples
 
-go 1.17
+go 1.22
 
 require (
 	github.com/google/go-cmp v0.5.6
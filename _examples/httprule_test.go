@@ -5,15 +5,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestMessaging_GetMessage(t *testing.T) {
@@ -457,3 +461,427 @@ func TestMessaging_CreateMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterMessagingHTTPHandlers(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "GET with path value",
+			method:     http.MethodGet,
+			target:     "/api/v1/messages/abc1234?message=hello",
+			wantStatus: http.StatusOK,
+			want:       `{"messageId":"abc1234","message":"hello"}`,
+		},
+		{
+			name:       "PUT with nested path value",
+			method:     http.MethodPut,
+			target:     "/api/v1/messages/abc1234/submsg",
+			body:       `{"message":"hello"}`,
+			wantStatus: http.StatusOK,
+			want:       `{"messageId":"abc1234","sub":{"subfield":"submsg"},"message":"hello"}`,
+		},
+		{
+			name:       "unregistered method",
+			method:     http.MethodDelete,
+			target:     "/api/v1/messages/abc1234",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	mux := http.NewServeMux()
+	RegisterMessagingHTTPHandlers(mux, NewMessagingHTTPConverter(&Messaging{}))
+	// Path values are read from the mux even when the handler is mounted under a prefix.
+	root := http.NewServeMux()
+	root.Handle("/api/", http.StripPrefix("/api", mux))
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			root.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.want == "" {
+				return
+			}
+			var got, want interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegisterMessagingHTTPRoutes(t *testing.T) {
+	type route struct {
+		Method  string
+		Pattern string
+	}
+	var got []route
+	RegisterMessagingHTTPRoutes(MessagingHTTPRouterFunc(func(method, pattern string, handler http.Handler) {
+		if handler == nil {
+			t.Errorf("handler of %s %s is nil", method, pattern)
+		}
+		got = append(got, route{Method: method, Pattern: pattern})
+	}), NewMessagingHTTPConverter(&Messaging{}))
+
+	want := []route{
+		{Method: http.MethodGet, Pattern: "/v1/messages/{message_id}"},
+		{Method: http.MethodPut, Pattern: "/v1/messages/{message_id}/{sub.subfield}"},
+		{Method: http.MethodPost, Pattern: "/v1/messages/{message_id}/{msg.sub.subfield}/{sub.subfield}"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	var fullMethods []string
+	for _, r := range MessagingHTTPRoutes(NewMessagingHTTPConverter(&Messaging{})) {
+		fullMethods = append(fullMethods, r.FullMethod)
+	}
+	if diff := cmp.Diff(fullMethods, []string{
+		"/main.Messaging/GetMessage",
+		"/main.Messaging/UpdateMessage",
+		"/main.Messaging/CreateMessage",
+	}); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestMessagingHTTPClient(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterMessagingHTTPHandlers(mux, NewMessagingHTTPConverter(&Messaging{}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, contentType := range []string{"application/json", "application/protobuf"} {
+		contentType := contentType
+		t.Run(contentType, func(t *testing.T) {
+			client := NewMessagingHTTPClient(srv.URL+"/", WithMessagingHTTPClientContentType(contentType))
+			ctx := context.Background()
+
+			getResp, err := client.GetMessage(ctx, &GetMessageRequest{
+				MessageId: "abc/1234 x",
+				Message:   "hello",
+				Tags:      []string{"a", "b"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(getResp, &GetMessageResponse{
+				MessageId: "abc/1234 x",
+				Message:   "hello",
+				Tags:      []string{"a", "b"},
+			}, cmpopts.IgnoreUnexported(GetMessageResponse{})); diff != "" {
+				t.Errorf("%s", diff)
+			}
+
+			updateResp, err := client.UpdateMessage(ctx, &UpdateMessageRequest{
+				MessageId: "abc1234",
+				Sub:       &SubMessage{Subfield: "submsg"},
+				Message:   "hello",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(updateResp, &UpdateMessageResponse{
+				MessageId: "abc1234",
+				Sub:       &SubMessage{Subfield: "submsg"},
+				Message:   "hello",
+			}, cmpopts.IgnoreUnexported(UpdateMessageResponse{}, SubMessage{})); diff != "" {
+				t.Errorf("%s", diff)
+			}
+
+			createResp, err := client.CreateMessage(ctx, &CreateMessageRequest{
+				MessageId: "abc1234",
+				Sub:       &SubMessage{Subfield: "sub"},
+				Msg:       &CreateMessageRequest_Message{Sub: &SubMessage{Subfield: "msgsub"}},
+				Opt:       "opt",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(createResp, &CreateMessageResponse{
+				MessageId: "abc1234",
+				Sub:       &SubMessage{Subfield: "sub"},
+				Msg:       &CreateMessageResponse_Message{Sub: &SubMessage{Subfield: "msgsub"}},
+				Opt:       "opt",
+			}, cmpopts.IgnoreUnexported(CreateMessageResponse{}, CreateMessageResponse_Message{}, SubMessage{})); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestMessagingHTTPClient_ClientConn(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterMessagingHTTPHandlers(mux, NewMessagingHTTPConverter(&Messaging{}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := NewMessagingClient(NewMessagingHTTPClient(srv.URL))
+	resp, err := client.GetMessage(context.Background(), &GetMessageRequest{
+		MessageId: "abc1234",
+		Message:   "hello",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(resp, &GetMessageResponse{
+		MessageId: "abc1234",
+		Message:   "hello",
+	}, cmpopts.IgnoreUnexported(GetMessageResponse{})); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestMessagingHTTPRoutesHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	MessagingHTTPRoutesHandler(NewMessagingHTTPConverter(&Messaging{})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/routes", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want %q", got, "application/json")
+	}
+	var got, want interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`[
+		{"method":"GET","pattern":"/v1/messages/{message_id}","fullMethod":"/main.Messaging/GetMessage"},
+		{"method":"PUT","pattern":"/v1/messages/{message_id}/{sub.subfield}","fullMethod":"/main.Messaging/UpdateMessage"},
+		{"method":"POST","pattern":"/v1/messages/{message_id}/{msg.sub.subfield}/{sub.subfield}","fullMethod":"/main.Messaging/CreateMessage"}
+	]`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestMessagingHTTPOpenAPIHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	MessagingHTTPOpenAPIHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want %q", got, "application/json")
+	}
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("openapi = %q, want %q", doc.OpenAPI, "3.0.3")
+	}
+	if got := doc.Paths["/v1/messages/{message_id}"]["get"].OperationID; got != "Messaging_GetMessage" {
+		t.Errorf("operationId = %q, want %q", got, "Messaging_GetMessage")
+	}
+}
+
+func TestMessaging_HTTPRule_MethodCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []MessagingHTTPConverterOption
+		method     string
+		wantStatus int
+		wantAllow  string
+	}{
+		{
+			name:       "matched method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "HEAD for GET",
+			method:     http.MethodHead,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unmatched method",
+			method:     http.MethodPost,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantAllow:  "GET, HEAD",
+		},
+		{
+			name:       "skip method check",
+			opts:       []MessagingHTTPConverterOption{WithMessagingHTTPSkipMethodCheck(true)},
+			method:     http.MethodPost,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, hf := NewMessagingHTTPConverter(&Messaging{}, tt.opts...).GetMessageHTTPRule(nil)
+			req := httptest.NewRequest(tt.method, "/v1/messages/abc1234", bytes.NewBufferString(`{}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			hf.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+type recordingTracer struct {
+	method     string
+	route      string
+	arg, ret   proto.Message
+	statusCode int
+	err        error
+	traced     bool
+}
+
+type traceKey struct{}
+
+func (t *recordingTracer) OnStart(ctx context.Context, method protoreflect.MethodDescriptor, route string, r *http.Request) context.Context {
+	t.method = fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+	t.route = route
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+func (t *recordingTracer) OnFinish(ctx context.Context, method protoreflect.MethodDescriptor, arg, ret proto.Message, statusCode int, err error, elapsed time.Duration) {
+	t.arg, t.ret, t.statusCode, t.err = arg, ret, statusCode, err
+	t.traced = ctx.Value(traceKey{}) == t
+}
+
+func TestMessagingHTTPConverter_Tracer(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		want   *recordingTracer
+	}{
+		{
+			name:   "ok",
+			method: http.MethodGet,
+			want: &recordingTracer{
+				method:     "/main.Messaging/GetMessage",
+				route:      "/v1/messages/{message_id}",
+				arg:        &GetMessageRequest{MessageId: "abc1234"},
+				ret:        &GetMessageResponse{MessageId: "abc1234"},
+				statusCode: http.StatusOK,
+				traced:     true,
+			},
+		},
+		{
+			name:   "method not allowed",
+			method: http.MethodPost,
+			want: &recordingTracer{
+				method:     "/main.Messaging/GetMessage",
+				route:      "/v1/messages/{message_id}",
+				statusCode: http.StatusMethodNotAllowed,
+				traced:     true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := &recordingTracer{}
+			_, _, hf := NewMessagingHTTPConverter(&Messaging{}, WithMessagingHTTPTracer(tracer)).GetMessageHTTPRule(nil)
+			req := httptest.NewRequest(tt.method, "/v1/messages/abc1234", nil)
+			rec := httptest.NewRecorder()
+			hf.ServeHTTP(rec, req)
+
+			if diff := cmp.Diff(tracer, tt.want, cmp.AllowUnexported(recordingTracer{}), protocmp.Transform()); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestMessagingHTTPConverter_AccessLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		payloads bool
+		want     map[string]interface{}
+	}{
+		{
+			name: "without payloads",
+			want: map[string]interface{}{
+				"level":       "INFO",
+				"msg":         "access",
+				"method":      "/main.Messaging/UpdateMessage",
+				"http_method": "PUT",
+				"route":       "/v1/messages/{message_id}/{sub.subfield}",
+				"status":      float64(http.StatusOK),
+				"code":        "OK",
+			},
+		},
+		{
+			name:     "with payloads",
+			payloads: true,
+			want: map[string]interface{}{
+				"level":       "INFO",
+				"msg":         "access",
+				"method":      "/main.Messaging/UpdateMessage",
+				"http_method": "PUT",
+				"route":       "/v1/messages/{message_id}/{sub.subfield}",
+				"status":      float64(http.StatusOK),
+				"code":        "OK",
+				"request":     `{"messageId":"abc1234","sub":{"subfield":"foo"},"message":"hello"}`,
+				"response":    `{"messageId":"abc1234","sub":{"subfield":"foo"},"message":"hello"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			_, _, hf := NewMessagingHTTPConverter(&Messaging{},
+				WithMessagingHTTPAccessLog(logger),
+				WithMessagingHTTPAccessLogPayloads(tt.payloads),
+			).UpdateMessageHTTPRule(nil)
+			req := httptest.NewRequest(http.MethodPut, "/v1/messages/abc1234/foo", bytes.NewBufferString(`{"message": "hello", "sub": {"secret": "password"}}`))
+			req.Header.Set("Content-Type", "application/json")
+			hf.ServeHTTP(httptest.NewRecorder(), req)
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := got["latency"]; !ok {
+				t.Errorf("latency is not logged: %s", buf.String())
+			}
+			delete(got, "time")
+			delete(got, "latency")
+			if diff := cmp.Diff(got, tt.want, cmpopts.AcyclicTransformer("compact", compactJSON)); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+// compactJSON removes the spaces of JSON strings, which protojson adds randomly.
+func compactJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return s
+	}
+	return buf.String()
+}
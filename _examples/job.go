@@ -0,0 +1,13 @@
+package main
+
+import (
+	"context"
+)
+
+var _ JobHTTPService = (*JobService)(nil)
+
+type JobService struct{}
+
+func (j *JobService) Invoke(ctx context.Context, req *InvokeRequest) (*InvokeResponse, error) {
+	return &InvokeResponse{Result: req.Name + ": " + req.Payload}, nil
+}
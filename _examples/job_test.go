@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJobHTTPClient(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterJobHTTPHandlers(mux, NewJobHTTPConverter(&JobService{}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := NewJobHTTPClient(srv.URL)
+	resp, err := client.Invoke(context.Background(), &InvokeRequest{Name: "backup", Payload: "now"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Result != "backup: now" {
+		t.Errorf("result = %q, want %q", resp.Result, "backup: now")
+	}
+
+	// The RPC named Invoke collides with grpc.ClientConnInterface, so the client is used through JobHTTPClientConn.
+	resp, err = NewJobClient(NewJobHTTPClientConn(client)).Invoke(context.Background(), &InvokeRequest{Name: "backup", Payload: "later"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Result != "backup: later" {
+		t.Errorf("result = %q, want %q", resp.Result, "backup: later")
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ LibraryHTTPService = (*Library)(nil)
+
+type Library struct{}
+
+func (l *Library) GetShelf(ctx context.Context, req *GetShelfRequest) (*Shelf, error) {
+	return &Shelf{Name: req.Name}, nil
+}
+
+func (l *Library) UpdateShelf(ctx context.Context, req *UpdateShelfRequest) (*Shelf, error) {
+	return &Shelf{Name: req.GetShelf().GetName(), Theme: req.GetShelf().GetTheme(), Etag: req.Etag}, nil
+}
+
+func (l *Library) DeleteShelf(ctx context.Context, req *DeleteShelfRequest) (*Shelf, error) {
+	if !req.Force {
+		return nil, status.Error(codes.FailedPrecondition, "shelf is not empty")
+	}
+	return &Shelf{Name: req.Name}, nil
+}
+
+func (l *Library) ArchiveShelf(ctx context.Context, req *ArchiveShelfRequest) (*Shelf, error) {
+	return &Shelf{Name: req.Name, Theme: req.Theme}, nil
+}
+
+func (l *Library) GetPublisher(ctx context.Context, req *GetPublisherRequest) (*Publisher, error) {
+	return &Publisher{Name: req.Name}, nil
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRegisterLibraryHTTPHandlers(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "shelf",
+			method:     http.MethodGet,
+			target:     "/v1/shelves/1",
+			wantStatus: http.StatusOK,
+			want:       `{"name":"shelves/1"}`,
+		},
+		{
+			name:       "publisher",
+			method:     http.MethodGet,
+			target:     "/v1/publishers/2",
+			wantStatus: http.StatusOK,
+			want:       `{"name":"publishers/2"}`,
+		},
+		{
+			name:       "unknown collection",
+			method:     http.MethodGet,
+			target:     "/v1/authors/3",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "too many segments",
+			method:     http.MethodGet,
+			target:     "/v1/shelves/1/books",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "body field",
+			method:     http.MethodPatch,
+			target:     "/v1/shelves/1?etag=abc",
+			body:       `{"theme":"history"}`,
+			wantStatus: http.StatusOK,
+			want:       `{"name":"shelves/1","theme":"history","etag":"abc"}`,
+		},
+		{
+			name:       "path parameter overrides body field",
+			method:     http.MethodPatch,
+			target:     "/v1/shelves/1",
+			body:       `{"name":"shelves/2","theme":"history"}`,
+			wantStatus: http.StatusOK,
+			want:       `{"name":"shelves/1","theme":"history"}`,
+		},
+		{
+			name:       "body field is not the whole request",
+			method:     http.MethodPatch,
+			target:     "/v1/shelves/1",
+			body:       `{"shelf":{"theme":"history"},"etag":"abc"}`,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "query parameter without body",
+			method:     http.MethodDelete,
+			target:     "/v1/shelves/1?force=true",
+			wantStatus: http.StatusOK,
+			want:       `{"name":"shelves/1"}`,
+		},
+		{
+			// The body used to be decoded into the request message regardless of body field.
+			name:       "body is not decoded without body field",
+			method:     http.MethodDelete,
+			target:     "/v1/shelves/1",
+			body:       `{"force":true}`,
+			wantStatus: http.StatusInternalServerError,
+			want:       `{"code":9,"message":"shelf is not empty"}`,
+		},
+	}
+
+	mux := http.NewServeMux()
+	RegisterLibraryHTTPHandlers(mux, NewLibraryHTTPConverter(&Library{}))
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.want == "" {
+				return
+			}
+			if diff := cmp.Diff(compactJSON(rec.Body.String()), tt.want); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestLibraryHTTPClient(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterLibraryHTTPHandlers(mux, NewLibraryHTTPConverter(&Library{}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, contentType := range []string{"application/json", "application/protobuf"} {
+		contentType := contentType
+		t.Run(contentType, func(t *testing.T) {
+			ctx := context.Background()
+			client := NewLibraryHTTPClient(srv.URL, WithLibraryHTTPClientContentType(contentType))
+
+			resp, err := client.GetShelf(ctx, &GetShelfRequest{Name: "shelves/1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(resp, &Shelf{Name: "shelves/1"}, cmpopts.IgnoreUnexported(Shelf{})); diff != "" {
+				t.Errorf("GetShelf: %s", diff)
+			}
+
+			resp, err = client.UpdateShelf(ctx, &UpdateShelfRequest{
+				Shelf: &Shelf{Name: "shelves/1", Theme: "history"},
+				Etag:  "abc",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(resp, &Shelf{Name: "shelves/1", Theme: "history", Etag: "abc"}, cmpopts.IgnoreUnexported(Shelf{})); diff != "" {
+				t.Errorf("UpdateShelf: %s", diff)
+			}
+
+			resp, err = client.DeleteShelf(ctx, &DeleteShelfRequest{Name: "shelves/1", Force: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(resp, &Shelf{Name: "shelves/1"}, cmpopts.IgnoreUnexported(Shelf{})); diff != "" {
+				t.Errorf("DeleteShelf: %s", diff)
+			}
+
+			if _, err := client.DeleteShelf(ctx, &DeleteShelfRequest{Name: "shelves/1"}); status.Code(err) != codes.FailedPrecondition {
+				t.Errorf("DeleteShelf: code = %v, want %v", status.Code(err), codes.FailedPrecondition)
+			}
+		})
+	}
+}
+
+func TestLibraryHTTPConverter_ArchiveShelfHTTPRule(t *testing.T) {
+	conv := NewLibraryHTTPConverter(&Library{})
+
+	// http.ServeMux cannot match the verb after the variable, so the handler is routed by the prefix.
+	method, pattern, handler := conv.ArchiveShelfHTTPRule(nil)
+	if method != http.MethodPost || pattern != "/v1/{name=shelves/*}:archive" {
+		t.Fatalf("ArchiveShelfHTTPRule() = %q, %q", method, pattern)
+	}
+	mux := http.NewServeMux()
+	RegisterLibraryHTTPHandlers(mux, conv)
+	mux.Handle("POST /v1/shelves/", handler)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := NewLibraryHTTPClient(srv.URL).ArchiveShelf(context.Background(), &ArchiveShelfRequest{Name: "shelves/1", Theme: "history"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(resp, &Shelf{Name: "shelves/1", Theme: "history"}, cmpopts.IgnoreUnexported(Shelf{})); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	rec := httptest.NewRecorder()
+	LibraryHTTPOpenAPIHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Description string `json:"description"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Paths["/v1/{name}:archive"]["post"].Description; !strings.Contains(got, "RegisterLibraryHTTPHandlers does not register") {
+		t.Errorf("description = %q", got)
+	}
+}
+
+func TestLibraryHTTPConverter_UpdateShelfHTTPRule_AdditionalBindings(t *testing.T) {
+	_, _, handler := NewLibraryHTTPConverter(&Library{}).UpdateShelfHTTPRule(nil)
+
+	tests := []struct {
+		method     string
+		wantStatus int
+		wantAllow  string
+	}{
+		{method: http.MethodPatch, wantStatus: http.StatusOK},
+		{method: http.MethodPut, wantStatus: http.StatusOK},
+		{method: http.MethodPost, wantStatus: http.StatusMethodNotAllowed, wantAllow: "PATCH, PUT"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.method, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/v1/shelves/1", strings.NewReader(`{"theme":"history"}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+		})
+	}
+
+	mux := http.NewServeMux()
+	RegisterLibraryHTTPHandlers(mux, NewLibraryHTTPConverter(&Library{}))
+	req := httptest.NewRequest(http.MethodPut, "/v1/shelves/1", strings.NewReader(`{"theme":"history"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if diff := cmp.Diff(compactJSON(rec.Body.String()), `{"name":"shelves/1","theme":"history"}`); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
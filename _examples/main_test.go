@@ -2,19 +2,28 @@ package main
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
@@ -397,3 +406,962 @@ func TestEchoGreeterServer_SayHello(t *testing.T) {
 		})
 	}
 }
+
+func TestNewGreeterHTTPConverter_Options(t *testing.T) {
+	var called []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			called = append(called, name)
+			return handler(ctx, arg)
+		}
+	}
+
+	var cbErr error
+	conv := NewGreeterHTTPConverter(&ErrorService{},
+		WithGreeterHTTPCallback(func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			cbErr = err
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+		WithGreeterHTTPInterceptors(interceptor("converter")),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	conv.SayHello(nil, interceptor("method")).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status code = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if cbErr == nil {
+		t.Errorf("converter callback was not called with error")
+	}
+	if diff := cmp.Diff(called, []string{"converter", "method"}); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestRegisterGreeterHTTPHandlers(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterGreeterHTTPHandlers(mux, NewGreeterHTTPConverter(&EchoGreeterServer{}))
+
+	req := httptest.NewRequest(http.MethodPost, "/main.Greeter/SayHello", bytes.NewBufferString(`{"name": "John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	resp := &HelloReply{}
+	if err := protojson.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Message != "Hello, John!" {
+		t.Errorf("message = %q, want %q", resp.Message, "Hello, John!")
+	}
+}
+
+func TestGreeterHTTPClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		srv         GreeterHTTPService
+		contentType string
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{
+			name:        "JSON",
+			srv:         &EchoGreeterServer{},
+			contentType: "application/json",
+			wantCode:    codes.OK,
+			wantMessage: "Hello, John!",
+		},
+		{
+			name:        "Protobuf",
+			srv:         &EchoGreeterServer{},
+			contentType: "application/protobuf",
+			wantCode:    codes.OK,
+			wantMessage: "Hello, John!",
+		},
+		{
+			name:        "error JSON",
+			srv:         &ErrorService{},
+			contentType: "application/json",
+			wantCode:    codes.Unknown,
+			wantMessage: "ERROR",
+		},
+		{
+			name:        "error Protobuf",
+			srv:         &ErrorService{},
+			contentType: "application/protobuf",
+			wantCode:    codes.Unknown,
+			wantMessage: "ERROR",
+		},
+		{
+			name:        "not registered",
+			contentType: "application/json",
+			wantCode:    codes.Unimplemented,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			if tt.srv != nil {
+				RegisterGreeterHTTPHandlers(mux, NewGreeterHTTPConverter(tt.srv))
+			}
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			client := NewGreeterHTTPClient(srv.URL, WithGreeterHTTPClientContentType(tt.contentType))
+			resp, err := client.SayHello(context.Background(), &HelloRequest{Name: "John"})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v: %v", code, tt.wantCode, err)
+			}
+			if err != nil {
+				if tt.wantMessage != "" && status.Convert(err).Message() != tt.wantMessage {
+					t.Errorf("message = %q, want %q", status.Convert(err).Message(), tt.wantMessage)
+				}
+				return
+			}
+			if resp.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", resp.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestGreeterHTTPClient_Interceptors(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterGreeterHTTPHandlers(mux, NewGreeterHTTPConverter(&EchoGreeterServer{}))
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		mux.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	var calls []string
+	interceptor := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			calls = append(calls, name+" "+method)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+	rename := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer token")
+		return invoker(ctx, method, &HelloRequest{Name: "Jane"}, reply, cc, opts...)
+	}
+
+	client := NewGreeterHTTPClient(srv.URL,
+		WithGreeterHTTPClientInterceptors(interceptor("first"), interceptor("second")),
+		WithGreeterHTTPClientInterceptors(rename),
+	)
+	resp, err := client.SayHello(context.Background(), &HelloRequest{Name: "John"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Message != "Hello, Jane!" {
+		t.Errorf("message = %q, want %q", resp.Message, "Hello, Jane!")
+	}
+	if authorization != "Bearer token" {
+		t.Errorf("Authorization = %q, want %q", authorization, "Bearer token")
+	}
+	if diff := cmp.Diff(calls, []string{
+		"first /main.Greeter/SayHello",
+		"second /main.Greeter/SayHello",
+	}); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	retry := 0
+	client = NewGreeterHTTPClient(srv.URL+"/unknown", WithGreeterHTTPClientInterceptors(
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			var err error
+			for i := 0; i < 3; i++ {
+				retry++
+				if err = invoker(ctx, method, req, reply, cc, opts...); status.Code(err) != codes.Unimplemented {
+					return err
+				}
+			}
+			return err
+		},
+	))
+	if _, err := client.SayHello(context.Background(), &HelloRequest{Name: "John"}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("code = %v, want %v", status.Code(err), codes.Unimplemented)
+	}
+	if retry != 3 {
+		t.Errorf("retry = %d, want 3", retry)
+	}
+}
+
+func TestGreeterHTTPClient_Metadata(t *testing.T) {
+	var got metadata.MD
+	interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		got, _ = metadata.FromIncomingContext(ctx)
+		return handler(ctx, arg)
+	}
+	mux := http.NewServeMux()
+	RegisterGreeterHTTPHandlers(mux, NewGreeterHTTPConverter(&EchoGreeterServer{}, WithGreeterHTTPInterceptors(interceptor)))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"authorization", "Bearer token",
+		"x-request-id", "abc",
+		"trace-bin", "\x00\x01",
+	)
+	if _, err := NewGreeterHTTPClient(srv.URL).SayHello(ctx, &HelloRequest{Name: "John"}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, metadata.MD{
+		"authorization": {"Bearer token"},
+		"x-request-id":  {"abc"},
+		"trace-bin":     {"\x00\x01"},
+	}); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestGreeterHTTPClient_ClientConn(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterGreeterHTTPHandlers(mux, NewGreeterHTTPConverter(&EchoGreeterServer{}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := NewGreeterClient(NewGreeterHTTPClient(srv.URL))
+	resp, err := client.SayHello(context.Background(), &HelloRequest{Name: "John"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Message != "Hello, John!" {
+		t.Errorf("message = %q, want %q", resp.Message, "Hello, John!")
+	}
+
+	conn := NewGreeterHTTPClient(srv.URL)
+	if err := conn.Invoke(context.Background(), "/main.Greeter/Unknown", &HelloRequest{}, &HelloReply{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("code = %v, want %v", status.Code(err), codes.Unimplemented)
+	}
+	if err := conn.Invoke(context.Background(), "/main.Greeter/SayHello", &HelloReply{}, &HelloReply{}); status.Code(err) != codes.Internal {
+		t.Errorf("code = %v, want %v", status.Code(err), codes.Internal)
+	}
+}
+
+func TestGreeterHTTPClient_CallOptions(t *testing.T) {
+	interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := grpc.SetHeader(ctx, metadata.Pairs("x-request-id", "abc", "trace-bin", "\x01\x02\x03", "location", "/hello")); err != nil {
+			return nil, err
+		}
+		if err := grpc.SetTrailer(ctx, metadata.Pairs("x-elapsed", "1ms")); err != nil {
+			return nil, err
+		}
+		return handler(ctx, arg)
+	}
+	mux := http.NewServeMux()
+	RegisterGreeterHTTPHandlers(mux, NewGreeterHTTPConverter(&EchoGreeterServer{}, WithGreeterHTTPInterceptors(interceptor)))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		conn        grpc.ClientConnInterface
+		wantHeader  metadata.MD
+		wantTrailer metadata.MD
+	}{
+		{
+			name: "client",
+			conn: NewGreeterHTTPClient(srv.URL),
+			wantHeader: metadata.MD{
+				"x-request-id": {"abc"},
+				"trace-bin":    {"\x01\x02\x03"},
+			},
+			wantTrailer: metadata.MD{"x-elapsed": {"1ms"}},
+		},
+		{
+			name: "conn with headers",
+			conn: NewGreeterHTTPClientConn(NewGreeterHTTPClient(srv.URL, WithGreeterHTTPClientHeaders("Location"))),
+			wantHeader: metadata.MD{
+				"x-request-id": {"abc"},
+				"trace-bin":    {"\x01\x02\x03"},
+				"location":     {"/hello"},
+			},
+			wantTrailer: metadata.MD{"x-elapsed": {"1ms"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var header, trailer metadata.MD
+			if _, err := NewGreeterClient(tt.conn).SayHello(context.Background(), &HelloRequest{Name: "John"}, grpc.Header(&header), grpc.Trailer(&trailer)); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(header, tt.wantHeader); diff != "" {
+				t.Errorf("header: %s", diff)
+			}
+			if diff := cmp.Diff(trailer, tt.wantTrailer); diff != "" {
+				t.Errorf("trailer: %s", diff)
+			}
+		})
+	}
+}
+
+func TestNewGreeterHTTPConverter_MaxBodySize(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []GreeterHTTPConverterOption
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "default limit",
+			body:       `{"name": "John"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "within limit",
+			opts:       []GreeterHTTPConverterOption{WithGreeterHTTPMaxBodySize(16)},
+			body:       `{"name": "John"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "too large",
+			opts:       []GreeterHTTPConverterOption{WithGreeterHTTPMaxBodySize(15)},
+			body:       `{"name": "John"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "no limit",
+			opts:       []GreeterHTTPConverterOption{WithGreeterHTTPMaxBodySize(0)},
+			body:       fmt.Sprintf(`{"name": "%s"}`, bytes.Repeat([]byte("a"), 5<<20)),
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			NewGreeterHTTPConverter(&EchoGreeterServer{}, tt.opts...).SayHello(nil).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNewGreeterHTTPConverter_IncomingMetadata(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []GreeterHTTPConverterOption
+		header http.Header
+		want   metadata.MD
+	}{
+		{
+			name: "default",
+			header: http.Header{
+				"Authorization":              {"Bearer token"},
+				"Grpc-Metadata-X-Request-Id": {"abc", "def"},
+				"Grpc-Metadata-Trace-Bin":    {"AQID"},
+				"X-Request-Id":               {"ignored"},
+			},
+			want: metadata.MD{
+				"authorization": {"Bearer token"},
+				"x-request-id":  {"abc", "def"},
+				"trace-bin":     {"\x01\x02\x03"},
+			},
+		},
+		{
+			name: "allow-list and prefix",
+			opts: []GreeterHTTPConverterOption{
+				WithGreeterHTTPIncomingHeaders("X-Request-Id", "User-Agent"),
+				WithGreeterHTTPIncomingHeaderPrefix("X-Md-"),
+			},
+			header: http.Header{
+				"Authorization":        {"ignored"},
+				"Grpc-Metadata-Tenant": {"ignored"},
+				"X-Request-Id":         {"abc"},
+				"User-Agent":           {"test"},
+				"X-Md-Tenant":          {"example"},
+			},
+			want: metadata.MD{
+				"x-request-id": {"abc"},
+				"user-agent":   {"test"},
+				"tenant":       {"example"},
+			},
+		},
+		{
+			name: "disabled",
+			opts: []GreeterHTTPConverterOption{
+				WithGreeterHTTPIncomingHeaders(),
+				WithGreeterHTTPIncomingHeaderPrefix(""),
+			},
+			header: http.Header{
+				"Authorization":        {"Bearer token"},
+				"Grpc-Metadata-Tenant": {"example"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var got metadata.MD
+			interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				got, _ = metadata.FromIncomingContext(ctx)
+				return handler(ctx, arg)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+			req.Header = tt.header
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			NewGreeterHTTPConverter(&EchoGreeterServer{}, tt.opts...).SayHello(nil, interceptor).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status code = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if diff := cmp.Diff(got, tt.want, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewGreeterHTTPConverter_OutgoingMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []GreeterHTTPConverterOption
+		want http.Header
+	}{
+		{
+			name: "default",
+			want: http.Header{
+				"Grpc-Metadata-X-Request-Id": {"abc"},
+				"Grpc-Metadata-Trace-Bin":    {"AQID"},
+				"Grpc-Trailer-X-Elapsed":     {"1ms"},
+			},
+		},
+		{
+			name: "prefix",
+			opts: []GreeterHTTPConverterOption{
+				WithGreeterHTTPOutgoingHeaderPrefix("X-Md-"),
+				WithGreeterHTTPOutgoingTrailerPrefix("X-Trailer-"),
+			},
+			want: http.Header{
+				"X-Md-X-Request-Id":   {"abc"},
+				"X-Md-Trace-Bin":      {"AQID"},
+				"X-Trailer-X-Elapsed": {"1ms"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if err := grpc.SetHeader(ctx, metadata.Pairs("x-request-id", "abc", "trace-bin", "\x01\x02\x03")); err != nil {
+					return nil, err
+				}
+				if err := grpc.SetTrailer(ctx, metadata.Pairs("x-elapsed", "1ms")); err != nil {
+					return nil, err
+				}
+				return handler(ctx, arg)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			NewGreeterHTTPConverter(&EchoGreeterServer{}, tt.opts...).SayHello(nil, interceptor).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status code = %d, want %d", rec.Code, http.StatusOK)
+			}
+			got := rec.Header().Clone()
+			got.Del("Content-Type")
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewGreeterHTTPConverter_StatusCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		wantCode int
+		wantBody bool
+		wantLoc  string
+	}{
+		{
+			name:     "default",
+			wantCode: http.StatusOK,
+			wantBody: true,
+		},
+		{
+			name:     "created",
+			md:       metadata.Pairs("x-http-code", "201", "location", "/greetings/1"),
+			wantCode: http.StatusCreated,
+			wantBody: true,
+			wantLoc:  "/greetings/1",
+		},
+		{
+			name:     "no content",
+			md:       metadata.Pairs("x-http-code", "204"),
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "invalid",
+			md:       metadata.Pairs("x-http-code", "abc"),
+			wantCode: http.StatusOK,
+			wantBody: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if err := grpc.SetHeader(ctx, tt.md); err != nil {
+					return nil, err
+				}
+				return handler(ctx, arg)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			NewGreeterHTTPConverter(&EchoGreeterServer{}).SayHello(nil, interceptor).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := rec.Body.Len() != 0; got != tt.wantBody {
+				t.Errorf("body written = %v, want %v", got, tt.wantBody)
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLoc {
+				t.Errorf("Location = %q, want %q", got, tt.wantLoc)
+			}
+			if got := rec.Header().Get("Grpc-Metadata-X-Http-Code"); got != "" {
+				t.Errorf("Grpc-Metadata-X-Http-Code = %q, want empty", got)
+			}
+		})
+	}
+}
+
+func TestNewGreeterHTTPConverter_Timeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []GreeterHTTPConverterOption
+		header http.Header
+		want   time.Duration
+	}{
+		{
+			name:   "grpc-timeout",
+			header: http.Header{"Grpc-Timeout": {"2S"}},
+			want:   2 * time.Second,
+		},
+		{
+			name:   "invalid",
+			header: http.Header{"Grpc-Timeout": {"2s"}},
+		},
+		{
+			name:   "timeout header is disabled by default",
+			header: http.Header{"X-Request-Timeout": {"2s"}},
+		},
+		{
+			name:   "shorter timeout",
+			opts:   []GreeterHTTPConverterOption{WithGreeterHTTPTimeoutHeader("X-Request-Timeout")},
+			header: http.Header{"Grpc-Timeout": {"1H"}, "X-Request-Timeout": {"2s"}},
+			want:   2 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var got time.Duration
+			interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if deadline, ok := ctx.Deadline(); ok {
+					got = time.Until(deadline)
+				}
+				return handler(ctx, arg)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+			req.Header = tt.header
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			NewGreeterHTTPConverter(&EchoGreeterServer{}, tt.opts...).SayHello(nil, interceptor).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status code = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if got > tt.want || got < tt.want-time.Second {
+				t.Errorf("timeout = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGreeterHTTPConverter_DeadlineExceeded(t *testing.T) {
+	interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Grpc-Timeout", "1m")
+	rec := httptest.NewRecorder()
+	NewGreeterHTTPConverter(&EchoGreeterServer{}).SayHello(nil, interceptor).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status code = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+}
+
+func TestNewGreeterHTTPConverter_Peer(t *testing.T) {
+	tests := []struct {
+		name     string
+		tls      bool
+		wantAddr string
+		wantAuth string
+	}{
+		{
+			name:     "http",
+			wantAddr: "192.0.2.1:1234",
+		},
+		{
+			name:     "https",
+			tls:      true,
+			wantAddr: "192.0.2.1:1234",
+			wantAuth: "tls",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var p *peer.Peer
+			interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				p, _ = peer.FromContext(ctx)
+				return handler(ctx, arg)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			rec := httptest.NewRecorder()
+			NewGreeterHTTPConverter(&EchoGreeterServer{}).SayHello(nil, interceptor).ServeHTTP(rec, req)
+
+			if p == nil {
+				t.Fatal("peer is not set")
+			}
+			if got := p.Addr.String(); got != tt.wantAddr {
+				t.Errorf("Addr = %q, want %q", got, tt.wantAddr)
+			}
+			var gotAuth string
+			if p.AuthInfo != nil {
+				gotAuth = p.AuthInfo.AuthType()
+			}
+			if gotAuth != tt.wantAuth {
+				t.Errorf("AuthType = %q, want %q", gotAuth, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestExpvarObserver(t *testing.T) {
+	observer := &ExpvarObserver{}
+	conv := NewGreeterHTTPConverter(&EchoGreeterServer{}, WithGreeterHTTPObserver(observer))
+
+	for _, body := range []string{`{"name": "John"}`, `{"name":`} {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		conv.SayHello(nil).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	rec := httptest.NewRecorder()
+	observer.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	want := []string{
+		"# TYPE http_server_requests_total counter",
+		`http_server_requests_total{method="/main.Greeter/SayHello",route="",status="200",code="OK"} 1`,
+		`http_server_requests_total{method="/main.Greeter/SayHello",route="",status="500",code="Unknown"} 1`,
+		`http_server_request_size_bytes_sum{method="/main.Greeter/SayHello",route="",status="200",code="OK"} 16`,
+		`http_server_response_size_bytes_sum{method="/main.Greeter/SayHello",route="",status="200",code="OK"} 26`,
+	}
+	for _, line := range want {
+		if !strings.Contains(rec.Body.String(), line+"\n") {
+			t.Errorf("metrics do not contain %q:\n%s", line, rec.Body.String())
+		}
+	}
+}
+
+func TestNewGreeterHTTPConverter_Recovery(t *testing.T) {
+	var reported []byte
+	conv := NewGreeterHTTPConverter(&EchoGreeterServer{}, WithGreeterHTTPRecovery(func(ctx context.Context, fullMethod string, p interface{}, stack []byte) {
+		if fullMethod != "/main.Greeter/SayHello" {
+			t.Errorf("fullMethod = %q", fullMethod)
+		}
+		reported = stack
+	}))
+	interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		panic("boom")
+	}
+
+	var cbErr error
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	conv.SayHello(func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+		cbErr = err
+	}, interceptor).ServeHTTP(httptest.NewRecorder(), req)
+
+	var panicErr *GreeterHTTPPanicError
+	if !errors.As(cbErr, &panicErr) {
+		t.Fatalf("callback error = %v, want *GreeterHTTPPanicError", cbErr)
+	}
+	if panicErr.Value != "boom" {
+		t.Errorf("Value = %v, want %v", panicErr.Value, "boom")
+	}
+	if code := status.Code(cbErr); code != codes.Internal {
+		t.Errorf("code = %v, want %v", code, codes.Internal)
+	}
+	if !bytes.Contains(panicErr.Stack, []byte("TestNewGreeterHTTPConverter_Recovery")) {
+		t.Errorf("stack does not contain the panicked function:\n%s", panicErr.Stack)
+	}
+	if !bytes.Equal(reported, panicErr.Stack) {
+		t.Errorf("reported stack differs from the error")
+	}
+}
+
+func TestNewGreeterHTTPConverter_DefaultCallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		panic      bool
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "status error",
+			err:        status.Error(codes.NotFound, "not found"),
+			wantStatus: http.StatusInternalServerError,
+			want:       `{"code":5,"message":"not found"}`,
+		},
+		{
+			name:       "other error",
+			err:        errors.New("failed"),
+			wantStatus: http.StatusInternalServerError,
+			want:       `{"code":2,"message":"failed"}`,
+		},
+		{
+			name:       "deadline exceeded",
+			err:        context.DeadlineExceeded,
+			wantStatus: http.StatusGatewayTimeout,
+			want:       `{"code":4,"message":"context deadline exceeded"}`,
+		},
+		{
+			name:       "panic",
+			panic:      true,
+			wantStatus: http.StatusInternalServerError,
+			want:       `{"code":13,"message":"internal error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if tt.panic {
+					panic("boom")
+				}
+				return nil, tt.err
+			}
+			conv := NewGreeterHTTPConverter(&EchoGreeterServer{}, WithGreeterHTTPRecovery(nil))
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			conv.SayHello(nil, interceptor).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if diff := cmp.Diff(compactJSON(rec.Body.String()), tt.want); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewGreeterHTTPConverter_Compression(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []GreeterHTTPConverterOption
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{
+			name:           "disabled by default",
+			acceptEncoding: "gzip",
+		},
+		{
+			name:           "gzip",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(0)},
+			acceptEncoding: "deflate, gzip",
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "deflate",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(0)},
+			acceptEncoding: "gzip;q=0, deflate",
+			wantEncoding:   "deflate",
+		},
+		{
+			name:           "not accepted",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(0)},
+			acceptEncoding: "br",
+		},
+		{
+			name:           "smaller than threshold",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(1024)},
+			acceptEncoding: "gzip",
+		},
+		{
+			name:           "wildcard",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(0)},
+			acceptEncoding: "*",
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "wildcard after gzip excluded",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(0)},
+			acceptEncoding: "gzip;q=0, *",
+			wantEncoding:   "deflate",
+		},
+		{
+			name:           "x-gzip excluded",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(0)},
+			acceptEncoding: "x-gzip;q=0, deflate;q=0, *;q=0.5",
+		},
+		{
+			name:           "wildcard excluded",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(0)},
+			acceptEncoding: "*;q=0, identity",
+		},
+		{
+			name:           "identity excluded",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(1024)},
+			acceptEncoding: "deflate, identity;q=0",
+			wantEncoding:   "deflate",
+		},
+		{
+			name:           "identity excluded by wildcard",
+			opts:           []GreeterHTTPConverterOption{WithGreeterHTTPCompression(1024)},
+			acceptEncoding: "gzip, *;q=0",
+			wantEncoding:   "gzip",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name": "John"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			rec := httptest.NewRecorder()
+			NewGreeterHTTPConverter(&EchoGreeterServer{}, tt.opts...).SayHello(nil).ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			var body io.Reader = rec.Body
+			switch tt.wantEncoding {
+			case "gzip":
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = zr
+			case "deflate":
+				zr, err := zlib.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = zr
+			}
+			buf, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := &HelloReply{}
+			if err := protojson.Unmarshal(buf, got); err != nil {
+				t.Fatal(err)
+			}
+			if got.GetMessage() != "Hello, John!" {
+				t.Errorf("message = %q", got.GetMessage())
+			}
+		})
+	}
+}
+
+func TestNewGreeterHTTPConverter_ContentEncoding(t *testing.T) {
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	if _, err := zw.Write([]byte(`{"name": "John"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		wantCode int
+	}{
+		{
+			name:     "gzip",
+			encoding: "gzip",
+			body:     gzipped.Bytes(),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "unsupported",
+			encoding: "br",
+			body:     gzipped.Bytes(),
+			wantCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:     "corrupt gzip header",
+			encoding: "gzip",
+			body:     []byte(`{"name": "John"}`),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "corrupt deflate header",
+			encoding: "deflate",
+			body:     []byte(`{"name": "John"}`),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "truncated gzip",
+			encoding: "gzip",
+			body:     gzipped.Bytes()[:gzipped.Len()-4],
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Content-Encoding", tt.encoding)
+			rec := httptest.NewRecorder()
+			NewGreeterHTTPConverter(&EchoGreeterServer{}).SayHello(nil).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ExpvarObserver is a reference implementation of the observers of the converters, e.g. GreeterHTTPObserver.
+// It counts requests and sums the latency and the sizes in expvar.Map keyed by the labels of the request,
+// which are exported as counters in Prometheus text format by ServeHTTP.
+type ExpvarObserver struct {
+	Requests      expvar.Map // number of requests
+	Seconds       expvar.Map // sum of the latency in seconds
+	RequestBytes  expvar.Map // sum of the request body sizes
+	ResponseBytes expvar.Map // sum of the response body sizes
+}
+
+// Observe implements the observers of the converters.
+func (o *ExpvarObserver) Observe(ctx context.Context, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, requestSize, responseSize int64, elapsed time.Duration) {
+	key := fmt.Sprintf(`method="/%s/%s",route=%s,status="%d",code="%s"`,
+		method.Parent().FullName(), method.Name(), strconv.Quote(route), statusCode, code)
+	o.Requests.Add(key, 1)
+	o.Seconds.AddFloat(key, elapsed.Seconds())
+	o.RequestBytes.Add(key, requestSize)
+	o.ResponseBytes.Add(key, responseSize)
+}
+
+// Publish publishes the metrics as expvar variables named with the prefix, e.g. "http_server_requests_total".
+func (o *ExpvarObserver) Publish(prefix string) {
+	expvar.Publish(prefix+"_requests_total", &o.Requests)
+	expvar.Publish(prefix+"_request_duration_seconds_sum", &o.Seconds)
+	expvar.Publish(prefix+"_request_size_bytes_sum", &o.RequestBytes)
+	expvar.Publish(prefix+"_response_size_bytes_sum", &o.ResponseBytes)
+}
+
+// ServeHTTP writes the metrics in Prometheus text format with the metric names prefixed by "http_server".
+func (o *ExpvarObserver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	var b strings.Builder
+	for _, m := range []struct {
+		name string
+		vars *expvar.Map
+	}{
+		{"http_server_requests_total", &o.Requests},
+		{"http_server_request_duration_seconds_sum", &o.Seconds},
+		{"http_server_request_size_bytes_sum", &o.RequestBytes},
+		{"http_server_response_size_bytes_sum", &o.ResponseBytes},
+	} {
+		fmt.Fprintf(&b, "# TYPE %s counter\n", m.name)
+		m.vars.Do(func(kv expvar.KeyValue) {
+			fmt.Fprintf(&b, "%s{%s} %s\n", m.name, kv.Key, kv.Value)
+		})
+	}
+	fmt.Fprint(w, b.String())
+}
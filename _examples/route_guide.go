@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ RouteGuideHTTPService = (*RouteGuide)(nil)
+
+type RouteGuide struct{}
+
+func (r *RouteGuide) ListFeatures(rect *Rectangle, stream RouteGuide_ListFeaturesServer) error {
+	for lat := rect.GetLo().GetLatitude(); lat <= rect.GetHi().GetLatitude(); lat++ {
+		feature := &Feature{
+			Name: fmt.Sprintf("feature-%d", lat),
+			Location: &Point{
+				Latitude:  lat,
+				Longitude: rect.GetLo().GetLongitude(),
+			},
+		}
+		if err := stream.Send(feature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RouteGuide) RecordRoute(stream RouteGuide_RecordRouteServer) error {
+	summary := &RouteSummary{}
+	var prev *Point
+	for {
+		point, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(summary)
+		}
+		if err != nil {
+			return err
+		}
+		summary.PointCount++
+		if prev != nil {
+			summary.Distance += abs(point.GetLatitude()-prev.GetLatitude()) + abs(point.GetLongitude()-prev.GetLongitude())
+		}
+		prev = point
+	}
+}
+
+func (r *RouteGuide) RouteChat(stream RouteGuide_RouteChatServer) error {
+	for {
+		note, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if note.GetLocation() == nil {
+			return status.Error(codes.InvalidArgument, "location is required")
+		}
+		if err := stream.Send(note); err != nil {
+			return err
+		}
+	}
+}
+
+func abs(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,909 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestRouteGuide_ListFeatures(t *testing.T) {
+	tests := []struct {
+		name            string
+		reqFunc         func() (*http.Request, error)
+		wantContentType string
+		want            []*Feature
+	}{
+		{
+			name: "NDJSON",
+			reqFunc: func() (*http.Request, error) {
+				req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBufferString(`{"lo": {"latitude": 1, "longitude": 10}, "hi": {"latitude": 2}}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Accept", "application/x-ndjson")
+				return req, nil
+			},
+			wantContentType: "application/x-ndjson",
+			want: []*Feature{
+				{Name: "feature-1", Location: &Point{Latitude: 1, Longitude: 10}},
+				{Name: "feature-2", Location: &Point{Latitude: 2, Longitude: 10}},
+			},
+		},
+		{
+			name: "length-prefixed protobuf",
+			reqFunc: func() (*http.Request, error) {
+				buf, err := proto.Marshal(&Rectangle{
+					Lo: &Point{Latitude: 3, Longitude: 20},
+					Hi: &Point{Latitude: 5},
+				})
+				if err != nil {
+					return nil, err
+				}
+				req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBuffer(buf))
+				req.Header.Set("Content-Type", "application/protobuf")
+				return req, nil
+			},
+			wantContentType: "application/protobuf",
+			want: []*Feature{
+				{Name: "feature-3", Location: &Point{Latitude: 3, Longitude: 20}},
+				{Name: "feature-4", Location: &Point{Latitude: 4, Longitude: 20}},
+				{Name: "feature-5", Location: &Point{Latitude: 5, Longitude: 20}},
+			},
+		},
+	}
+
+	opts := cmpopts.IgnoreUnexported(Feature{}, Point{})
+
+	conv := NewRouteGuideHTTPConverter(&RouteGuide{})
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.reqFunc()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			conv.ListFeatures(nil).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status code = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			contentType := rec.Header().Get("Content-Type")
+			if contentType != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", contentType, tt.wantContentType)
+			}
+			if !rec.Flushed {
+				t.Errorf("response is not flushed")
+			}
+
+			var got []*Feature
+			switch contentType {
+			case "application/protobuf":
+				buf := rec.Body.Bytes()
+				for len(buf) > 0 {
+					size, n := protowire.ConsumeVarint(buf)
+					if n < 0 {
+						t.Fatal(protowire.ParseError(n))
+					}
+					f := &Feature{}
+					if err := proto.Unmarshal(buf[n:n+int(size)], f); err != nil {
+						t.Fatal(err)
+					}
+					got = append(got, f)
+					buf = buf[n+int(size):]
+				}
+			default:
+				r := bufio.NewReader(rec.Body)
+				for {
+					line, err := r.ReadBytes('\n')
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					f := &Feature{}
+					if err := protojson.Unmarshal(line, f); err != nil {
+						t.Fatal(err)
+					}
+					got = append(got, f)
+				}
+			}
+
+			if diff := cmp.Diff(got, tt.want, opts); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestRouteGuide_ListFeatures_ServerSentEvents(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBufferString(`{"lo": {"latitude": 1}, "hi": {"latitude": 2}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	rec := httptest.NewRecorder()
+	NewRouteGuideHTTPConverter(&RouteGuide{}).ListFeatures(nil).ServeHTTP(rec, req)
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want %q", contentType, "text/event-stream")
+	}
+
+	var events []string
+	for _, event := range strings.Split(strings.TrimSuffix(rec.Body.String(), "\n\n"), "\n\n") {
+		events = append(events, strings.Replace(event, " ", "", -1))
+	}
+	want := []string{
+		`data:{"name":"feature-1","location":{"latitude":1}}`,
+		`data:{"name":"feature-2","location":{"latitude":2}}`,
+		"event:status\ndata:{}",
+	}
+	if diff := cmp.Diff(events, want); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+type endlessRouteGuide struct {
+	RouteGuide
+	ctxErr error
+}
+
+func (r *endlessRouteGuide) ListFeatures(rect *Rectangle, stream RouteGuide_ListFeaturesServer) error {
+	for {
+		if err := stream.Send(&Feature{}); err != nil {
+			r.ctxErr = stream.Context().Err()
+			return err
+		}
+	}
+}
+
+type brokenResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w *brokenResponseWriter) Write(b []byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func TestRouteGuide_ListFeatures_ClientDisconnect(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	srv := &endlessRouteGuide{}
+	var cbErr error
+	NewRouteGuideHTTPConverter(srv).ListFeatures(func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+		cbErr = err
+	}).ServeHTTP(&brokenResponseWriter{httptest.NewRecorder()}, req)
+
+	if srv.ctxErr != context.Canceled {
+		t.Errorf("context error = %v, want %v", srv.ctxErr, context.Canceled)
+	}
+	if cbErr == nil {
+		t.Errorf("callback was not called with error")
+	}
+}
+
+type failingRouteGuide struct {
+	RouteGuide
+}
+
+func (r *failingRouteGuide) ListFeatures(rect *Rectangle, stream RouteGuide_ListFeaturesServer) error {
+	if err := stream.Send(&Feature{Name: "feature-1"}); err != nil {
+		return err
+	}
+	return status.Error(codes.Unavailable, "feature store is down")
+}
+
+func TestRouteGuide_ListFeatures_ErrorAfterMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		wantBody func(t *testing.T, body []byte)
+	}{
+		{
+			name:   "newline-delimited JSON",
+			accept: "application/json",
+			wantBody: func(t *testing.T, body []byte) {
+				lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+				if len(lines) != 2 {
+					t.Fatalf("got %d lines, want 2: %q", len(lines), body)
+				}
+				feature := &Feature{}
+				if err := protojson.Unmarshal([]byte(lines[0]), feature); err != nil {
+					t.Fatal(err)
+				}
+				if feature.GetName() != "feature-1" {
+					t.Errorf("name = %q, want %q", feature.GetName(), "feature-1")
+				}
+				var last struct {
+					Error json.RawMessage `json:"error"`
+				}
+				if err := json.Unmarshal([]byte(lines[1]), &last); err != nil {
+					t.Fatal(err)
+				}
+				st := &spb.Status{}
+				if err := protojson.Unmarshal(last.Error, st); err != nil {
+					t.Fatal(err)
+				}
+				want := status.New(codes.Unavailable, "feature store is down").Proto()
+				if diff := cmp.Diff(st, want, protocmp.Transform()); diff != "" {
+					t.Errorf("%s", diff)
+				}
+			},
+		},
+		{
+			name:   "Server-Sent Events",
+			accept: "text/event-stream",
+			wantBody: func(t *testing.T, body []byte) {
+				var events []string
+				for _, event := range strings.Split(strings.TrimSuffix(string(body), "\n\n"), "\n\n") {
+					events = append(events, strings.Replace(event, " ", "", -1))
+				}
+				want := []string{
+					`data:{"name":"feature-1"}`,
+					"event:status\n" + `data:{"code":14,"message":"featurestoreisdown"}`,
+				}
+				if diff := cmp.Diff(events, want); diff != "" {
+					t.Errorf("%s", diff)
+				}
+			},
+		},
+		{
+			name:   "protobuf",
+			accept: "application/protobuf",
+			wantBody: func(t *testing.T, body []byte) {
+				b, n := protowire.ConsumeBytes(body)
+				if n < 0 || n != len(body) {
+					t.Fatalf("body is not a single message: %q", body)
+				}
+				feature := &Feature{}
+				if err := proto.Unmarshal(b, feature); err != nil {
+					t.Fatal(err)
+				}
+				if feature.GetName() != "feature-1" {
+					t.Errorf("name = %q, want %q", feature.GetName(), "feature-1")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBufferString(`{}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			NewRouteGuideHTTPConverter(&failingRouteGuide{}).ListFeatures(nil).ServeHTTP(rec, req)
+
+			res := rec.Result()
+			if res.StatusCode != http.StatusOK {
+				t.Errorf("status code = %d, want %d", res.StatusCode, http.StatusOK)
+			}
+			tt.wantBody(t, rec.Body.Bytes())
+			if got := res.Trailer.Get("Grpc-Status"); got != strconv.Itoa(int(codes.Unavailable)) {
+				t.Errorf("Grpc-Status = %q, want %q", got, strconv.Itoa(int(codes.Unavailable)))
+			}
+			if got := res.Trailer.Get("Grpc-Message"); got != "feature%20store%20is%20down" {
+				t.Errorf("Grpc-Message = %q, want %q", got, "feature%20store%20is%20down")
+			}
+		})
+	}
+}
+
+func TestRouteGuide_RecordRoute(t *testing.T) {
+	points := []*Point{
+		{Latitude: 1, Longitude: 1},
+		{Latitude: 2, Longitude: 3},
+		{Latitude: 0, Longitude: 3},
+	}
+	tests := []struct {
+		name    string
+		reqFunc func() (*http.Request, error)
+		want    *RouteSummary
+	}{
+		{
+			name: "NDJSON",
+			reqFunc: func() (*http.Request, error) {
+				var body bytes.Buffer
+				for _, p := range points {
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return nil, err
+					}
+					body.Write(append(buf, '\n'))
+				}
+				req := httptest.NewRequest(http.MethodPost, "/routeguide", &body)
+				req.Header.Set("Content-Type", "application/x-ndjson")
+				return req, nil
+			},
+			want: &RouteSummary{PointCount: 3, Distance: 5},
+		},
+		{
+			name: "length-prefixed protobuf",
+			reqFunc: func() (*http.Request, error) {
+				var body []byte
+				for _, p := range points {
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return nil, err
+					}
+					body = append(protowire.AppendVarint(body, uint64(len(buf))), buf...)
+				}
+				req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBuffer(body))
+				req.Header.Set("Content-Type", "application/protobuf")
+				return req, nil
+			},
+			want: &RouteSummary{PointCount: 3, Distance: 5},
+		},
+		{
+			name: "empty body",
+			reqFunc: func() (*http.Request, error) {
+				req := httptest.NewRequest(http.MethodPost, "/routeguide", nil)
+				req.Header.Set("Content-Type", "application/json")
+				return req, nil
+			},
+			want: &RouteSummary{},
+		},
+	}
+
+	opts := cmpopts.IgnoreUnexported(RouteSummary{})
+
+	conv := NewRouteGuideHTTPConverter(&RouteGuide{})
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.reqFunc()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			conv.RecordRoute(nil).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status code = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+
+			got := &RouteSummary{}
+			switch rec.Header().Get("Content-Type") {
+			case "application/protobuf":
+				if err := proto.Unmarshal(rec.Body.Bytes(), got); err != nil {
+					t.Fatal(err)
+				}
+			case "application/json":
+				if err := protojson.Unmarshal(rec.Body.Bytes(), got); err != nil {
+					t.Fatal(err)
+				}
+			default:
+				t.Fatalf("unexpected Content-Type %q", rec.Header().Get("Content-Type"))
+			}
+
+			if diff := cmp.Diff(got, tt.want, opts); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestRouteGuide_RecordRoute_TruncatedBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBuffer([]byte{0x0a, 0x08}))
+	req.Header.Set("Content-Type", "application/protobuf")
+
+	var cbErr error
+	NewRouteGuideHTTPConverter(&RouteGuide{}).RecordRoute(func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+		cbErr = err
+	}).ServeHTTP(httptest.NewRecorder(), req)
+
+	if cbErr != io.ErrUnexpectedEOF {
+		t.Errorf("callback error = %v, want %v", cbErr, io.ErrUnexpectedEOF)
+	}
+}
+
+type webSocketClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dialWebSocket(t *testing.T, url, protocol string, header http.Header) (*webSocketClient, *http.Response) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if protocol != "" {
+		req.Header.Set("Sec-WebSocket-Protocol", protocol)
+	}
+
+	conn, err := net.Dial("tcp", req.URL.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &webSocketClient{conn: conn, r: r}, resp
+}
+
+func (c *webSocketClient) writeFrame(t *testing.T, opcode byte, payload []byte) {
+	t.Helper()
+
+	mask := [4]byte{1, 2, 3, 4}
+	buf := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	buf = append(buf, mask[:]...)
+	for i, b := range payload {
+		buf = append(buf, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *webSocketClient) readFrame(t *testing.T) (byte, []byte) {
+	t.Helper()
+
+	var header [2]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		t.Fatal(err)
+	}
+	payload := make([]byte, header[1]&0x7f)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return header[0] & 0x0f, payload
+}
+
+func TestRouteGuide_RouteChat(t *testing.T) {
+	tests := []struct {
+		name      string
+		protocol  string
+		notes     []*RouteNote
+		wantCode  uint16
+		wantClose string
+	}{
+		{
+			name: "JSON text frames",
+			notes: []*RouteNote{
+				{Location: &Point{Latitude: 1}, Message: "hello"},
+				{Location: &Point{Latitude: 2}, Message: "world"},
+			},
+			wantCode: 1000,
+		},
+		{
+			name:     "protobuf binary frames",
+			protocol: "protobuf",
+			notes: []*RouteNote{
+				{Location: &Point{Latitude: 1}, Message: "hello"},
+			},
+			wantCode: 1000,
+		},
+		{
+			name: "error status",
+			notes: []*RouteNote{
+				{Message: "no location"},
+			},
+			wantCode:  4000 + 3,
+			wantClose: "location is required",
+		},
+	}
+
+	opts := cmpopts.IgnoreUnexported(RouteNote{}, Point{})
+
+	mux := http.NewServeMux()
+	RegisterRouteGuideHTTPHandlers(mux, NewRouteGuideHTTPConverter(&RouteGuide{}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c, resp := dialWebSocket(t, srv.URL+"/main.RouteGuide/RouteChat", tt.protocol, nil)
+			if resp.StatusCode != http.StatusSwitchingProtocols {
+				t.Fatalf("status code = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+			}
+			if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+				t.Errorf("Sec-WebSocket-Accept = %q", accept)
+			}
+			if protocol := resp.Header.Get("Sec-WebSocket-Protocol"); protocol != tt.protocol {
+				t.Errorf("Sec-WebSocket-Protocol = %q, want %q", protocol, tt.protocol)
+			}
+
+			for _, note := range tt.notes {
+				if tt.protocol == "protobuf" {
+					buf, err := proto.Marshal(note)
+					if err != nil {
+						t.Fatal(err)
+					}
+					c.writeFrame(t, 0x2, buf)
+				} else {
+					buf, err := protojson.Marshal(note)
+					if err != nil {
+						t.Fatal(err)
+					}
+					c.writeFrame(t, 0x1, buf)
+				}
+				if tt.wantClose != "" {
+					break
+				}
+
+				opcode, payload := c.readFrame(t)
+				got := &RouteNote{}
+				switch opcode {
+				case 0x1:
+					if err := protojson.Unmarshal(payload, got); err != nil {
+						t.Fatal(err)
+					}
+				case 0x2:
+					if err := proto.Unmarshal(payload, got); err != nil {
+						t.Fatal(err)
+					}
+				default:
+					t.Fatalf("unexpected opcode %x", opcode)
+				}
+				if tt.protocol == "protobuf" && opcode != 0x2 {
+					t.Errorf("opcode = %x, want binary frame", opcode)
+				}
+				if diff := cmp.Diff(got, note, opts); diff != "" {
+					t.Errorf("%s", diff)
+				}
+			}
+			if tt.wantClose == "" {
+				c.writeFrame(t, 0x8, binary.BigEndian.AppendUint16(nil, 1000))
+			}
+
+			opcode, payload := c.readFrame(t)
+			if opcode != 0x8 {
+				t.Fatalf("opcode = %x, want close frame", opcode)
+			}
+			if code := binary.BigEndian.Uint16(payload); code != tt.wantCode {
+				t.Errorf("close code = %d, want %d", code, tt.wantCode)
+			}
+			if reason := string(payload[2:]); reason != tt.wantClose {
+				t.Errorf("close reason = %q, want %q", reason, tt.wantClose)
+			}
+		})
+	}
+}
+
+func TestRouteGuide_RouteChat_BadHandshake(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/main.RouteGuide/RouteChat", nil)
+	rec := httptest.NewRecorder()
+	NewRouteGuideHTTPConverter(&RouteGuide{}).RouteChat(nil).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status code = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestRouteGuide_RouteChat_Origin(t *testing.T) {
+	tests := []struct {
+		name       string
+		origin     string
+		opts       []RouteGuideHTTPConverterOption
+		wantStatus int
+	}{
+		{
+			name:       "no origin",
+			wantStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:       "same origin",
+			origin:     "same",
+			wantStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:       "other origin",
+			origin:     "https://example.com",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "allowed origin",
+			origin:     "https://example.com",
+			opts:       []RouteGuideHTTPConverterOption{WithRouteGuideHTTPWebSocketOrigins("https://example.com")},
+			wantStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:       "any origin",
+			origin:     "https://example.com",
+			opts:       []RouteGuideHTTPConverterOption{WithRouteGuideHTTPWebSocketOrigins("*")},
+			wantStatus: http.StatusSwitchingProtocols,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			RegisterRouteGuideHTTPHandlers(mux, NewRouteGuideHTTPConverter(&RouteGuide{}, tt.opts...))
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			header := http.Header{}
+			switch tt.origin {
+			case "":
+			case "same":
+				header.Set("Origin", srv.URL)
+			default:
+				header.Set("Origin", tt.origin)
+			}
+			_, resp := dialWebSocket(t, srv.URL+"/main.RouteGuide/RouteChat", "", header)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status code = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// maskedFrame returns the frame sent by clients with the first byte of the header and the payload.
+func maskedFrame(b0 byte, payload []byte) []byte {
+	mask := [4]byte{1, 2, 3, 4}
+	buf := []byte{b0, 0x80 | byte(len(payload))}
+	buf = append(buf, mask[:]...)
+	for i, b := range payload {
+		buf = append(buf, b^mask[i%4])
+	}
+	return buf
+}
+
+func TestRouteGuide_RouteChat_InvalidFrames(t *testing.T) {
+	tests := []struct {
+		name     string
+		frames   [][]byte
+		wantCode uint16
+	}{
+		{
+			name:     "reserved bit",
+			frames:   [][]byte{maskedFrame(0x80|0x40|0x1, []byte(`{}`))},
+			wantCode: 1002,
+		},
+		{
+			name:     "reserved opcode",
+			frames:   [][]byte{maskedFrame(0x80|0x3, []byte(`{}`))},
+			wantCode: 1002,
+		},
+		{
+			name:     "fragmented control frame",
+			frames:   [][]byte{maskedFrame(0x9, nil)},
+			wantCode: 1002,
+		},
+		{
+			name:     "continuation without data frame",
+			frames:   [][]byte{maskedFrame(0x80, []byte(`{}`))},
+			wantCode: 1002,
+		},
+		{
+			name: "fragments larger than limit",
+			frames: [][]byte{
+				maskedFrame(0x1, []byte(`{"message":`)),
+				maskedFrame(0x0, []byte(`"0123456789"`)),
+				maskedFrame(0x80, []byte(`}`)),
+			},
+			wantCode: 1009,
+		},
+		{
+			name: "frame length larger than limit",
+			frames: [][]byte{
+				{0x80 | 0x2, 0x80 | 127, 0x40, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3, 4},
+			},
+			wantCode: 1009,
+		},
+	}
+
+	mux := http.NewServeMux()
+	RegisterRouteGuideHTTPHandlers(mux, NewRouteGuideHTTPConverter(&RouteGuide{}, WithRouteGuideHTTPMaxBodySize(16)))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c, resp := dialWebSocket(t, srv.URL+"/main.RouteGuide/RouteChat", "", nil)
+			if resp.StatusCode != http.StatusSwitchingProtocols {
+				t.Fatalf("status code = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+			}
+			for _, frame := range tt.frames {
+				if _, err := c.conn.Write(frame); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			opcode, payload := c.readFrame(t)
+			if opcode != 0x8 {
+				t.Fatalf("opcode = %x, want close frame", opcode)
+			}
+			if code := binary.BigEndian.Uint16(payload); code != tt.wantCode {
+				t.Errorf("close code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+type countingServerStream struct {
+	grpc.ServerStream
+	sent int
+}
+
+func (s *countingServerStream) SendMsg(m interface{}) error {
+	s.sent++
+	return s.ServerStream.SendMsg(m)
+}
+
+func TestRouteGuide_StreamInterceptors(t *testing.T) {
+	var calls []string
+	var infos []*grpc.StreamServerInfo
+	interceptor := func(name string) grpc.StreamServerInterceptor {
+		return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			calls = append(calls, name)
+			infos = append(infos, info)
+			return handler(srv, ss)
+		}
+	}
+	counter := &countingServerStream{}
+	counting := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		counter.ServerStream = ss
+		return handler(srv, counter)
+	}
+
+	conv := NewRouteGuideHTTPConverter(&RouteGuide{}, WithRouteGuideHTTPStreamInterceptors(interceptor("option")))
+
+	req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBufferString(`{"lo": {"latitude": 1}, "hi": {"latitude": 3}}`))
+	req.Header.Set("Content-Type", "application/json")
+	conv.ListFeatures(nil, interceptor("first"), interceptor("second"), counting).ServeHTTP(httptest.NewRecorder(), req)
+
+	if diff := cmp.Diff(calls, []string{"option", "first", "second"}); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if counter.sent != 3 {
+		t.Errorf("sent = %d, want %d", counter.sent, 3)
+	}
+	want := &grpc.StreamServerInfo{FullMethod: "/main.RouteGuide/ListFeatures", IsServerStream: true}
+	if diff := cmp.Diff(infos[0], want); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	calls, infos = nil, nil
+	req = httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBufferString("{\"latitude\": 1}\n"))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	rec := httptest.NewRecorder()
+	conv.RecordRoute(nil, counting).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status code = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	want = &grpc.StreamServerInfo{FullMethod: "/main.RouteGuide/RecordRoute", IsClientStream: true}
+	if diff := cmp.Diff(infos[0], want); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestRouteGuide_RecordRoute_MaxBodySize(t *testing.T) {
+	var body bytes.Buffer
+	for i := 0; i < 10; i++ {
+		body.WriteString(`{"latitude":1,"longitude":1}` + "\n")
+	}
+	req := httptest.NewRequest(http.MethodPost, "/routeguide", &body)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	rec := httptest.NewRecorder()
+	NewRouteGuideHTTPConverter(&RouteGuide{}, WithRouteGuideHTTPMaxBodySize(100)).RecordRoute(nil).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status code = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+type metadataRouteGuide struct {
+	RouteGuide
+}
+
+func (r *metadataRouteGuide) ListFeatures(rect *Rectangle, stream RouteGuide_ListFeaturesServer) error {
+	if err := stream.SendHeader(metadata.Pairs("x-request-id", "abc")); err != nil {
+		return err
+	}
+	if err := stream.Send(&Feature{Name: "feature"}); err != nil {
+		return err
+	}
+	stream.SetTrailer(metadata.Pairs("x-count", "1"))
+	return nil
+}
+
+func TestRouteGuide_ListFeatures_Metadata(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	NewRouteGuideHTTPConverter(&metadataRouteGuide{}).ListFeatures(nil).ServeHTTP(rec, req)
+
+	res := rec.Result()
+	if got := res.Header.Get("Grpc-Metadata-X-Request-Id"); got != "abc" {
+		t.Errorf("header = %q, want %q", got, "abc")
+	}
+	if got := res.Trailer.Get("Grpc-Trailer-X-Count"); got != "1" {
+		t.Errorf("trailer = %q, want %q", got, "1")
+	}
+}
+
+type statusTracer chan int
+
+func (t statusTracer) OnStart(ctx context.Context, method protoreflect.MethodDescriptor, route string, r *http.Request) context.Context {
+	return ctx
+}
+
+func (t statusTracer) OnFinish(ctx context.Context, method protoreflect.MethodDescriptor, arg, ret proto.Message, statusCode int, err error, elapsed time.Duration) {
+	t <- statusCode
+}
+
+func TestRouteGuide_RouteChat_Tracer(t *testing.T) {
+	tracer := make(statusTracer, 1)
+	mux := http.NewServeMux()
+	RegisterRouteGuideHTTPHandlers(mux, NewRouteGuideHTTPConverter(&RouteGuide{}, WithRouteGuideHTTPTracer(tracer)))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, resp := dialWebSocket(t, srv.URL+"/main.RouteGuide/RouteChat", "", nil)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status code = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	c.writeFrame(t, 0x8, binary.BigEndian.AppendUint16(nil, 1000))
+	if opcode, _ := c.readFrame(t); opcode != 0x8 {
+		t.Fatalf("opcode = %x, want close frame", opcode)
+	}
+
+	if got := <-tracer; got != http.StatusSwitchingProtocols {
+		t.Errorf("traced status code = %d, want %d", got, http.StatusSwitchingProtocols)
+	}
+}
+
+type panicRouteGuide struct {
+	RouteGuide
+}
+
+func (r *panicRouteGuide) ListFeatures(rect *Rectangle, stream RouteGuide_ListFeaturesServer) error {
+	panic("boom")
+}
+
+func TestRouteGuide_ListFeatures_Recovery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/routeguide", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	NewRouteGuideHTTPConverter(&panicRouteGuide{}, WithRouteGuideHTTPRecovery(nil)).ListFeatures(nil).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status code = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// debugRedactFieldNumber is the field number of debug_redact in google.protobuf.FieldOptions.
+const debugRedactFieldNumber = 16
+
+// genAccessLogOptions generates the options of the access log.
+func genAccessLogOptions(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// With", srv.GoName, "HTTPAccessLog sets the logger emitting an access log record of every request with the full method,")
+	g.P("// the HTTP method, the route, the status code, the gRPC code and the latency. Requests responded with 5xx are logged")
+	g.P("// at error level, and the others are logged at info level. The access log is disabled by default.")
+	g.P("func With", srv.GoName, "HTTPAccessLog(logger *", slogPackage.Ident("Logger"), ") ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.accessLogger = logger")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", srv.GoName, "HTTPAccessLogPayloads sets whether the access log contains the request and response messages in JSON.")
+	g.P("// Fields marked with debug_redact option are removed from the messages.")
+	g.P("func With", srv.GoName, "HTTPAccessLogPayloads(payloads bool) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.accessLogPayloads = payloads")
+	g.P("	}")
+	g.P("}")
+}
+
+// genAccessLog generates the method emitting the access log.
+func genAccessLog(g *protogen.GeneratedFile, srv *protogen.Service) {
+	redact := runtimeName(srv.Desc.ParentFile(), "redact")
+	g.P("// logAccess emits the access log record of the request.")
+	g.P("func (h *", srv.GoName, "HTTPConverter) logAccess(ctx ", contextPackage.Ident("Context"), ", r *", httpPackage.Ident("Request"), ", method ", protoreflectPackage.Ident("MethodDescriptor"), ", route string, statusCode int, code ", codesPackage.Ident("Code"), ", err error, elapsed ", timePackage.Ident("Duration"), ", arg, ret ", protoPackage.Ident("Message"), ") {")
+	g.P("	level := ", slogPackage.Ident("LevelInfo"))
+	g.P("	if statusCode >= ", httpPackage.Ident("StatusInternalServerError"), " {")
+	g.P("		level = ", slogPackage.Ident("LevelError"))
+	g.P("	}")
+	g.P("	attrs := []", slogPackage.Ident("Attr"), "{")
+	g.P("		", slogPackage.Ident("String"), "(\"method\", ", fmtPackage.Ident("Sprintf"), "(\"/%s/%s\", method.Parent().FullName(), method.Name())),")
+	g.P("		", slogPackage.Ident("String"), "(\"http_method\", r.Method),")
+	g.P("		", slogPackage.Ident("String"), "(\"route\", route),")
+	g.P("		", slogPackage.Ident("Int"), "(\"status\", statusCode),")
+	g.P("		", slogPackage.Ident("String"), "(\"code\", code.String()),")
+	g.P("		", slogPackage.Ident("Duration"), "(\"latency\", elapsed),")
+	g.P("	}")
+	g.P("	if err != nil {")
+	g.P("		attrs = append(attrs, ", slogPackage.Ident("String"), "(\"error\", err.Error()))")
+	g.P("	}")
+	g.P("	if h.accessLogPayloads {")
+	g.P("		if arg != nil {")
+	g.P("			attrs = append(attrs, ", slogPackage.Ident("String"), "(\"request\", ", redact, "(arg)))")
+	g.P("		}")
+	g.P("		if ret != nil {")
+	g.P("			attrs = append(attrs, ", slogPackage.Ident("String"), "(\"response\", ", redact, "(ret)))")
+	g.P("		}")
+	g.P("	}")
+	g.P("	h.accessLogger.LogAttrs(ctx, level, \"access\", attrs...)")
+	g.P("}")
+}
+
+// genRedact generates the functions removing the fields marked with debug_redact option from the access log.
+// The fields are cleared only if the messages of the file have such fields.
+func genRedact(g *protogen.GeneratedFile, file *protogen.File) {
+	redact := runtimeName(file.Desc, "redact")
+	clear := runtimeName(file.Desc, "clearSensitiveFields")
+	sensitive := runtimeName(file.Desc, "isSensitiveField")
+	fields := sensitiveFields(file)
+	g.P("// ", redact, " returns m marshaled in JSON without the fields marked with debug_redact option.")
+	g.P("func ", redact, "(m ", protoPackage.Ident("Message"), ") string {")
+	if len(fields) != 0 {
+		g.P("	m = ", protoPackage.Ident("Clone"), "(m)")
+		g.P("	", clear, "(m.ProtoReflect())")
+	}
+	g.P("	buf, err := ", protojsonPackage.Ident("Marshal"), "(m)")
+	g.P("	if err != nil {")
+	g.P("		return \"\"")
+	g.P("	}")
+	g.P("	return string(buf)")
+	g.P("}")
+	if len(fields) == 0 {
+		return
+	}
+	g.P()
+	g.P("// ", clear, " clears the fields of m and its descendants marked with debug_redact option.")
+	g.P("func ", clear, "(m ", protoreflectPackage.Ident("Message"), ") {")
+	g.P("	m.Range(func(fd ", protoreflectPackage.Ident("FieldDescriptor"), ", v ", protoreflectPackage.Ident("Value"), ") bool {")
+	g.P("		switch {")
+	g.P("		case ", sensitive, "(fd):")
+	g.P("			m.Clear(fd)")
+	g.P("		case fd.IsList() && fd.Message() != nil:")
+	g.P("			for i := 0; i < v.List().Len(); i++ {")
+	g.P("				", clear, "(v.List().Get(i).Message())")
+	g.P("			}")
+	g.P("		case fd.IsMap() && fd.MapValue().Message() != nil:")
+	g.P("			v.Map().Range(func(_ ", protoreflectPackage.Ident("MapKey"), ", v ", protoreflectPackage.Ident("Value"), ") bool {")
+	g.P("				", clear, "(v.Message())")
+	g.P("				return true")
+	g.P("			})")
+	g.P("		case fd.Message() != nil:")
+	g.P("			", clear, "(v.Message())")
+	g.P("		}")
+	g.P("		return true")
+	g.P("	})")
+	g.P("}")
+	g.P()
+	g.P("// ", sensitive, " reports whether fd is marked with debug_redact option.")
+	g.P("func ", sensitive, "(fd ", protoreflectPackage.Ident("FieldDescriptor"), ") bool {")
+	g.P("	switch fd.FullName() {")
+	for _, f := range fields {
+		g.P("	case \"", f.Desc.FullName(), "\":")
+		g.P("		return true")
+	}
+	g.P("	}")
+	g.P("	return false")
+	g.P("}")
+}
+
+// sensitiveFields returns the fields marked with debug_redact option in the messages used by the methods of the file.
+func sensitiveFields(file *protogen.File) []*protogen.Field {
+	var fields []*protogen.Field
+	seen := map[protoreflect.FullName]bool{}
+	var walk func(message *protogen.Message)
+	walk = func(message *protogen.Message) {
+		if message == nil || seen[message.Desc.FullName()] {
+			return
+		}
+		seen[message.Desc.FullName()] = true
+		for _, field := range message.Fields {
+			if isDebugRedact(field) {
+				fields = append(fields, field)
+			}
+			walk(field.Message)
+		}
+	}
+	for _, srv := range file.Services {
+		for _, method := range srv.Methods {
+			if !isGeneratedMethod(method) {
+				continue
+			}
+			walk(method.Input)
+			walk(method.Output)
+		}
+	}
+	return fields
+}
+
+// isDebugRedact reports whether the field has debug_redact option. The option is read from the unknown fields
+// of google.protobuf.FieldOptions if the descriptor compiled in the plugin does not know it.
+func isDebugRedact(field *protogen.Field) bool {
+	options, ok := field.Desc.Options().(*descriptorpb.FieldOptions)
+	if !ok || options == nil {
+		return false
+	}
+	m := options.ProtoReflect()
+	if fd := m.Descriptor().Fields().ByNumber(debugRedactFieldNumber); fd != nil {
+		return m.Get(fd).Bool()
+	}
+	redact := false
+	b := m.GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+		if num == debugRedactFieldNumber && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return false
+			}
+			redact = v != 0
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+	}
+	return redact
+}
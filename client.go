@@ -0,0 +1,645 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// clientName returns the name of the HTTP client of the service.
+func clientName(srv *protogen.Service) string {
+	return srv.GoName + "HTTPClient"
+}
+
+// hasMethodNamed reports whether the service has a method whose Go name is one of names.
+func hasMethodNamed(srv *protogen.Service, names ...string) bool {
+	for _, method := range srv.Methods {
+		for _, name := range names {
+			if method.GoName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getterChain converts the Go name of the field path to the chain of getters, i.e., "A.BC" to "GetA().GetBC()".
+func getterChain(goName string) string {
+	names := strings.Split(goName, ".")
+	for i, name := range names {
+		names[i] = "Get" + name + "()"
+	}
+	return strings.Join(names, ".")
+}
+
+// genClient generates the HTTP client calling unary methods of the converter.
+func genClient(g *protogen.GeneratedFile, srv *protogen.Service) error {
+	name := clientName(srv)
+
+	g.P("// ", name, " is the client API for ", srv.GoName, " service over HTTP.")
+	g.P("// Only unary methods are implemented.")
+	g.P("type ", name, " struct {")
+	g.P("	baseURL       string")
+	g.P("	client        *", httpPackage.Ident("Client"))
+	g.P("	contentType   string")
+	g.P("	interceptors  []", grpcPackage.Ident("UnaryClientInterceptor"))
+	g.P("	headers       []string")
+	g.P("	headerPrefix  string")
+	g.P("	trailerPrefix string")
+	g.P("}")
+	g.P()
+	g.P("// ", name, "Option configures ", name, ".")
+	g.P("type ", name, "Option func(*", name, ")")
+	g.P()
+	g.P("// With", name, " sets http.Client used to send requests. http.DefaultClient is used by default.")
+	g.P("func With", name, "(client *", httpPackage.Ident("Client"), ") ", name, "Option {")
+	g.P("	return func(c *", name, ") {")
+	g.P("		c.client = client")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", name, "ContentType sets the media type of requests and responses.")
+	g.P("// \"application/json\" (default), \"application/protobuf\" and \"application/x-protobuf\" are supported.")
+	g.P("func With", name, "ContentType(contentType string) ", name, "Option {")
+	g.P("	return func(c *", name, ") {")
+	g.P("		c.contentType = contentType")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", name, "Interceptors appends interceptors executed in left-to-right order for each call.")
+	g.P("// The method passed to the interceptors is the full method name, e.g., \"/", srv.Desc.FullName(), "/Method\", and cc is always nil.")
+	g.P("func With", name, "Interceptors(interceptors ...", grpcPackage.Ident("UnaryClientInterceptor"), ") ", name, "Option {")
+	g.P("	return func(c *", name, ") {")
+	g.P("		c.interceptors = append(c.interceptors, interceptors...)")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", name, "Headers sets the outgoing metadata keys sent as the request headers of the same names without the prefix.")
+	g.P("// The response headers of the names are also read as the header metadata.")
+	g.P("// The default is Authorization, the same as the incoming headers of the converter.")
+	g.P("func With", name, "Headers(headers ...string) ", name, "Option {")
+	g.P("	return func(c *", name, ") {")
+	g.P("		c.headers = headers")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", name, "HeaderPrefix sets the prefix of the request headers sending the other outgoing metadata,")
+	g.P("// and of the response headers read as the header metadata.")
+	g.P("// The default is \"Grpc-Metadata-\", the same as the incoming and outgoing header prefixes of the converter.")
+	g.P("func With", name, "HeaderPrefix(prefix string) ", name, "Option {")
+	g.P("	return func(c *", name, ") {")
+	g.P("		c.headerPrefix = prefix")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", name, "TrailerPrefix sets the prefix of the response headers read as the trailer metadata.")
+	g.P("// The default is \"Grpc-Trailer-\", the same as the outgoing trailer prefix of the converter.")
+	g.P("func With", name, "TrailerPrefix(prefix string) ", name, "Option {")
+	g.P("	return func(c *", name, ") {")
+	g.P("		c.trailerPrefix = prefix")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// New", name, " returns ", name, " sending requests to baseURL, e.g., \"http://localhost:8080\".")
+	g.P("func New", name, "(baseURL string, opts ...", name, "Option) *", name, " {")
+	g.P("	c := &", name, "{")
+	g.P("		baseURL:       ", stringsPackage.Ident("TrimSuffix"), "(baseURL, \"/\"),")
+	g.P("		client:        ", httpPackage.Ident("DefaultClient"), ",")
+	g.P("		contentType:   \"application/json\",")
+	g.P("		headers:       []string{\"Authorization\"},")
+	g.P("		headerPrefix:  \"Grpc-Metadata-\",")
+	g.P("		trailerPrefix: \"Grpc-Trailer-\",")
+	g.P("	}")
+	g.P("	for _, opt := range opts {")
+	g.P("		opt(c)")
+	g.P("	}")
+	g.P("	return c")
+	g.P("}")
+
+	unary := true
+	for _, method := range srv.Methods {
+		if !isGeneratedMethod(method) {
+			continue
+		}
+		if isStreaming(method) {
+			unary = false
+			continue
+		}
+		g.P()
+		if err := genClientMethod(g, method); err != nil {
+			return err
+		}
+	}
+	if unary {
+		g.P()
+		g.P("var _ ", srv.GoName, "HTTPService = (*", name, ")(nil)")
+	}
+	g.P()
+	genClientConn(g, srv)
+
+	g.P()
+	g.P("// invoke calls invoker through the interceptors.")
+	g.P("func (c *", name, ") invoke(ctx ", contextPackage.Ident("Context"), ", method string, req, reply interface{}, invoker ", grpcPackage.Ident("UnaryInvoker"), ", opts ...", grpcPackage.Ident("CallOption"), ") error {")
+	g.P("	chainer := func(currentInter ", grpcPackage.Ident("UnaryClientInterceptor"), ", currentInvoker ", grpcPackage.Ident("UnaryInvoker"), ") ", grpcPackage.Ident("UnaryInvoker"), " {")
+	g.P("		return func(currentCtx ", contextPackage.Ident("Context"), ", currentMethod string, currentReq, currentReply interface{}, cc *", grpcPackage.Ident("ClientConn"), ", opts ...", grpcPackage.Ident("CallOption"), ") error {")
+	g.P("			return currentInter(currentCtx, currentMethod, currentReq, currentReply, cc, currentInvoker, opts...)")
+	g.P("		}")
+	g.P("	}")
+	g.P("	chainedInvoker := invoker")
+	g.P("	for i := len(c.interceptors) - 1; i >= 0; i-- {")
+	g.P("		chainedInvoker = chainer(c.interceptors[i], chainedInvoker)")
+	g.P("	}")
+	g.P("	return chainedInvoker(ctx, method, req, reply, nil, opts...)")
+	g.P("}")
+	g.P()
+	g.P("// do sends in, or the field of in if field is not empty, as the request body unless in is nil,")
+	g.P("// and unmarshals the response into out. The response metadata is set to grpc.Header and grpc.Trailer of opts.")
+	g.P("func (c *", name, ") do(ctx ", contextPackage.Ident("Context"), ", method, path string, query ", urlPackage.Ident("Values"), ", in ", protoPackage.Ident("Message"), ", field ", protoreflectPackage.Ident("Name"), ", out ", protoPackage.Ident("Message"), ", opts ...", grpcPackage.Ident("CallOption"), ") error {")
+	g.P("	u := c.baseURL + path")
+	g.P("	if len(query) != 0 {")
+	g.P("		u += \"?\" + query.Encode()")
+	g.P("	}")
+	g.P()
+	g.P("	var body ", ioPackage.Ident("Reader"))
+	g.P("	if in != nil {")
+	g.P("		buf, err := c.marshal(in, field)")
+	g.P("		if err != nil {")
+	g.P("			return err")
+	g.P("		}")
+	g.P("		body = ", bytesPackage.Ident("NewReader"), "(buf)")
+	g.P("	}")
+	g.P()
+	g.P("	req, err := ", httpPackage.Ident("NewRequestWithContext"), "(ctx, method, u, body)")
+	g.P("	if err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	// Content-Type is also set to GET requests because the converter encodes errors by it.")
+	g.P("	req.Header.Set(\"Content-Type\", c.contentType)")
+	g.P("	req.Header.Set(\"Accept\", c.contentType)")
+	g.P("	// Outgoing metadata set by interceptors is sent as headers which the converter reads as incoming metadata.")
+	g.P("	md, _ := ", metadataPackage.Ident("FromOutgoingContext"), "(ctx)")
+	g.P("	for k, vs := range md {")
+	g.P("		key := c.headerPrefix + k")
+	g.P("		for _, h := range c.headers {")
+	g.P("			if ", stringsPackage.Ident("EqualFold"), "(h, k) {")
+	g.P("				key = k")
+	g.P("			}")
+	g.P("		}")
+	g.P("		for _, v := range vs {")
+	g.P("			if ", stringsPackage.Ident("HasSuffix"), "(k, \"-bin\") {")
+	g.P("				v = ", base64Package.Ident("RawStdEncoding"), ".EncodeToString([]byte(v))")
+	g.P("			}")
+	g.P("			req.Header.Add(key, v)")
+	g.P("		}")
+	g.P("	}")
+	g.P()
+	g.P("	resp, err := c.client.Do(req)")
+	g.P("	if err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	defer resp.Body.Close()")
+	g.P("	c.setMetadata(resp.Header, opts)")
+	g.P()
+	g.P("	buf, err := ", ioPackage.Ident("ReadAll"), "(resp.Body)")
+	g.P("	if err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P()
+	g.P("	contentType, _, _ := ", mimePackage.Ident("ParseMediaType"), "(resp.Header.Get(\"Content-Type\"))")
+	g.P("	if resp.StatusCode < 200 || resp.StatusCode >= 300 {")
+	g.P("		return ", unexport(name), "Error(resp.StatusCode, contentType, buf)")
+	g.P("	}")
+	g.P("	switch contentType {")
+	g.P("	case \"application/protobuf\", \"application/x-protobuf\":")
+	g.P("		return ", protoPackage.Ident("Unmarshal"), "(buf, out)")
+	g.P("	default:")
+	g.P("		return ", protojsonPackage.Ident("Unmarshal"), "(buf, out)")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// setMetadata sets the metadata read from the response headers to grpc.Header and grpc.Trailer of opts.")
+	g.P("// Values of the keys ending with \"-bin\" are decoded from base64 as gRPC does. The other options are ignored.")
+	g.P("func (c *", name, ") setMetadata(header ", httpPackage.Ident("Header"), ", opts []", grpcPackage.Ident("CallOption"), ") {")
+	g.P("	headerMD, trailerMD := ", metadataPackage.Ident("MD"), "{}, ", metadataPackage.Ident("MD"), "{}")
+	g.P("	add := func(md ", metadataPackage.Ident("MD"), ", key string, values []string) {")
+	g.P("		key = ", stringsPackage.Ident("ToLower"), "(key)")
+	g.P("		for _, v := range values {")
+	g.P("			if ", stringsPackage.Ident("HasSuffix"), "(key, \"-bin\") {")
+	g.P("				enc := ", base64Package.Ident("StdEncoding"))
+	g.P("				if len(v)%4 != 0 {")
+	g.P("					enc = ", base64Package.Ident("RawStdEncoding"))
+	g.P("				}")
+	g.P("				b, err := enc.DecodeString(v)")
+	g.P("				if err != nil {")
+	g.P("					continue")
+	g.P("				}")
+	g.P("				v = string(b)")
+	g.P("			}")
+	g.P("			md.Append(key, v)")
+	g.P("		}")
+	g.P("	}")
+	g.P("	hasPrefix := func(key, prefix string) bool {")
+	g.P("		return prefix != \"\" && len(key) > len(prefix) && ", stringsPackage.Ident("EqualFold"), "(key[:len(prefix)], prefix)")
+	g.P("	}")
+	g.P()
+	g.P("	for _, key := range c.headers {")
+	g.P("		add(headerMD, key, header.Values(key))")
+	g.P("	}")
+	g.P("	for key, values := range header {")
+	g.P("		switch {")
+	g.P("		case hasPrefix(key, c.trailerPrefix):")
+	g.P("			add(trailerMD, key[len(c.trailerPrefix):], values)")
+	g.P("		case hasPrefix(key, c.headerPrefix):")
+	g.P("			add(headerMD, key[len(c.headerPrefix):], values)")
+	g.P("		}")
+	g.P("	}")
+	g.P()
+	g.P("	for _, opt := range opts {")
+	g.P("		switch o := opt.(type) {")
+	g.P("		case ", grpcPackage.Ident("HeaderCallOption"), ":")
+	g.P("			*o.HeaderAddr = headerMD")
+	g.P("		case ", grpcPackage.Ident("TrailerCallOption"), ":")
+	g.P("			*o.TrailerAddr = trailerMD")
+	g.P("		}")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// marshal marshals in, or the field of in if field is not empty, in the content type of the client.")
+	g.P("// Only message fields can be marshaled in protobuf.")
+	g.P("func (c *", name, ") marshal(in ", protoPackage.Ident("Message"), ", field ", protoreflectPackage.Ident("Name"), ") ([]byte, error) {")
+	g.P("	protobuf := c.contentType == \"application/protobuf\" || c.contentType == \"application/x-protobuf\"")
+	g.P("	if field == \"\" {")
+	g.P("		if protobuf {")
+	g.P("			return ", protoPackage.Ident("Marshal"), "(in)")
+	g.P("		}")
+	g.P("		return ", protojsonPackage.Ident("Marshal"), "(in)")
+	g.P("	}")
+	g.P()
+	g.P("	m := in.ProtoReflect()")
+	g.P("	fd := m.Descriptor().Fields().ByName(field)")
+	g.P("	if protobuf {")
+	g.P("		if fd.Message() == nil || fd.IsList() || fd.IsMap() {")
+	g.P("			return nil, ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Unimplemented"), ", \"field %s cannot be sent in %s\", field, c.contentType)")
+	g.P("		}")
+	g.P("		return ", protoPackage.Ident("Marshal"), "(m.Get(fd).Message().Interface())")
+	g.P("	}")
+	g.P("	// The JSON of the field is taken from the message which has only the field.")
+	g.P("	only := m.New()")
+	g.P("	if m.Has(fd) {")
+	g.P("		only.Set(fd, m.Get(fd))")
+	g.P("	}")
+	g.P("	buf, err := ", protojsonPackage.Ident("MarshalOptions"), "{EmitUnpopulated: true}.Marshal(only.Interface())")
+	g.P("	if err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	var fields map[string]", jsonPackage.Ident("RawMessage"))
+	g.P("	if err := ", jsonPackage.Ident("Unmarshal"), "(buf, &fields); err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	return fields[fd.JSONName()], nil")
+	g.P("}")
+	g.P()
+	g.P("// ", unexport(name), "Error converts the error response to the error of grpc/status.")
+	g.P("// If the body is not google.rpc.Status, the code is decided by the HTTP status code as gRPC clients do.")
+	g.P("func ", unexport(name), "Error(code int, contentType string, body []byte) error {")
+	g.P("	p := ", statusPackage.Ident("New"), "(", codesPackage.Ident("Unknown"), ", \"\").Proto()")
+	g.P("	var err error")
+	g.P("	switch contentType {")
+	g.P("	case \"application/protobuf\", \"application/x-protobuf\":")
+	g.P("		err = ", protoPackage.Ident("Unmarshal"), "(body, p)")
+	g.P("	case \"application/json\":")
+	g.P("		err = ", protojsonPackage.Ident("Unmarshal"), "(body, p)")
+	g.P("	default:")
+	g.P("		err = ", fmtPackage.Ident("Errorf"), "(\"unexpected Content-Type: %s\", contentType)")
+	g.P("	}")
+	g.P("	if err == nil && ", codesPackage.Ident("Code"), "(p.GetCode()) != ", codesPackage.Ident("OK"), " {")
+	g.P("		return ", statusPackage.Ident("ErrorProto"), "(p)")
+	g.P("	}")
+	g.P()
+	g.P("	c := ", codesPackage.Ident("Unknown"))
+	g.P("	switch code {")
+	g.P("	case ", httpPackage.Ident("StatusBadRequest"), ":")
+	g.P("		c = ", codesPackage.Ident("Internal"))
+	g.P("	case ", httpPackage.Ident("StatusUnauthorized"), ":")
+	g.P("		c = ", codesPackage.Ident("Unauthenticated"))
+	g.P("	case ", httpPackage.Ident("StatusForbidden"), ":")
+	g.P("		c = ", codesPackage.Ident("PermissionDenied"))
+	g.P("	case ", httpPackage.Ident("StatusNotFound"), ":")
+	g.P("		c = ", codesPackage.Ident("Unimplemented"))
+	g.P("	case ", httpPackage.Ident("StatusTooManyRequests"), ", ", httpPackage.Ident("StatusBadGateway"), ", ", httpPackage.Ident("StatusServiceUnavailable"), ", ", httpPackage.Ident("StatusGatewayTimeout"), ":")
+	g.P("		c = ", codesPackage.Ident("Unavailable"))
+	g.P("	}")
+	g.P("	return ", statusPackage.Ident("Errorf"), "(c, \"unexpected HTTP status code %d: %s\", code, body)")
+	g.P("}")
+
+	return nil
+}
+
+func genClientMethod(g *protogen.GeneratedFile, method *protogen.Method) error {
+	httpMethod, pattern := "POST", fullMethodName(method)
+	var rule *annotations.HttpRule
+	if r, ok := getHTTPRule(method); ok {
+		if m, p, ok := httpRulePattern(r); ok {
+			httpMethod, pattern, rule = m, p, r
+		}
+	}
+
+	name := clientName(method.Parent)
+	invoker := "invoke" + method.GoName
+
+	g.P("// ", method.GoName, " calls ", method.GoName, " with ", httpMethod, " ", pattern, ".")
+	g.P("func (c *", name, ") ", method.GoName, "(ctx ", contextPackage.Ident("Context"), ", in *", genMessageName(method.Input), ") (*", genMessageName(method.Output), ", error) {")
+	g.P("	out := &", genMessageName(method.Output), "{}")
+	g.P("	if err := c.invoke(ctx, \"", fullMethodName(method), "\", in, out, c.", invoker, "); err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	return out, nil")
+	g.P("}")
+	g.P()
+	g.P("// ", invoker, " is the grpc.UnaryInvoker sending the request of ", method.GoName, ".")
+	g.P("func (c *", name, ") ", invoker, "(ctx ", contextPackage.Ident("Context"), ", method string, req, reply interface{}, cc *", grpcPackage.Ident("ClientConn"), ", opts ...", grpcPackage.Ident("CallOption"), ") error {")
+
+	var path string
+	if rule != nil {
+		p, err := clientPath(g, pattern)
+		if err != nil {
+			g.P("	return ", statusPackage.Ident("Error"), "(", codesPackage.Ident("Unimplemented"), ", \"", fullMethodName(method), ": ", strings.Replace(err.Error(), "\"", "'", -1), "\")")
+			g.P("}")
+			return nil
+		}
+		path = p
+	}
+
+	var pathParams []*pathParam
+	var queryParams []*queryParam
+	body := "*"
+	if rule != nil {
+		var err error
+		pathParams, err = parsePathParam(pattern)
+		if err != nil {
+			return err
+		}
+		if _, err := bodyField(method, rule); err != nil {
+			return err
+		}
+		body = rule.GetBody()
+		if httpMethod == "GET" {
+			body = ""
+		}
+		for _, q := range ruleQueryParams(method, rule, pathParams) {
+			if isClientQueryKind(q.Desc.Kind()) {
+				queryParams = append(queryParams, q)
+			}
+		}
+	}
+
+	if body == "" && len(pathParams) == 0 && len(queryParams) == 0 {
+		g.P("	if _, ok := req.(*", genMessageName(method.Input), "); !ok {")
+	} else {
+		g.P("	in, ok := req.(*", genMessageName(method.Input), ")")
+		g.P("	if !ok {")
+	}
+	g.P("		return ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Internal"), ", \"unexpected request type: %T\", req)")
+	g.P("	}")
+	g.P("	out, ok := reply.(*", genMessageName(method.Output), ")")
+	g.P("	if !ok {")
+	g.P("		return ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Internal"), ", \"unexpected response type: %T\", reply)")
+	g.P("	}")
+	if rule == nil {
+		g.P("	return c.do(ctx, ", httpMethodIdent(httpMethod), ", \"", pattern, "\", nil, in, \"\", out, opts...)")
+		g.P("}")
+		return nil
+	}
+
+	g.P("	path := ", path)
+	switch body {
+	case "*":
+		// Fields bound to the path are not sent in the body.
+		g.P("	body := ", protoPackage.Ident("Clone"), "(in).(*", genMessageName(method.Input), ")")
+		for _, p := range pathParams {
+			if err := genClientClearField(g, method, p); err != nil {
+				return err
+			}
+		}
+		g.P("	return c.do(ctx, ", httpMethodIdent(httpMethod), ", path, nil, body, \"\", out, opts...)")
+	default:
+		g.P("	query := ", urlPackage.Ident("Values"), "{}")
+		for _, q := range queryParams {
+			genClientQueryString(g, q)
+		}
+		if body == "" {
+			g.P("	return c.do(ctx, ", httpMethodIdent(httpMethod), ", path, query, nil, \"\", out, opts...)")
+		} else {
+			g.P("	return c.do(ctx, ", httpMethodIdent(httpMethod), ", path, query, in, \"", body, "\", out, opts...)")
+		}
+	}
+	g.P("}")
+	return nil
+}
+
+// genClientClearField generates the code clearing the field of the path parameter in the request body "body".
+func genClientClearField(g *protogen.GeneratedFile, method *protogen.Method, p *pathParam) error {
+	field := findField(method.Input, p.Name)
+	if field == nil {
+		return fmt.Errorf("%s: path parameter %q is not a field of %s", method.Desc.FullName(), p.Name, method.Input.Desc.FullName())
+	}
+	zero := "0"
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		zero = "false"
+	case protoreflect.StringKind:
+		zero = `""`
+	case protoreflect.BytesKind, protoreflect.MessageKind, protoreflect.GroupKind:
+		zero = "nil"
+	}
+	if field.Desc.IsList() || field.Desc.IsMap() {
+		zero = "nil"
+	}
+
+	names := strings.Split(p.GoName, ".")
+	if len(names) == 1 {
+		g.P("	body.", p.GoName, " = ", zero)
+		return nil
+	}
+	g.P("	if m := body.", getterChain(strings.Join(names[:len(names)-1], ".")), "; m != nil {")
+	g.P("		m.", names[len(names)-1], " = ", zero)
+	g.P("	}")
+	return nil
+}
+
+// genClientConn generates the methods implementing grpc.ClientConnInterface,
+// so that the HTTP client can be passed to the client constructor generated by protoc-gen-go-grpc.
+func genClientConn(g *protogen.GeneratedFile, srv *protogen.Service) {
+	name := clientName(srv)
+	conn := name + "Conn"
+
+	g.P("// ", conn, " implements grpc.ClientConnInterface by ", name, ", so the client can be used as")
+	g.P("// the connection of gRPC clients, e.g., New", srv.GoName, "Client(New", conn, "(New", name, "(baseURL))).")
+	g.P("type ", conn, " struct {")
+	g.P("	c *", name)
+	g.P("}")
+	g.P()
+	g.P("// New", conn, " returns ", conn, " sending the RPCs by c.")
+	g.P("func New", conn, "(c *", name, ") *", conn, " {")
+	g.P("	return &", conn, "{c: c}")
+	g.P("}")
+	g.P()
+	g.P("// Invoke sends the unary RPC of method over HTTP. The HTTP method and path are decided by")
+	g.P("// google.api.http option of the method in the same way as the methods of ", name, ".")
+	g.P("// The response metadata is set to grpc.Header and grpc.Trailer of opts, and the other options are ignored.")
+	g.P("func (cc *", conn, ") Invoke(ctx ", contextPackage.Ident("Context"), ", method string, args, reply interface{}, opts ...", grpcPackage.Ident("CallOption"), ") error {")
+	g.P("	switch method {")
+	for _, method := range srv.Methods {
+		if !isGeneratedMethod(method) || isStreaming(method) {
+			continue
+		}
+		g.P("	case \"", fullMethodName(method), "\":")
+		g.P("		return cc.c.invoke(ctx, method, args, reply, cc.c.invoke", method.GoName, ", opts...)")
+	}
+	g.P("	}")
+	g.P("	return ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Unimplemented"), ", \"unknown method %s\", method)")
+	g.P("}")
+	g.P()
+	g.P("// NewStream always returns an error because streaming RPCs are not supported by ", name, ".")
+	g.P("func (cc *", conn, ") NewStream(ctx ", contextPackage.Ident("Context"), ", desc *", grpcPackage.Ident("StreamDesc"), ", method string, opts ...", grpcPackage.Ident("CallOption"), ") (", grpcPackage.Ident("ClientStream"), ", error) {")
+	g.P("	return nil, ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("Unimplemented"), ", \"streaming RPC %s is not supported over HTTP\", method)")
+	g.P("}")
+	g.P()
+	g.P("var _ ", grpcPackage.Ident("ClientConnInterface"), " = (*", conn, ")(nil)")
+
+	// The client itself also implements grpc.ClientConnInterface unless the methods collide with the RPCs.
+	if hasMethodNamed(srv, "Invoke", "NewStream") {
+		return
+	}
+	g.P()
+	g.P("// Invoke is the same as Invoke of ", conn, ". Invoke and NewStream implement grpc.ClientConnInterface,")
+	g.P("// so the client can be used as the connection of gRPC clients, e.g., New", srv.GoName, "Client(New", name, "(baseURL)).")
+	g.P("func (c *", name, ") Invoke(ctx ", contextPackage.Ident("Context"), ", method string, args, reply interface{}, opts ...", grpcPackage.Ident("CallOption"), ") error {")
+	g.P("	return New", conn, "(c).Invoke(ctx, method, args, reply, opts...)")
+	g.P("}")
+	g.P()
+	g.P("// NewStream always returns an error because streaming RPCs are not supported by ", name, ".")
+	g.P("func (c *", name, ") NewStream(ctx ", contextPackage.Ident("Context"), ", desc *", grpcPackage.Ident("StreamDesc"), ", method string, opts ...", grpcPackage.Ident("CallOption"), ") (", grpcPackage.Ident("ClientStream"), ", error) {")
+	g.P("	return New", conn, "(c).NewStream(ctx, desc, method, opts...)")
+	g.P("}")
+	g.P()
+	g.P("var _ ", grpcPackage.Ident("ClientConnInterface"), " = (*", name, ")(nil)")
+}
+
+// clientPath returns the Go expression building the path from the path template and the request message "in".
+func clientPath(g *protogen.GeneratedFile, pattern string) (string, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return "", fmt.Errorf("no leading /")
+	}
+	tokens, verb := tokenize(pattern[1:])
+
+	p := parser{tokens: tokens}
+	segs, err := p.topLevelSegments()
+	if err != nil {
+		return "", err
+	}
+
+	elems := make([]string, 0, len(segs))
+	for _, seg := range segs {
+		switch s := seg.(type) {
+		case literal:
+			elems = append(elems, fmt.Sprintf("%q", "/"+string(s)))
+		case wildcard, deepWildcard:
+			return "", fmt.Errorf("wildcard without field path is not supported: %s", pattern)
+		case variable:
+			if len(s.segments) == 1 {
+				if _, ok := s.segments[0].(wildcard); ok {
+					elems = append(elems, `"/"`, g.QualifiedGoIdent(urlPackage.Ident("PathEscape"))+"(in."+getterChain(toCamelCase(s.path))+")")
+					continue
+				}
+			}
+			elems = append(elems, `"/"`, "(&"+g.QualifiedGoIdent(urlPackage.Ident("URL"))+"{Path: in."+getterChain(toCamelCase(s.path))+"}).EscapedPath()")
+		}
+	}
+	if verb != "" {
+		elems = append(elems, fmt.Sprintf("%q", ":"+verb))
+	}
+
+	// Merge adjacent string literals.
+	merged := make([]string, 0, len(elems))
+	for _, e := range elems {
+		if n := len(merged); n != 0 && strings.HasPrefix(e, `"`) && strings.HasSuffix(merged[n-1], `"`) {
+			merged[n-1] = merged[n-1][:len(merged[n-1])-1] + e[1:]
+			continue
+		}
+		merged = append(merged, e)
+	}
+	return strings.Join(merged, " + "), nil
+}
+
+// isClientQueryKind reports whether genClientQueryString adds the field of the kind to the query string.
+func isClientQueryKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.BoolKind,
+		protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind,
+		protoreflect.FloatKind, protoreflect.DoubleKind, protoreflect.StringKind, protoreflect.BytesKind:
+		return true
+	}
+	return false
+}
+
+// genClientQueryString generates the code adding the field of the request message to the query string
+// in the format which genQueryString reads.
+func genClientQueryString(g *protogen.GeneratedFile, queryParam *queryParam) {
+	getter := "in." + getterChain(queryParam.GoName)
+
+	var format func(v string) string
+	zero := "0"
+	switch queryParam.Desc.Kind() {
+	case protoreflect.BoolKind:
+		format = func(v string) string { return g.QualifiedGoIdent(strconvPackage.Ident("FormatBool")) + "(" + v + ")" }
+		zero = "false"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		format = func(v string) string {
+			return g.QualifiedGoIdent(strconvPackage.Ident("FormatInt")) + "(int64(" + v + "), 10)"
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		format = func(v string) string {
+			return g.QualifiedGoIdent(strconvPackage.Ident("FormatUint")) + "(uint64(" + v + "), 10)"
+		}
+	case protoreflect.FloatKind:
+		format = func(v string) string {
+			return g.QualifiedGoIdent(strconvPackage.Ident("FormatFloat")) + "(float64(" + v + "), 'g', -1, 32)"
+		}
+	case protoreflect.DoubleKind:
+		format = func(v string) string {
+			return g.QualifiedGoIdent(strconvPackage.Ident("FormatFloat")) + "(" + v + ", 'g', -1, 64)"
+		}
+	case protoreflect.StringKind:
+		format = func(v string) string { return v }
+		zero = `""`
+	case protoreflect.BytesKind:
+		format = func(v string) string {
+			return g.QualifiedGoIdent(base64Package.Ident("StdEncoding")) + ".EncodeToString(" + v + ")"
+		}
+		zero = ""
+	default:
+		// The converter does not read the other types from the query string.
+		return
+	}
+
+	if queryParam.Desc.IsList() {
+		g.P("	for _, v := range ", getter, " {")
+		g.P("		query.Add(\"", queryParam.Name, "\", ", format("v"), ")")
+		g.P("	}")
+		return
+	}
+	if zero == "" {
+		g.P("	if v := ", getter, "; len(v) != 0 {")
+	} else {
+		g.P("	if v := ", getter, "; v != ", zero, " {")
+	}
+	g.P("		query.Set(\"", queryParam.Name, "\", ", format("v"), ")")
+	g.P("	}")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// genCompressionOptions generates the option of the response compression.
+func genCompressionOptions(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// With", srv.GoName, "HTTPCompression enables the compression of response bodies of unary and client-side streaming RPCs")
+	g.P("// with gzip or deflate negotiated by Accept-Encoding header. Only bodies of at least minSize bytes are compressed.")
+	g.P("// A negative value disables it, which is the default.")
+	g.P("func With", srv.GoName, "HTTPCompression(minSize int) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.compressionMinSize = minSize")
+	g.P("	}")
+	g.P("}")
+}
+
+// genDecodeContentEncoding generates the code decoding the request body by Content-Encoding header.
+// Unsupported encodings are responded with 415 Unsupported Media Type.
+func genDecodeContentEncoding(g *protogen.GeneratedFile, file protoreflect.FileDescriptor) {
+	g.P("switch encoding := ", stringsPackage.Ident("ToLower"), "(r.Header.Get(\"Content-Encoding\")); encoding {")
+	g.P("case \"\", \"identity\":")
+	g.P("case \"gzip\", \"x-gzip\", \"deflate\":")
+	g.P("	if err := ", runtimeName(file, "decodeBody"), "(r, encoding); err != nil {")
+	g.P("		cb(ctx, w, r, nil, nil, err)")
+	g.P("		return")
+	g.P("	}")
+	g.P("default:")
+	g.P("	w.WriteHeader(", httpPackage.Ident("StatusUnsupportedMediaType"), ")")
+	g.P("	_, err := ", fmtPackage.Ident("Fprintf"), "(w, \"Unsupported Content-Encoding: %s\", encoding)")
+	g.P("	cb(ctx, w, r, nil, nil, err)")
+	g.P("	return")
+	g.P("}")
+}
+
+// genDecodeBodyEncoding generates the function decoding request bodies by Content-Encoding header.
+func genDecodeBodyEncoding(g *protogen.GeneratedFile, file *protogen.File) {
+	decode := runtimeName(file.Desc, "decodeBody")
+	decoded := runtimeName(file.Desc, "decodedBody")
+	g.P("// ", decode, " replaces the body of r with the reader decoding encoding, which is gzip, x-gzip or deflate.")
+	g.P("// A malformed body fails with codes.InvalidArgument.")
+	g.P("func ", decode, "(r *", httpPackage.Ident("Request"), ", encoding string) error {")
+	g.P("	var zr ", ioPackage.Ident("ReadCloser"))
+	g.P("	var err error")
+	g.P("	if encoding == \"deflate\" {")
+	g.P("		zr, err = ", zlibPackage.Ident("NewReader"), "(r.Body)")
+	g.P("	} else {")
+	g.P("		zr, err = ", gzipPackage.Ident("NewReader"), "(r.Body)")
+	g.P("	}")
+	g.P("	if err != nil {")
+	g.P("		return ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("InvalidArgument"), ", \"invalid %s request body: %v\", encoding, err)")
+	g.P("	}")
+	g.P("	r.Body = &", decoded, "{ReadCloser: zr, encoding: encoding}")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// ", decoded, " is the request body decoded by Content-Encoding header.")
+	g.P("// Reading it fails with codes.InvalidArgument if the rest of the body turns out to be malformed.")
+	g.P("type ", decoded, " struct {")
+	g.P("	", ioPackage.Ident("ReadCloser"))
+	g.P("	encoding string")
+	g.P("}")
+	g.P()
+	g.P("func (b *", decoded, ") Read(p []byte) (int, error) {")
+	g.P("	n, err := b.ReadCloser.Read(p)")
+	g.P("	if err != nil && err != ", ioPackage.Ident("EOF"), " {")
+	g.P("		err = ", statusPackage.Ident("Errorf"), "(", codesPackage.Ident("InvalidArgument"), ", \"invalid %s request body: %v\", b.encoding, err)")
+	g.P("	}")
+	g.P("	return n, err")
+	g.P("}")
+}
+
+// genCompression generates the functions compressing response bodies.
+func genCompression(g *protogen.GeneratedFile, file *protogen.File) {
+	compress := runtimeName(file.Desc, "compress")
+	accept := runtimeName(file.Desc, "acceptEncoding")
+	g.P("// ", compress, " returns buf compressed by the content coding negotiated by Accept-Encoding header of r")
+	g.P("// if buf is at least minSize bytes, or identity is not accepted. A negative minSize disables the compression.")
+	g.P("// Otherwise, it returns buf as is.")
+	g.P("func ", compress, "(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ", buf []byte, minSize int) []byte {")
+	g.P("	if minSize < 0 {")
+	g.P("		return buf")
+	g.P("	}")
+	g.P("	w.Header().Add(\"Vary\", \"Accept-Encoding\")")
+	g.P("	encoding, identity := ", accept, "(r)")
+	g.P("	if identity && len(buf) < minSize {")
+	g.P("		return buf")
+	g.P("	}")
+	g.P("	var b ", bytesPackage.Ident("Buffer"))
+	g.P("	var zw ", ioPackage.Ident("WriteCloser"))
+	g.P("	switch encoding {")
+	g.P("	case \"gzip\":")
+	g.P("		zw = ", gzipPackage.Ident("NewWriter"), "(&b)")
+	g.P("	case \"deflate\":")
+	g.P("		zw = ", zlibPackage.Ident("NewWriter"), "(&b)")
+	g.P("	default:")
+	g.P("		return buf")
+	g.P("	}")
+	g.P("	if _, err := zw.Write(buf); err != nil {")
+	g.P("		return buf")
+	g.P("	}")
+	g.P("	if err := zw.Close(); err != nil {")
+	g.P("		return buf")
+	g.P("	}")
+	g.P("	w.Header().Set(\"Content-Encoding\", encoding)")
+	g.P("	return b.Bytes()")
+	g.P("}")
+	g.P()
+	g.P("// ", accept, " returns gzip or deflate accepted by Accept-Encoding header of r. gzip is preferred to deflate.")
+	g.P("// It returns the empty string if neither is accepted. Codings with q=0 are not accepted, and * applies to the codings")
+	g.P("// not listed in the header. identity reports whether the response may be sent without any coding.")
+	g.P("func ", accept, "(r *", httpPackage.Ident("Request"), ") (encoding string, identity bool) {")
+	g.P("	qs := make(map[string]float64)")
+	g.P("	for _, v := range ", stringsPackage.Ident("Split"), "(r.Header.Get(\"Accept-Encoding\"), \",\") {")
+	g.P("		coding, params, _ := ", stringsPackage.Ident("Cut"), "(v, \";\")")
+	g.P("		coding = ", stringsPackage.Ident("ToLower"), "(", stringsPackage.Ident("TrimSpace"), "(coding))")
+	g.P("		if coding == \"\" {")
+	g.P("			continue")
+	g.P("		}")
+	g.P("		if coding == \"x-gzip\" {")
+	g.P("			coding = \"gzip\"")
+	g.P("		}")
+	g.P("		q := 1.0")
+	g.P("		if v, ok := ", stringsPackage.Ident("CutPrefix"), "(", stringsPackage.Ident("TrimSpace"), "(params), \"q=\"); ok {")
+	g.P("			if f, err := ", strconvPackage.Ident("ParseFloat"), "(v, 64); err == nil {")
+	g.P("				q = f")
+	g.P("			}")
+	g.P("		}")
+	g.P("		qs[coding] = q")
+	g.P("	}")
+	g.P("	accepted := func(coding string) bool {")
+	g.P("		if q, ok := qs[coding]; ok {")
+	g.P("			return q > 0")
+	g.P("		}")
+	g.P("		if q, ok := qs[\"*\"]; ok {")
+	g.P("			return q > 0")
+	g.P("		}")
+	g.P("		// identity is acceptable unless it is excluded explicitly.")
+	g.P("		return coding == \"identity\"")
+	g.P("	}")
+	g.P()
+	g.P("	switch {")
+	g.P("	case accepted(\"gzip\"):")
+	g.P("		encoding = \"gzip\"")
+	g.P("	case accepted(\"deflate\"):")
+	g.P("		encoding = \"deflate\"")
+	g.P("	}")
+	g.P("	return encoding, accepted(\"identity\")")
+	g.P("}")
+}
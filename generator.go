@@ -6,12 +6,26 @@ import (
 	"sort"
 	"strings"
 
+	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 var toCamelCaseRe = regexp.MustCompile(`(^[A-Za-z])|(_|\.)([A-Za-z])`)
 
+var fileVarRe = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// fileVarName returns the name of the unexported identifier shared by all services of the proto file.
+// It is prefixed in the same way as the file-level variables of protoc-gen-go, e.g. file_foo_bar_proto_name for foo/bar.proto.
+func fileVarName(protoPath, name string) string {
+	return "file_" + fileVarRe.ReplaceAllString(protoPath, "_") + "_" + name
+}
+
+// runtimeName returns the name of the runtime helper generated once per file and shared by its services.
+func runtimeName(file protoreflect.FileDescriptor, name string) string {
+	return fileVarName(file.Path(), name)
+}
+
 func toCamelCase(str string) string {
 	return toCamelCaseRe.ReplaceAllStringFunc(str, func(s string) string {
 		return strings.ToUpper(strings.Replace(s, "_", "", -1))
@@ -19,9 +33,13 @@ func toCamelCase(str string) string {
 }
 
 type pathParam struct {
-	Index  int
+	// Value is the Go expression reading the path parameter from the path segments "p",
+	// used when the request is not routed by http.ServeMux.
+	Value  string
 	Name   string
 	GoName string
+	// Elems is the elements of http.ServeMux pattern matching the path parameter.
+	Elems []string
 }
 
 func (t *pathParam) GetSplitedGoNames() []string {
@@ -48,15 +66,43 @@ func parsePathParam(pattern string) ([]*pathParam, error) {
 		return nil, err
 	}
 
+	// The segments after ** are indexed from the end of the path.
+	rest := 0
+	for _, seg := range segs {
+		if w := segmentWidth(seg); w > 0 {
+			rest += w
+		}
+	}
+	start, deep := 1, false
+
 	params := make([]*pathParam, 0)
-	for i, seg := range segs {
+	for _, seg := range segs {
+		width := segmentWidth(seg)
+		if width < 0 {
+			width, deep = 0, true
+		} else {
+			rest -= width
+		}
 		if v, ok := seg.(variable); ok {
+			from, to := fmt.Sprint(start), fmt.Sprint(start+width)
+			if deep {
+				from, to = fromEnd(rest+width), fromEnd(rest)
+				if width == 0 {
+					from = fmt.Sprint(start)
+				}
+			}
+			value := fmt.Sprintf("strings.Join(p[%s:%s], \"/\")", from, to)
+			if width == 1 {
+				value = fmt.Sprintf("p[%s]", from)
+			}
 			params = append(params, &pathParam{
-				Index:  i + 1,
+				Value:  value,
 				Name:   v.path,
 				GoName: toCamelCase(v.path),
+				Elems:  variableElems(v),
 			})
 		}
+		start += width
 	}
 
 	sort.Slice(params, func(i, j int) bool {
@@ -106,3 +152,157 @@ func createQueryParams(method *protogen.Method) []*queryParam {
 
 	return queryParams
 }
+
+// bodyField returns the field of the request message selected by body of google.api.HttpRule.
+// It returns nil if body is "*" or empty.
+func bodyField(method *protogen.Method, rule *annotations.HttpRule) (*protogen.Field, error) {
+	body := rule.GetBody()
+	if body == "" || body == "*" {
+		return nil, nil
+	}
+	for _, field := range method.Input.Fields {
+		if string(field.Desc.Name()) == body {
+			return field, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: body field %q is not a field of %s", method.Desc.FullName(), body, method.Input.Desc.FullName())
+}
+
+// isSingularMessage reports whether the field is a message field which is neither repeated nor map.
+func isSingularMessage(field *protogen.Field) bool {
+	return field.Message != nil && !field.Desc.IsList() && !field.Desc.IsMap()
+}
+
+// ruleQueryParams returns the query parameters of google.api.HttpRule,
+// which are the fields of the request message bound to neither the path nor the body.
+func ruleQueryParams(method *protogen.Method, rule *annotations.HttpRule, pathParams []*pathParam) []*queryParam {
+	body := rule.GetBody()
+	if _, ok := rule.GetPattern().(*annotations.HttpRule_Get); ok {
+		body = ""
+	}
+	if body == "*" {
+		return nil
+	}
+
+	params := make([]*queryParam, 0)
+Query:
+	for _, q := range createQueryParams(method) {
+		if body != "" && (q.Name == body || strings.HasPrefix(q.Name, body+".")) {
+			continue
+		}
+		for _, p := range pathParams {
+			if q.Name == p.Name {
+				continue Query
+			}
+		}
+		params = append(params, q)
+	}
+	return params
+}
+
+// fromEnd returns the Go expression of the index of the path segments "p" counted from the end.
+func fromEnd(n int) string {
+	if n == 0 {
+		return "len(p)"
+	}
+	return fmt.Sprintf("len(p)-%d", n)
+}
+
+// segmentWidth returns the number of path segments matched by the segment of path template,
+// or -1 if it matches any number of segments.
+func segmentWidth(seg segment) int {
+	switch s := seg.(type) {
+	case deepWildcard:
+		return -1
+	case variable:
+		n := 0
+		for _, seg := range s.segments {
+			w := segmentWidth(seg)
+			if w < 0 {
+				return -1
+			}
+			n += w
+		}
+		return n
+	}
+	return 1
+}
+
+// wildcardName returns the name of http.ServeMux wildcard for the field path of path parameter.
+func wildcardName(path string) string {
+	return strings.Replace(path, ".", "_", -1)
+}
+
+// variableElems returns the elements of http.ServeMux pattern matching the variable of path template.
+// A variable matching a single segment or the rest of the path is a wildcard named after its field path.
+// Otherwise the literal segments of the variable are kept and each wildcard is named after the field path
+// and its position, i.e., {name=shelves/*} is converted to shelves/{name_1}.
+func variableElems(v variable) []string {
+	name := wildcardName(v.path)
+	if len(v.segments) == 1 {
+		switch v.segments[0].(type) {
+		case wildcard:
+			return []string{"{" + name + "}"}
+		case deepWildcard:
+			return []string{"{" + name + "...}"}
+		}
+	}
+
+	elems := make([]string, 0, len(v.segments))
+	n := 0
+	for _, seg := range v.segments {
+		switch s := seg.(type) {
+		case literal:
+			elems = append(elems, string(s))
+		case wildcard:
+			n++
+			elems = append(elems, fmt.Sprintf("{%s_%d}", name, n))
+		case deepWildcard:
+			n++
+			elems = append(elems, fmt.Sprintf("{%s_%d...}", name, n))
+		}
+	}
+	return elems
+}
+
+// serveMuxPattern converts the path template of google.api.HttpRule to the pattern of http.ServeMux.
+func serveMuxPattern(httpMethod, pattern string) (string, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return "", fmt.Errorf("no leading /")
+	}
+	tokens, verb := tokenize(pattern[1:])
+
+	p := parser{tokens: tokens}
+	segs, err := p.topLevelSegments()
+	if err != nil {
+		return "", err
+	}
+
+	elems := make([]string, 0, len(segs))
+	for i, seg := range segs {
+		switch s := seg.(type) {
+		case literal:
+			elems = append(elems, string(s))
+		case wildcard:
+			elems = append(elems, fmt.Sprintf("{_%d}", i+1))
+		case deepWildcard:
+			elems = append(elems, fmt.Sprintf("{_%d...}", i+1))
+		case variable:
+			elems = append(elems, variableElems(s)...)
+		}
+	}
+	for i, elem := range elems {
+		if strings.HasSuffix(elem, "...}") && i != len(elems)-1 {
+			return "", fmt.Errorf("** must be the last segment: %s", pattern)
+		}
+	}
+
+	if verb != "" {
+		if _, ok := segs[len(segs)-1].(literal); !ok {
+			return "", fmt.Errorf("verb after variable is not supported: %s", pattern)
+		}
+		elems[len(elems)-1] += ":" + verb
+	}
+
+	return httpMethod + " /" + strings.Join(elems, "/"), nil
+}
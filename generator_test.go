@@ -0,0 +1,150 @@
+package main
+
+import "testing"
+
+func TestServeMuxPattern(t *testing.T) {
+	for _, spec := range []struct {
+		method  string
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{
+			method:  "GET",
+			pattern: "/v1/messages",
+			want:    "GET /v1/messages",
+		},
+		{
+			method:  "GET",
+			pattern: "/v1/messages/{message_id}",
+			want:    "GET /v1/messages/{message_id}",
+		},
+		{
+			method:  "POST",
+			pattern: "/v1/messages/{message_id}/{sub.subfield}",
+			want:    "POST /v1/messages/{message_id}/{sub_subfield}",
+		},
+		{
+			method:  "GET",
+			pattern: "/v1/{name=shelves/*}",
+			want:    "GET /v1/shelves/{name_1}",
+		},
+		{
+			method:  "GET",
+			pattern: "/v1/{name=shelves/*/books/*}",
+			want:    "GET /v1/shelves/{name_1}/books/{name_2}",
+		},
+		{
+			method:  "GET",
+			pattern: "/v1/{name=shelves/*}/books",
+			want:    "GET /v1/shelves/{name_1}/books",
+		},
+		{
+			method:  "GET",
+			pattern: "/v1/{name=shelves/**}",
+			want:    "GET /v1/shelves/{name_1...}",
+		},
+		{
+			method:  "GET",
+			pattern: "/v1/{name=**}",
+			want:    "GET /v1/{name...}",
+		},
+		{
+			method:  "GET",
+			pattern: "/v1/*/messages/**",
+			want:    "GET /v1/{_2}/messages/{_4...}",
+		},
+		{
+			method:  "POST",
+			pattern: "/v1/messages:batchGet",
+			want:    "POST /v1/messages:batchGet",
+		},
+		{
+			method:  "GET",
+			pattern: "/v1/{name=shelves/**}/books",
+			wantErr: true,
+		},
+		{
+			method:  "POST",
+			pattern: "/v1/messages/{message_id}:cancel",
+			wantErr: true,
+		},
+		{
+			method:  "GET",
+			pattern: "v1/messages",
+			wantErr: true,
+		},
+	} {
+		got, err := serveMuxPattern(spec.method, spec.pattern)
+		if spec.wantErr {
+			if err == nil {
+				t.Errorf("serveMuxPattern(%q, %q) succeeded; want failure", spec.method, spec.pattern)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("serveMuxPattern(%q, %q) failed with %v; want success", spec.method, spec.pattern, err)
+			continue
+		}
+		if got != spec.want {
+			t.Errorf("serveMuxPattern(%q, %q) = %q; want %q", spec.method, spec.pattern, got, spec.want)
+		}
+	}
+}
+
+func TestParsePathParamValue(t *testing.T) {
+	for _, spec := range []struct {
+		pattern string
+		want    map[string]string
+	}{
+		{
+			pattern: "/v1/messages/{message_id}",
+			want:    map[string]string{"message_id": "p[3]"},
+		},
+		{
+			pattern: "/v1/messages/{message_id}:cancel",
+			want:    map[string]string{"message_id": "p[3]"},
+		},
+		{
+			pattern: "/v1/{name=shelves/*}/books/{book}",
+			want: map[string]string{
+				"name": `strings.Join(p[2:4], "/")`,
+				"book": "p[5]",
+			},
+		},
+		{
+			pattern: "/v1/{name=shelves/**}",
+			want:    map[string]string{"name": `strings.Join(p[2:len(p)], "/")`},
+		},
+		{
+			pattern: "/v1/{name=shelves/**}/books/{book}",
+			want: map[string]string{
+				"name": `strings.Join(p[2:len(p)-2], "/")`,
+				"book": "p[len(p)-1]",
+			},
+		},
+		{
+			pattern: "/v1/**/{name=books/*}",
+			want:    map[string]string{"name": `strings.Join(p[len(p)-2:len(p)], "/")`},
+		},
+	} {
+		params, err := parsePathParam(spec.pattern)
+		if err != nil {
+			t.Errorf("parsePathParam(%q) failed with %v; want success", spec.pattern, err)
+			continue
+		}
+		got := make(map[string]string, len(params))
+		for _, p := range params {
+			got[p.Name] = p.Value
+		}
+		if len(got) != len(spec.want) {
+			t.Errorf("parsePathParam(%q) = %v; want %v", spec.pattern, got, spec.want)
+			continue
+		}
+		for name, want := range spec.want {
+			if got[name] != want {
+				t.Errorf("parsePathParam(%q) value of %s = %q; want %q", spec.pattern, name, got[name], want)
+			}
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
 	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
@@ -9,23 +14,43 @@ import (
 )
 
 var (
+	bufioPackage   = protogen.GoImportPath("bufio")
 	bytesPackage   = protogen.GoImportPath("bytes")
+	gzipPackage    = protogen.GoImportPath("compress/gzip")
+	zlibPackage    = protogen.GoImportPath("compress/zlib")
 	contextPackage = protogen.GoImportPath("context")
+	sha1Package    = protogen.GoImportPath("crypto/sha1")
+	embedPackage   = protogen.GoImportPath("embed")
 	base64Package  = protogen.GoImportPath("encoding/base64")
+	binaryPackage  = protogen.GoImportPath("encoding/binary")
+	errorsPackage  = protogen.GoImportPath("errors")
+	jsonPackage    = protogen.GoImportPath("encoding/json")
 	fmtPackage     = protogen.GoImportPath("fmt")
 	ioPackage      = protogen.GoImportPath("io")
 	ioutilPackage  = protogen.GoImportPath("io/ioutil")
+	slogPackage    = protogen.GoImportPath("log/slog")
+	mathPackage    = protogen.GoImportPath("math")
 	mimePackage    = protogen.GoImportPath("mime")
+	netPackage     = protogen.GoImportPath("net")
 	httpPackage    = protogen.GoImportPath("net/http")
+	urlPackage     = protogen.GoImportPath("net/url")
 	strconvPackage = protogen.GoImportPath("strconv")
 	stringsPackage = protogen.GoImportPath("strings")
 	reflectPackage = protogen.GoImportPath("reflect")
+	debugPackage   = protogen.GoImportPath("runtime/debug")
+	syncPackage    = protogen.GoImportPath("sync")
+	timePackage    = protogen.GoImportPath("time")
 )
 
 var (
 	protoPackage           = protogen.GoImportPath("google.golang.org/protobuf/proto")
 	protojsonPackage       = protogen.GoImportPath("google.golang.org/protobuf/encoding/protojson")
+	protowirePackage       = protogen.GoImportPath("google.golang.org/protobuf/encoding/protowire")
+	protoreflectPackage    = protogen.GoImportPath("google.golang.org/protobuf/reflect/protoreflect")
 	grpcPackage            = protogen.GoImportPath("google.golang.org/grpc")
+	metadataPackage        = protogen.GoImportPath("google.golang.org/grpc/metadata")
+	peerPackage            = protogen.GoImportPath("google.golang.org/grpc/peer")
+	credentialsPackage     = protogen.GoImportPath("google.golang.org/grpc/credentials")
 	codesPackage           = protogen.GoImportPath("google.golang.org/grpc/codes")
 	statusPackage          = protogen.GoImportPath("google.golang.org/grpc/status")
 	anypbPackage           = protogen.GoImportPath("google.golang.org/protobuf/types/known/anypb")
@@ -41,17 +66,7 @@ var (
 )
 
 func GenerateFile(gen *protogen.Plugin, file *protogen.File) (*protogen.GeneratedFile, error) {
-	isGenerated := false
-	for _, srv := range file.Services {
-		for _, method := range srv.Methods {
-			if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
-				continue
-			}
-			isGenerated = true
-		}
-	}
-
-	if !isGenerated {
+	if !hasGeneratedMethod(file) {
 		return nil, nil
 	}
 
@@ -64,21 +79,72 @@ func GenerateFile(gen *protogen.Plugin, file *protogen.File) (*protogen.Generate
 	g.P("package ", file.GoPackageName)
 
 	for _, srv := range file.Services {
-		if err := genService(g, srv); err != nil {
+		if err := genService(g, file, srv); err != nil {
 			return nil, err
 		}
 	}
+	genRuntime(g, file)
+
+	if *openapi {
+		g.P()
+		genOpenAPIEmbed(g, file)
+	}
 
 	return g, nil
 }
 
-func genService(g *protogen.GeneratedFile, srv *protogen.Service) error {
+// hasGeneratedMethod reports whether the file has a method implemented by the converter.
+func hasGeneratedMethod(file *protogen.File) bool {
+	for _, srv := range file.Services {
+		for _, method := range srv.Methods {
+			if isGeneratedMethod(method) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isGeneratedMethod reports whether the converter implements the method.
+// Bidirectional streaming methods are implemented only if the websocket option is enabled.
+func isGeneratedMethod(method *protogen.Method) bool {
+	return !isBidiStreaming(method) || *websocket
+}
+
+// isBidiStreaming reports whether the method is bidirectional streaming.
+func isBidiStreaming(method *protogen.Method) bool {
+	return method.Desc.IsStreamingClient() && method.Desc.IsStreamingServer()
+}
+
+// hasStreamingMethod reports whether the service has streaming methods converted by the converter.
+func hasStreamingMethod(srv *protogen.Service) bool {
+	for _, method := range srv.Methods {
+		if isGeneratedMethod(method) && isStreaming(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStreaming reports whether the method is client-streaming or server-streaming.
+func isStreaming(method *protogen.Method) bool {
+	return method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer()
+}
+
+func genService(g *protogen.GeneratedFile, file *protogen.File, srv *protogen.Service) error {
 	genServiceInterface(g, srv)
+	genTracerInterface(g, srv)
+	genObserverInterface(g, srv)
 	genStruct(g, srv)
+	genOptions(g, srv)
 	genConstructor(g, srv)
+	genTracer(g, file, srv)
+	genAccessLog(g, srv)
+	genRecovery(g, srv)
+	genMethodStreams(g, srv)
 
 	for _, method := range srv.Methods {
-		if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+		if !isGeneratedMethod(method) {
 			continue
 		}
 
@@ -89,9 +155,61 @@ func genService(g *protogen.GeneratedFile, srv *protogen.Service) error {
 		}
 	}
 
+	genRegister(g, srv)
+	genRoutes(g, srv)
+	if *openapi {
+		g.P()
+		genOpenAPIHandler(g, srv)
+	}
+
+	if err := genClient(g, srv); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// genRuntime generates the helpers shared by the converters of all services in the file.
+func genRuntime(g *protogen.GeneratedFile, file *protogen.File) {
+	genIncomingMetadata(g, file)
+	genTransportStream(g, file)
+	genTimeout(g, file)
+	genRequestPeer(g, file)
+	genRequestBody(g, file)
+	genResponseWriter(g, file)
+	genRedact(g, file)
+
+	// Request bodies are decoded by every method but bidirectional streaming ones, response bodies are compressed
+	// by unary and client-streaming methods, and the server stream is used by client and server-streaming methods.
+	decodes, compresses, streams := false, false, false
+	for _, srv := range file.Services {
+		for _, method := range srv.Methods {
+			if !isGeneratedMethod(method) || isBidiStreaming(method) {
+				continue
+			}
+			decodes = true
+			if !method.Desc.IsStreamingServer() {
+				compresses = true
+			}
+			if isStreaming(method) {
+				streams = true
+			}
+		}
+	}
+	if decodes {
+		genDecodeBodyEncoding(g, file)
+	}
+	if compresses {
+		genCompression(g, file)
+	}
+	if streams {
+		genServerStream(g, file)
+	}
+	if hasWebSocketFile(file) {
+		genWebSocketStream(g, file)
+	}
+}
+
 func callbackSignature(g *protogen.GeneratedFile) string {
 	return "func(ctx " +
 		g.QualifiedGoIdent(contextPackage.Ident("Context")) +
@@ -104,18 +222,49 @@ func callbackSignature(g *protogen.GeneratedFile) string {
 		", err error)"
 }
 
+// interceptorType returns grpc.StreamServerInterceptor for streaming methods, otherwise grpc.UnaryServerInterceptor.
+func interceptorType(method *protogen.Method) protogen.GoIdent {
+	if isStreaming(method) {
+		return grpcPackage.Ident("StreamServerInterceptor")
+	}
+	return grpcPackage.Ident("UnaryServerInterceptor")
+}
+
 func methodSignature(g *protogen.GeneratedFile, method *protogen.Method, prefix string) string {
 	return "func (h *" + method.Parent.GoName + "HTTPConverter) " +
 		method.GoName + prefix + "(cb " + callbackSignature(g) +
-		", interceptors ..." + g.QualifiedGoIdent(grpcPackage.Ident("UnaryServerInterceptor")) + ") "
+		", interceptors ..." + g.QualifiedGoIdent(interceptorType(method)) + ") "
 }
 
-func genDefaultCallback(g *protogen.GeneratedFile) {
+func genDefaultCallback(g *protogen.GeneratedFile, method *protogen.Method) {
+	g.P("if cb == nil {")
+	g.P("	cb = h.cb")
+	g.P("}")
 	g.P("if cb == nil {")
 	g.P("	cb = ", callbackSignature(g), " {")
 	g.P("		if err != nil {")
-	g.P("			w.WriteHeader(", httpPackage.Ident("StatusInternalServerError"), ")")
-	g.P("			p := ", statusPackage.Ident("New"), "(", codesPackage.Ident("Unknown"), ", err.Error()).Proto()")
+	if method.Desc.IsStreamingServer() && !method.Desc.IsStreamingClient() {
+		g.P("			// The stream has already ended with the status.")
+		g.P("			if _, ok := w.Header()[", httpPackage.Ident("TrailerPrefix"), "+\"Grpc-Status\"]; ok {")
+		g.P("				return")
+		g.P("			}")
+	}
+	g.P("			st := ", statusPackage.Ident("Convert"), "(err)")
+	g.P("			code := st.Code()")
+	g.P("			var maxBytesErr *", httpPackage.Ident("MaxBytesError"))
+	g.P("			switch {")
+	g.P("			case ", errorsPackage.Ident("As"), "(err, &maxBytesErr):")
+	g.P("				code = ", codesPackage.Ident("ResourceExhausted"))
+	g.P("				w.WriteHeader(", httpPackage.Ident("StatusRequestEntityTooLarge"), ")")
+	g.P("			case ", errorsPackage.Ident("Is"), "(err, ", contextPackage.Ident("DeadlineExceeded"), "), code == ", codesPackage.Ident("DeadlineExceeded"), ":")
+	g.P("				code = ", codesPackage.Ident("DeadlineExceeded"))
+	g.P("				w.WriteHeader(", httpPackage.Ident("StatusGatewayTimeout"), ")")
+	g.P("			case code == ", codesPackage.Ident("InvalidArgument"), ":")
+	g.P("				w.WriteHeader(", httpPackage.Ident("StatusBadRequest"), ")")
+	g.P("			default:")
+	g.P("				w.WriteHeader(", httpPackage.Ident("StatusInternalServerError"), ")")
+	g.P("			}")
+	g.P("			p := ", statusPackage.Ident("New"), "(code, st.Message()).Proto()")
 	g.P("			switch contentType, _, _ := ", mimePackage.Ident("ParseMediaType"), "(r.Header.Get(\"Content-Type\")); contentType {")
 	g.P("				case \"application/protobuf\", \"application/x-protobuf\":")
 	g.P("					buf, err := ", protoPackage.Ident("Marshal"), "(p)")
@@ -140,12 +289,29 @@ func genDefaultCallback(g *protogen.GeneratedFile) {
 	g.P("}")
 }
 
+func genDefaultInterceptors(g *protogen.GeneratedFile, method *protogen.Method) {
+	if isStreaming(method) {
+		g.P("interceptors = append(append([]", grpcPackage.Ident("StreamServerInterceptor"), "{}, h.streamInterceptors...), interceptors...)")
+	} else {
+		g.P("interceptors = append(append([]", grpcPackage.Ident("UnaryServerInterceptor"), "{}, h.interceptors...), interceptors...)")
+	}
+	genRecoveryInterceptors(g, method)
+}
+
 func genServiceInterface(g *protogen.GeneratedFile, srv *protogen.Service) {
 	g.P("// ", srv.GoName, "HTTPService is the server API for ", srv.GoName, " service.")
 	g.P("type ", srv.GoName, "HTTPService interface {")
 
 	for _, method := range srv.Methods {
-		if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+		if !isGeneratedMethod(method) {
+			continue
+		}
+		if method.Desc.IsStreamingClient() {
+			g.P(method.Comments.Leading, method.GoName, "(", method.Parent.GoName, "_", method.GoName, "Server) error")
+			continue
+		}
+		if method.Desc.IsStreamingServer() {
+			g.P(method.Comments.Leading, method.GoName, "(*", genMessageName(method.Input), ", ", method.Parent.GoName, "_", method.GoName, "Server) error")
 			continue
 		}
 		g.P(method.Comments.Leading, method.GoName, "(", contextPackage.Ident("Context"), ", *", genMessageName(method.Input), ") (*", genMessageName(method.Output), ", error)")
@@ -156,17 +322,337 @@ func genServiceInterface(g *protogen.GeneratedFile, srv *protogen.Service) {
 func genStruct(g *protogen.GeneratedFile, srv *protogen.Service) {
 	g.P("// ", srv.GoName, "HTTPConverter has a function to convert ", srv.GoName, "HTTPService interface to http.HandlerFunc.")
 	g.P("type ", srv.GoName, "HTTPConverter struct {")
-	g.P("srv ", srv.GoName, "HTTPService")
+	g.P("srv          ", srv.GoName, "HTTPService")
+	g.P("cb           ", callbackSignature(g))
+	g.P("interceptors []", grpcPackage.Ident("UnaryServerInterceptor"))
+	if hasStreamingMethod(srv) {
+		g.P("streamInterceptors []", grpcPackage.Ident("StreamServerInterceptor"))
+	}
+	g.P("maxBodySize int64")
+	g.P("skipMethodCheck bool")
+	g.P("incomingHeaders []string")
+	g.P("incomingHeaderPrefix string")
+	g.P("outgoingHeaders []string")
+	g.P("outgoingHeaderPrefix string")
+	g.P("outgoingTrailerPrefix string")
+	g.P("timeoutHeader string")
+	g.P("tracer ", srv.GoName, "HTTPTracer")
+	g.P("observer ", srv.GoName, "HTTPObserver")
+	g.P("accessLogger *", slogPackage.Ident("Logger"))
+	g.P("accessLogPayloads bool")
+	g.P("recovery bool")
+	g.P("panicHandler ", panicHandlerSignature(g))
+	g.P("compressionMinSize int")
+	if hasWebSocketMethod(srv) {
+		g.P("webSocketOrigins []string")
+	}
+	g.P("}")
+}
+
+func genOptions(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// ", srv.GoName, "HTTPConverterOption configures ", srv.GoName, "HTTPConverter.")
+	g.P("type ", srv.GoName, "HTTPConverterOption func(*", srv.GoName, "HTTPConverter)")
+	g.P()
+	g.P("// With", srv.GoName, "HTTPCallback sets the callback used when nil is passed to a convert method.")
+	g.P("func With", srv.GoName, "HTTPCallback(cb ", callbackSignature(g), ") ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.cb = cb")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", srv.GoName, "HTTPInterceptors appends interceptors executed before the interceptors passed to a convert method.")
+	g.P("func With", srv.GoName, "HTTPInterceptors(interceptors ...", grpcPackage.Ident("UnaryServerInterceptor"), ") ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.interceptors = append(h.interceptors, interceptors...)")
+	g.P("	}")
+	g.P("}")
+	if hasStreamingMethod(srv) {
+		g.P()
+		g.P("// With", srv.GoName, "HTTPStreamInterceptors appends stream interceptors executed before the interceptors passed to a convert method of streaming RPC.")
+		g.P("func With", srv.GoName, "HTTPStreamInterceptors(interceptors ...", grpcPackage.Ident("StreamServerInterceptor"), ") ", srv.GoName, "HTTPConverterOption {")
+		g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+		g.P("		h.streamInterceptors = append(h.streamInterceptors, interceptors...)")
+		g.P("	}")
+		g.P("}")
+	}
+	g.P()
+	g.P("// With", srv.GoName, "HTTPMaxBodySize sets the maximum size of request bodies in bytes.")
+	g.P("// The default is ", *maxBodySize, " bytes set by max_body_size option of protoc-gen-gohttp. Zero or a negative value means no limit.")
+	g.P("// Requests with larger bodies fail with *http.MaxBytesError, and the default callback responds 413 Request Entity Too Large.")
+	g.P("func With", srv.GoName, "HTTPMaxBodySize(n int64) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.maxBodySize = n")
+	g.P("	}")
 	g.P("}")
+	g.P()
+	g.P("// With", srv.GoName, "HTTPSkipMethodCheck sets whether the handlers returned by HTTPRule methods skip checking the request method.")
+	g.P("// By default, the handlers respond 405 Method Not Allowed with Allow header if the request method does not match google.api.http option.")
+	g.P("// Routers that already route requests by the method can skip the check.")
+	g.P("func With", srv.GoName, "HTTPSkipMethodCheck(skip bool) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.skipMethodCheck = skip")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	genIncomingMetadataOptions(g, srv)
+	g.P()
+	genOutgoingMetadataOptions(g, srv)
+	g.P()
+	genTimeoutOptions(g, srv)
+	g.P()
+	genTracerOptions(g, srv)
+	g.P()
+	genAccessLogOptions(g, srv)
+	g.P()
+	genRecoveryOptions(g, srv)
+	g.P()
+	genCompressionOptions(g, srv)
+	if hasWebSocketMethod(srv) {
+		g.P()
+		genWebSocketOptions(g, srv)
+	}
 }
 
 func genConstructor(g *protogen.GeneratedFile, srv *protogen.Service) {
 	g.P("// New", srv.GoName, "HTTPConverter returns ", srv.GoName, "HTTPConverter.")
-	g.P("func New", srv.GoName, "HTTPConverter(srv ", srv.GoName, "HTTPService) *", srv.GoName, "HTTPConverter {")
-	g.P("	return &", srv.GoName, "HTTPConverter{")
-	g.P("		srv: srv,")
+	g.P("func New", srv.GoName, "HTTPConverter(srv ", srv.GoName, "HTTPService, opts ...", srv.GoName, "HTTPConverterOption) *", srv.GoName, "HTTPConverter {")
+	g.P("	h := &", srv.GoName, "HTTPConverter{")
+	g.P("		srv:                  srv,")
+	g.P("		maxBodySize:          ", *maxBodySize, ",")
+	g.P("		incomingHeaders:      []string{\"Authorization\"},")
+	g.P("		incomingHeaderPrefix: \"Grpc-Metadata-\",")
+	g.P("		outgoingHeaders:       []string{\"Location\"},")
+	g.P("		outgoingHeaderPrefix:  \"Grpc-Metadata-\",")
+	g.P("		outgoingTrailerPrefix: \"Grpc-Trailer-\",")
+	g.P("		compressionMinSize:    -1,")
+	g.P("	}")
+	g.P("	for _, opt := range opts {")
+	g.P("		opt(h)")
+	g.P("	}")
+	g.P("	return h")
+	g.P("}")
+}
+
+func unexport(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// serverStreamName returns the name of grpc.ServerStream implementation shared by the services of the file.
+func serverStreamName(file protoreflect.FileDescriptor) string {
+	return runtimeName(file, "httpServerStream")
+}
+
+// methodStreamName returns the name of the stream passed to the streaming method.
+func methodStreamName(method *protogen.Method) string {
+	return unexport(method.Parent.GoName) + method.GoName + "HTTPServer"
+}
+
+func genServerStream(g *protogen.GeneratedFile, file *protogen.File) {
+	name := serverStreamName(file.Desc)
+	g.P("// ", name, " implements grpc.ServerStream on HTTP.")
+	g.P("// Messages are read from the request body as newline-delimited JSON or varint length-prefixed protobuf,")
+	g.P("// and written as newline-delimited JSON, varint length-prefixed protobuf or Server-Sent Events.")
+	g.P("type ", name, " struct {")
+	g.P("	ctx         ", contextPackage.Ident("Context"))
+	g.P("	cancel      ", contextPackage.Ident("CancelFunc"))
+	g.P("	w           ", httpPackage.Ident("ResponseWriter"))
+	g.P("	body        *", bufioPackage.Ident("Reader"))
+	g.P("	contentType string")
+	g.P("	accept      string")
+	g.P()
+	g.P("	// clientStream is true if the RPC is client-streaming.")
+	g.P("	// The response of client-streaming RPC is held in ret and written after the method returns.")
+	g.P("	clientStream bool")
+	g.P("	ret          ", protoPackage.Ident("Message"))
+	g.P()
+	g.P("	ts    *", transportStreamName(file.Desc))
+	g.P("	wrote bool")
+	g.P("}")
+	g.P()
+	g.P("func (s *", name, ") SetHeader(md ", metadataPackage.Ident("MD"), ") error {")
+	g.P("	return s.ts.SetHeader(md)")
+	g.P("}")
+	g.P()
+	g.P("func (s *", name, ") SendHeader(md ", metadataPackage.Ident("MD"), ") error {")
+	g.P("	return s.ts.SendHeader(md)")
+	g.P("}")
+	g.P()
+	g.P("func (s *", name, ") SetTrailer(md ", metadataPackage.Ident("MD"), ") {")
+	g.P("	_ = s.ts.SetTrailer(md)")
+	g.P("}")
+	g.P()
+	g.P("func (s *", name, ") Context() ", contextPackage.Ident("Context"), " {")
+	g.P("	return s.ctx")
+	g.P("}")
+	g.P()
+	g.P("func (s *", name, ") SendMsg(m interface{}) error {")
+	g.P("	msg, ok := m.(", protoPackage.Ident("Message"), ")")
+	g.P("	if !ok {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), "(\"%T is not proto.Message\", m)")
+	g.P("	}")
+	g.P()
+	g.P("	if s.clientStream {")
+	g.P("		if s.ret != nil {")
+	g.P("			return ", fmtPackage.Ident("Errorf"), "(\"the response has already been sent\")")
+	g.P("		}")
+	g.P("		s.ret = msg")
+	g.P("		return nil")
+	g.P("	}")
+	g.P()
+	g.P("	var buf []byte")
+	g.P("	switch s.accept {")
+	g.P("	case \"application/protobuf\", \"application/x-protobuf\":")
+	g.P("		b, err := ", protoPackage.Ident("Marshal"), "(msg)")
+	g.P("		if err != nil {")
+	g.P("			return err")
+	g.P("		}")
+	g.P("		buf = append(", protowirePackage.Ident("AppendVarint"), "(nil, uint64(len(b))), b...)")
+	g.P("	case \"text/event-stream\":")
+	g.P("		b, err := ", protojsonPackage.Ident("Marshal"), "(msg)")
+	g.P("		if err != nil {")
+	g.P("			return err")
+	g.P("		}")
+	g.P("		buf = append(append([]byte(\"data: \"), b...), '\\n', '\\n')")
+	g.P("	default:")
+	g.P("		b, err := ", protojsonPackage.Ident("Marshal"), "(msg)")
+	g.P("		if err != nil {")
+	g.P("			return err")
+	g.P("		}")
+	g.P("		buf = append(b, '\\n')")
+	g.P("	}")
+	g.P()
+	g.P("	return s.write(buf)")
+	g.P("}")
+	g.P()
+	g.P("// RecvMsg reads the next message from the request body. It returns io.EOF at the end of the body.")
+	g.P("// Server-streaming RPC has no body to read because the request message is passed to the method as an argument.")
+	g.P("func (s *", name, ") RecvMsg(m interface{}) error {")
+	g.P("	msg, ok := m.(", protoPackage.Ident("Message"), ")")
+	g.P("	if !ok {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), "(\"%T is not proto.Message\", m)")
+	g.P("	}")
+	g.P("	if s.body == nil {")
+	g.P("		return ", ioPackage.Ident("EOF"))
+	g.P("	}")
+	g.P()
+	g.P("	switch s.contentType {")
+	g.P("	case \"application/protobuf\", \"application/x-protobuf\":")
+	g.P("		size, err := ", binaryPackage.Ident("ReadUvarint"), "(s.body)")
+	g.P("		if err != nil {")
+	g.P("			return err")
+	g.P("		}")
+	g.P("		buf, err := ", ioPackage.Ident("ReadAll"), "(", ioPackage.Ident("LimitReader"), "(s.body, int64(size)))")
+	g.P("		if err != nil {")
+	g.P("			return err")
+	g.P("		}")
+	g.P("		if uint64(len(buf)) != size {")
+	g.P("			return ", ioPackage.Ident("ErrUnexpectedEOF"))
+	g.P("		}")
+	g.P("		return ", protoPackage.Ident("Unmarshal"), "(buf, msg)")
+	g.P("	default:")
+	g.P("		for {")
+	g.P("			line, err := s.body.ReadBytes('\\n')")
+	g.P("			// The last line may not end with a newline, but the line is incomplete on other errors.")
+	g.P("			if err != nil && err != ", ioPackage.Ident("EOF"), " {")
+	g.P("				return err")
+	g.P("			}")
+	g.P("			if len(", bytesPackage.Ident("TrimSpace"), "(line)) != 0 {")
+	g.P("				return ", protojsonPackage.Ident("Unmarshal"), "(line, msg)")
+	g.P("			}")
+	g.P("			if err != nil {")
+	g.P("				return err")
+	g.P("			}")
+	g.P("		}")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// write writes buf and flushes it. The context is canceled if the client has gone away.")
+	g.P("func (s *", name, ") write(buf []byte) error {")
+	g.P("	s.ts.writeHeader()")
+	g.P("	s.wrote = true")
+	g.P("	if _, err := s.w.Write(buf); err != nil {")
+	g.P("		s.cancel()")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	if f, ok := s.w.(", httpPackage.Ident("Flusher"), "); ok {")
+	g.P("		f.Flush()")
+	g.P("	}")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// finish writes the status of the RPC after the messages so that the client can tell a failed stream from a complete one.")
+	g.P("// Server-Sent Events end with the status event, and newline-delimited JSON ends with an {\"error\": status} line")
+	g.P("// if the RPC fails. Every framing also ends with the Grpc-Status and Grpc-Message trailers once the response has started.")
+	g.P("// Before the response has started, the status is left to the callback.")
+	g.P("func (s *", name, ") finish(err error) {")
+	g.P("	st := ", statusPackage.Ident("Convert"), "(err)")
+	g.P("	switch {")
+	g.P("	case s.accept == \"text/event-stream\":")
+	g.P("		if b, merr := ", protojsonPackage.Ident("Marshal"), "(st.Proto()); merr == nil {")
+	g.P("			_ = s.write(append(append([]byte(\"event: status\\ndata: \"), b...), '\\n', '\\n'))")
+	g.P("		}")
+	g.P("	case !s.wrote:")
+	g.P("	case err != nil && s.accept != \"application/protobuf\" && s.accept != \"application/x-protobuf\":")
+	g.P("		if b, merr := ", protojsonPackage.Ident("Marshal"), "(st.Proto()); merr == nil {")
+	g.P("			_ = s.write(append(append([]byte(`{\"error\":`), b...), '}', '\\n'))")
+	g.P("		}")
+	g.P("	}")
+	g.P("	if !s.wrote {")
+	g.P("		return")
+	g.P("	}")
+	g.P("	s.w.Header().Set(", httpPackage.Ident("TrailerPrefix"), "+\"Grpc-Status\", ", strconvPackage.Ident("Itoa"), "(int(st.Code())))")
+	g.P("	if msg := st.Message(); msg != \"\" {")
+	g.P("		s.w.Header().Set(", httpPackage.Ident("TrailerPrefix"), "+\"Grpc-Message\", ", urlPackage.Ident("PathEscape"), "(msg))")
 	g.P("	}")
 	g.P("}")
+
+}
+
+// genMethodStreams generates the streams passed to the streaming methods.
+func genMethodStreams(g *protogen.GeneratedFile, srv *protogen.Service) {
+	for _, method := range srv.Methods {
+		if !isGeneratedMethod(method) || !isStreaming(method) {
+			continue
+		}
+		g.P()
+		g.P("type ", methodStreamName(method), " struct {")
+		g.P("	", grpcPackage.Ident("ServerStream"))
+		g.P("}")
+		if isBidiStreaming(method) {
+			g.P()
+			g.P("func (x *", methodStreamName(method), ") Send(m *", genMessageName(method.Output), ") error {")
+			g.P("	return x.ServerStream.SendMsg(m)")
+			g.P("}")
+			g.P()
+			g.P("func (x *", methodStreamName(method), ") Recv() (*", genMessageName(method.Input), ", error) {")
+			g.P("	m := new(", genMessageName(method.Input), ")")
+			g.P("	if err := x.ServerStream.RecvMsg(m); err != nil {")
+			g.P("		return nil, err")
+			g.P("	}")
+			g.P("	return m, nil")
+			g.P("}")
+			continue
+		}
+		if method.Desc.IsStreamingClient() {
+			g.P()
+			g.P("func (x *", methodStreamName(method), ") SendAndClose(m *", genMessageName(method.Output), ") error {")
+			g.P("	return x.ServerStream.SendMsg(m)")
+			g.P("}")
+			g.P()
+			g.P("func (x *", methodStreamName(method), ") Recv() (*", genMessageName(method.Input), ", error) {")
+			g.P("	m := new(", genMessageName(method.Input), ")")
+			g.P("	if err := x.ServerStream.RecvMsg(m); err != nil {")
+			g.P("		return nil, err")
+			g.P("	}")
+			g.P("	return m, nil")
+			g.P("}")
+			continue
+		}
+		g.P()
+		g.P("func (x *", methodStreamName(method), ") Send(m *", genMessageName(method.Output), ") error {")
+		g.P("	return x.ServerStream.SendMsg(m)")
+		g.P("}")
+	}
 }
 
 func genMethod(g *protogen.GeneratedFile, method *protogen.Method) {
@@ -175,9 +661,28 @@ func genMethod(g *protogen.GeneratedFile, method *protogen.Method) {
 		g.P("//")
 	}
 	g.P(method.Comments.Leading, methodSignature(g, method, ""), httpPackage.Ident("HandlerFunc"), " {")
-	genDefaultCallback(g)
+	if isBidiStreaming(method) {
+		genWebSocketMethod(g, method)
+		return
+	}
+	genDefaultCallback(g, method)
+	genDefaultInterceptors(g, method)
 	g.P("	return ", httpPackage.Ident("HandlerFunc"), "(func(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ") {")
-	g.P("		ctx := r.Context()")
+	genNegotiation(g, method, "")
+	if !method.Desc.IsStreamingClient() {
+		g.P("		arg := &", genMessageName(method.Input), "{}")
+		g.P("		if r.Method != ", httpPackage.Ident("MethodGet"), " {")
+		genDecodeBody(g, method, nil)
+		g.P("		}")
+		g.P("")
+	}
+	genCall(g, method)
+	g.P("	})")
+	g.P("}")
+}
+
+func genNegotiation(g *protogen.GeneratedFile, method *protogen.Method, route string) {
+	genRequestContext(g, method, route)
 	g.P("")
 	g.P("		contentType, _, _ := ", mimePackage.Ident("ParseMediaType"), "(r.Header.Get(\"Content-Type\"))")
 	g.P("")
@@ -191,10 +696,41 @@ func genMethod(g *protogen.GeneratedFile, method *protogen.Method) {
 	g.P("			}")
 	g.P("		}")
 	g.P("")
-	g.P("		w.Header().Set(\"Content-Type\", accept)")
+	if method.Desc.IsStreamingServer() {
+		g.P("		switch accept {")
+		g.P("		case \"application/json\", \"application/x-ndjson\":")
+		g.P("			w.Header().Set(\"Content-Type\", \"application/x-ndjson\")")
+		g.P("		case \"text/event-stream\":")
+		g.P("			w.Header().Set(\"Content-Type\", \"text/event-stream\")")
+		g.P("			w.Header().Set(\"Cache-Control\", \"no-cache\")")
+		g.P("		default:")
+		g.P("			w.Header().Set(\"Content-Type\", accept)")
+		g.P("		}")
+	} else if method.Desc.IsStreamingClient() {
+		g.P("		if accept == \"application/x-ndjson\" {")
+		g.P("			accept = \"application/json\"")
+		g.P("		}")
+		g.P("		w.Header().Set(\"Content-Type\", accept)")
+	} else {
+		g.P("		w.Header().Set(\"Content-Type\", accept)")
+	}
 	g.P("")
-	g.P("		arg := &", genMessageName(method.Input), "{}")
-	g.P("		if r.Method != ", httpPackage.Ident("MethodGet"), " {")
+}
+
+// genLimitBody generates the code limiting the size of the request body.
+// Reading the body beyond the limit fails with *http.MaxBytesError.
+func genLimitBody(g *protogen.GeneratedFile) {
+	g.P("if h.maxBodySize > 0 {")
+	g.P("	r.Body = ", httpPackage.Ident("MaxBytesReader"), "(w, r.Body, h.maxBodySize)")
+	g.P("}")
+}
+
+// genDecodeBody generates the code decoding the request body into arg.
+// If field is not nil, the body is decoded into the field as body of google.api.HttpRule selects it.
+// The body of non-message fields is accepted only in JSON.
+func genDecodeBody(g *protogen.GeneratedFile, method *protogen.Method, field *protogen.Field) {
+	genDecodeContentEncoding(g, method.Parent.Desc.ParentFile())
+	genLimitBody(g)
 	g.P("			body, err := ", ioutilPackage.Ident("ReadAll"), "(r.Body)")
 	g.P("			if err != nil {")
 	g.P("				cb(ctx, w, r, nil, nil, err)")
@@ -202,12 +738,22 @@ func genMethod(g *protogen.GeneratedFile, method *protogen.Method) {
 	g.P("			}")
 	g.P("")
 	g.P("			switch contentType {")
-	g.P("			case \"application/protobuf\", \"application/x-protobuf\":")
-	g.P("				if err := ", protoPackage.Ident("Unmarshal"), "(body, arg); err != nil {")
-	g.P("					cb(ctx, w, r, nil, nil, err)")
-	g.P("					return")
-	g.P("				}")
+	if field == nil || isSingularMessage(field) {
+		g.P("			case \"application/protobuf\", \"application/x-protobuf\":")
+		if field != nil {
+			g.P("				// The body is the field ", field.Desc.Name(), ", which is decoded as the field of the request message.")
+			g.P("				body = ", protowirePackage.Ident("AppendBytes"), "(", protowirePackage.Ident("AppendTag"), "(nil, ", field.Desc.Number(), ", ", protowirePackage.Ident("BytesType"), "), body)")
+		}
+		g.P("				if err := ", protoPackage.Ident("Unmarshal"), "(body, arg); err != nil {")
+		g.P("					cb(ctx, w, r, nil, nil, err)")
+		g.P("					return")
+		g.P("				}")
+	}
 	g.P("			case \"application/json\":")
+	if field != nil {
+		g.P("				// The body is the field ", field.Desc.Name(), ", which is decoded as the field of the request message.")
+		g.P("				body = append(append([]byte(`{\"", field.Desc.JSONName(), "\":`), body...), '}')")
+	}
 	g.P("				if err := ", protojsonPackage.Ident("Unmarshal"), "(body, arg); err != nil {")
 	g.P("					cb(ctx, w, r, nil, nil, err)")
 	g.P("					return")
@@ -218,8 +764,21 @@ func genMethod(g *protogen.GeneratedFile, method *protogen.Method) {
 	g.P("				cb(ctx, w, r, nil, nil, err)")
 	g.P("				return")
 	g.P("			}")
-	g.P("		}")
-	g.P("")
+}
+
+func genCall(g *protogen.GeneratedFile, method *protogen.Method) {
+	if method.Desc.IsStreamingClient() {
+		genClientStreamCall(g, method)
+		return
+	}
+	if method.Desc.IsStreamingServer() {
+		genServerStreamCall(g, method)
+		return
+	}
+	genUnaryCall(g, method)
+}
+
+func genUnaryCall(g *protogen.GeneratedFile, method *protogen.Method) {
 	g.P("		n := len(interceptors)")
 	g.P("		chained := func(ctx ", contextPackage.Ident("Context"), ", arg interface{}, info *", grpcPackage.Ident("UnaryServerInfo"), ", handler ", grpcPackage.Ident("UnaryHandler"), ") (interface{}, error) {")
 	g.P("			chainer := func(currentInter ", grpcPackage.Ident("UnaryServerInterceptor"), ", currentHandler ", grpcPackage.Ident("UnaryHandler"), ") ", grpcPackage.Ident("UnaryHandler"), " {")
@@ -237,7 +796,7 @@ func genMethod(g *protogen.GeneratedFile, method *protogen.Method) {
 	g.P("")
 	g.P("		info := &", grpcPackage.Ident("UnaryServerInfo"), "{")
 	g.P("			Server:     h.srv,")
-	g.P("			FullMethod: \"/", method.Desc.ParentFile().Package(), ".", method.Parent.GoName, "/", method.GoName, "\",")
+	g.P("			FullMethod: \"", fullMethodName(method), "\",")
 	g.P("		}")
 	g.P("")
 	g.P("		handler := func(c ", contextPackage.Ident("Context"), ", req interface{}) (interface{}, error) {")
@@ -245,6 +804,8 @@ func genMethod(g *protogen.GeneratedFile, method *protogen.Method) {
 	g.P("		}")
 	g.P("")
 	g.P("		iret, err := chained(ctx, arg, info, handler)")
+	g.P("		ts.writeHeader()")
+	g.P("		ts.writeTrailer(false)")
 	g.P("		if err != nil {")
 	g.P("			cb(ctx, w, r, arg, nil, err)")
 	g.P("			return")
@@ -252,40 +813,158 @@ func genMethod(g *protogen.GeneratedFile, method *protogen.Method) {
 	g.P("")
 	g.P("		ret, ok := iret.(*", genMessageName(method.Output), ")")
 	g.P("		if !ok {")
-	g.P("			cb(ctx, w, r, arg, nil, fmt.Errorf(\"/", method.Desc.ParentFile().Package(), ".", method.Parent.GoName, "/", method.GoName, ": interceptors have not return ", genMessageName(method.Output), "\"))")
+	g.P("			cb(ctx, w, r, arg, nil, fmt.Errorf(\"", fullMethodName(method), ": interceptors have not return ", genMessageName(method.Output), "\"))")
 	g.P("			return")
 	g.P("		}")
 	g.P("")
+	genWriteResponse(g, method, "arg")
+}
+
+// genWriteResponse writes ret marshaled by accept and calls cb with arg.
+func genWriteResponse(g *protogen.GeneratedFile, method *protogen.Method, arg string) {
+	compress := runtimeName(method.Parent.Desc.ParentFile(), "compress")
 	g.P("		switch accept {")
 	g.P("		case \"application/protobuf\", \"application/x-protobuf\":")
 	g.P("			buf, err := ", protoPackage.Ident("Marshal"), "(ret)")
 	g.P("			if err != nil {")
-	g.P("				cb(ctx, w, r, arg, ret, err)")
+	g.P("				cb(ctx, w, r, ", arg, ", ret, err)")
+	g.P("				return")
+	g.P("			}")
+	g.P("			buf = ", compress, "(w, r, buf, h.compressionMinSize)")
+	g.P("			if !ts.writeStatusCode() {")
+	g.P("				cb(ctx, w, r, ", arg, ", ret, nil)")
 	g.P("				return")
 	g.P("			}")
 	g.P("			if _, err := ", ioPackage.Ident("Copy"), "(w, ", bytesPackage.Ident("NewBuffer"), "(buf)); err != nil {")
-	g.P("				cb(ctx, w, r, arg, ret, err)")
+	g.P("				cb(ctx, w, r, ", arg, ", ret, err)")
 	g.P("				return")
 	g.P("			}")
 	g.P("		case \"application/json\":")
 	g.P("			buf, err := ", protojsonPackage.Ident("Marshal"), "(ret)")
 	g.P("			if err != nil {")
-	g.P("				cb(ctx, w, r, arg, ret, err)")
+	g.P("				cb(ctx, w, r, ", arg, ", ret, err)")
+	g.P("				return")
+	g.P("			}")
+	g.P("			buf = ", compress, "(w, r, buf, h.compressionMinSize)")
+	g.P("			if !ts.writeStatusCode() {")
+	g.P("				cb(ctx, w, r, ", arg, ", ret, nil)")
 	g.P("				return")
 	g.P("			}")
 	g.P("			if _, err := ", ioPackage.Ident("Copy"), "(w, ", bytesPackage.Ident("NewBuffer"), "(buf)); err != nil {")
-	g.P("				cb(ctx, w, r, arg, ret, err)")
+	g.P("				cb(ctx, w, r, ", arg, ", ret, err)")
 	g.P("				return")
 	g.P("			}")
 	g.P("		default:")
 	g.P("			w.WriteHeader(", httpPackage.Ident("StatusUnsupportedMediaType"), ")")
 	g.P("			_, err := fmt.Fprintf(w, \"Unsupported Accept: %s\", accept)")
-	g.P("			cb(ctx, w, r, arg, ret, err)")
+	g.P("			cb(ctx, w, r, ", arg, ", ret, err)")
 	g.P("			return")
 	g.P("		}")
-	g.P("		cb(ctx, w, r, arg, ret, nil)")
-	g.P("	})")
-	g.P("}")
+	g.P("		cb(ctx, w, r, ", arg, ", ret, nil)")
+}
+
+func genClientStreamCall(g *protogen.GeneratedFile, method *protogen.Method) {
+	g.P("		switch contentType {")
+	g.P("		case \"application/protobuf\", \"application/x-protobuf\", \"application/json\", \"application/x-ndjson\":")
+	g.P("		default:")
+	g.P("			w.WriteHeader(", httpPackage.Ident("StatusUnsupportedMediaType"), ")")
+	g.P("			_, err := ", fmtPackage.Ident("Fprintf"), "(w, \"Unsupported Content-Type: %s\", contentType)")
+	g.P("			cb(ctx, w, r, nil, nil, err)")
+	g.P("			return")
+	g.P("		}")
+	g.P("")
+	genDecodeContentEncoding(g, method.Parent.Desc.ParentFile())
+	genLimitBody(g)
+	g.P("		ctx, cancel := ", contextPackage.Ident("WithCancel"), "(ctx)")
+	g.P("		defer cancel()")
+	g.P("")
+	g.P("		stream := &", serverStreamName(method.Parent.Desc.ParentFile()), "{")
+	g.P("			ctx:          ctx,")
+	g.P("			cancel:       cancel,")
+	g.P("			w:            w,")
+	g.P("			body:         ", bufioPackage.Ident("NewReader"), "(r.Body),")
+	g.P("			contentType:  contentType,")
+	g.P("			accept:       accept,")
+	g.P("			clientStream: true,")
+	g.P("			ts:           ts,")
+	g.P("		}")
+	genStreamChain(g, method, "h.srv."+method.GoName+"(&"+methodStreamName(method)+"{ss})")
+	g.P("		err := chained(h.srv, stream, info, handler)")
+	g.P("		ts.writeHeader()")
+	g.P("		ts.writeTrailer(false)")
+	g.P("		if err != nil {")
+	g.P("			cb(ctx, w, r, nil, nil, err)")
+	g.P("			return")
+	g.P("		}")
+	g.P("")
+	g.P("		ret, ok := stream.ret.(*", genMessageName(method.Output), ")")
+	g.P("		if !ok {")
+	g.P("			cb(ctx, w, r, nil, nil, ", fmtPackage.Ident("Errorf"), "(\"", fullMethodName(method), ": SendAndClose has not been called\"))")
+	g.P("			return")
+	g.P("		}")
+	g.P("")
+	genWriteResponse(g, method, "nil")
+}
+
+// genStreamChain generates chained stream interceptors and the handler calling the method by call.
+func genStreamChain(g *protogen.GeneratedFile, method *protogen.Method, call string) {
+	g.P("		n := len(interceptors)")
+	g.P("		chained := func(srv interface{}, ss ", grpcPackage.Ident("ServerStream"), ", info *", grpcPackage.Ident("StreamServerInfo"), ", handler ", grpcPackage.Ident("StreamHandler"), ") error {")
+	g.P("			chainer := func(currentInter ", grpcPackage.Ident("StreamServerInterceptor"), ", currentHandler ", grpcPackage.Ident("StreamHandler"), ") ", grpcPackage.Ident("StreamHandler"), " {")
+	g.P("				return func(currentSrv interface{}, currentStream ", grpcPackage.Ident("ServerStream"), ") error {")
+	g.P("					return currentInter(currentSrv, currentStream, info, currentHandler)")
+	g.P("				}")
+	g.P("			}")
+	g.P("")
+	g.P("			chainedHandler := handler")
+	g.P("			for i := n - 1; i >= 0; i-- {")
+	g.P("				chainedHandler = chainer(interceptors[i], chainedHandler)")
+	g.P("			}")
+	g.P("			return chainedHandler(srv, ss)")
+	g.P("		}")
+	g.P("")
+	g.P("		info := &", grpcPackage.Ident("StreamServerInfo"), "{")
+	g.P("			FullMethod:     \"", fullMethodName(method), "\",")
+	g.P("			IsClientStream: ", method.Desc.IsStreamingClient(), ",")
+	g.P("			IsServerStream: ", method.Desc.IsStreamingServer(), ",")
+	g.P("		}")
+	g.P("")
+	g.P("		handler := func(srv interface{}, ss ", grpcPackage.Ident("ServerStream"), ") error {")
+	g.P("			return ", call)
+	g.P("		}")
+	g.P("")
+}
+
+func genServerStreamCall(g *protogen.GeneratedFile, method *protogen.Method) {
+	g.P("		switch accept {")
+	g.P("		case \"application/protobuf\", \"application/x-protobuf\", \"application/json\", \"application/x-ndjson\", \"text/event-stream\":")
+	g.P("		default:")
+	g.P("			w.WriteHeader(", httpPackage.Ident("StatusUnsupportedMediaType"), ")")
+	g.P("			_, err := ", fmtPackage.Ident("Fprintf"), "(w, \"Unsupported Accept: %s\", accept)")
+	g.P("			cb(ctx, w, r, arg, nil, err)")
+	g.P("			return")
+	g.P("		}")
+	g.P("")
+	g.P("		ctx, cancel := ", contextPackage.Ident("WithCancel"), "(ctx)")
+	g.P("		defer cancel()")
+	g.P("")
+	g.P("		stream := &", serverStreamName(method.Parent.Desc.ParentFile()), "{")
+	g.P("			ctx:    ctx,")
+	g.P("			cancel: cancel,")
+	g.P("			w:      w,")
+	g.P("			accept: accept,")
+	g.P("			ts:     ts,")
+	g.P("		}")
+	genStreamChain(g, method, "h.srv."+method.GoName+"(arg, &"+methodStreamName(method)+"{ss})")
+	g.P("		err := chained(h.srv, stream, info, handler)")
+	g.P("		ts.writeHeader()")
+	g.P("		ts.writeTrailer(stream.wrote)")
+	g.P("		stream.finish(err)")
+	g.P("		if err != nil {")
+	g.P("			cb(ctx, w, r, arg, nil, err)")
+	g.P("			return")
+	g.P("		}")
+	g.P("		cb(ctx, w, r, arg, nil, nil)")
 }
 
 func genMethodWithName(g *protogen.GeneratedFile, method *protogen.Method) {
@@ -298,39 +977,135 @@ func genMethodWithName(g *protogen.GeneratedFile, method *protogen.Method) {
 	g.P("}")
 }
 
-func genMethodHTTPRule(g *protogen.GeneratedFile, method *protogen.Method) error {
+func getHTTPRule(method *protogen.Method) (*annotations.HttpRule, bool) {
 	options, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
 	if !ok {
-		return nil
+		return nil, false
 	}
 
 	httpRule, ok := proto.GetExtension(options, annotations.E_Http).(*annotations.HttpRule)
-	if !ok {
-		return nil
+	if !ok || httpRule == nil {
+		return nil, false
 	}
 
-	var (
-		httpMethod string
-		pattern    string
-	)
+	return httpRule, true
+}
 
+// httpRulePattern returns HTTP method (e.g. "GET") and path template of google.api.HttpRule.
+func httpRulePattern(httpRule *annotations.HttpRule) (string, string, bool) {
 	switch httpRule.GetPattern().(type) {
 	case *annotations.HttpRule_Get:
-		httpMethod = "http.MethodGet"
-		pattern = httpRule.GetGet()
+		return "GET", httpRule.GetGet(), true
 	case *annotations.HttpRule_Put:
-		httpMethod = "http.MethodPut"
-		pattern = httpRule.GetPut()
+		return "PUT", httpRule.GetPut(), true
 	case *annotations.HttpRule_Post:
-		httpMethod = "http.MethodPost"
-		pattern = httpRule.GetPost()
+		return "POST", httpRule.GetPost(), true
 	case *annotations.HttpRule_Delete:
-		httpMethod = "http.MethodDelete"
-		pattern = httpRule.GetDelete()
+		return "DELETE", httpRule.GetDelete(), true
 	case *annotations.HttpRule_Patch:
-		httpMethod = "http.MethodPatch"
-		pattern = httpRule.GetPatch()
+		return "PATCH", httpRule.GetPatch(), true
 	default:
+		return "", "", false
+	}
+}
+
+// httpRuleMethods returns the HTTP method of the rule followed by the methods of its additional bindings
+// that have the same path and body. The handler of the rule serves them as it reads the requests in the same way.
+func httpRuleMethods(httpRule *annotations.HttpRule) []string {
+	httpMethod, pattern, ok := httpRulePattern(httpRule)
+	if !ok {
+		return nil
+	}
+	methods := []string{httpMethod}
+	for _, binding := range httpRule.GetAdditionalBindings() {
+		m, p, ok := httpRulePattern(binding)
+		if !ok || p != pattern || ruleBody(m, binding) != ruleBody(httpMethod, httpRule) {
+			continue
+		}
+		dup := false
+		for _, method := range methods {
+			dup = dup || method == m
+		}
+		if !dup {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+// ignoredBindings returns the additional bindings of the rule that the handler of the rule does not serve
+// because they have a different path or body, or a custom pattern, e.g. `post "/v1/messages/{message_id}"`.
+func ignoredBindings(httpRule *annotations.HttpRule) []string {
+	httpMethod, pattern, ok := httpRulePattern(httpRule)
+	if !ok {
+		return nil
+	}
+	var ignored []string
+	for _, binding := range httpRule.GetAdditionalBindings() {
+		m, p, ok := httpRulePattern(binding)
+		switch {
+		case !ok:
+			ignored = append(ignored, fmt.Sprintf("custom %q", binding.GetCustom().GetPath()))
+		case p != pattern || ruleBody(m, binding) != ruleBody(httpMethod, httpRule):
+			ignored = append(ignored, fmt.Sprintf("%s %q", strings.ToLower(m), p))
+		}
+	}
+	return ignored
+}
+
+// ruleBody returns body of the rule read by the handler. The body of GET requests is not read.
+func ruleBody(httpMethod string, httpRule *annotations.HttpRule) string {
+	if httpMethod == "GET" {
+		return ""
+	}
+	return httpRule.GetBody()
+}
+
+func httpMethodIdent(httpMethod string) protogen.GoIdent {
+	return httpPackage.Ident("Method" + httpMethod[:1] + strings.ToLower(httpMethod[1:]))
+}
+
+func fullMethodName(method *protogen.Method) string {
+	return "/" + string(method.Parent.Desc.FullName()) + "/" + string(method.Desc.Name())
+}
+
+// genMethodCheck generates the code responding 405 Method Not Allowed if the request method does not match httpMethod.
+// HEAD is allowed for GET as http.ServeMux routes HEAD requests to GET patterns.
+func genMethodCheck(g *protogen.GeneratedFile, httpMethods []string) {
+	allow := make([]string, 0, len(httpMethods)+1)
+	for _, m := range httpMethods {
+		allow = append(allow, m)
+		if m == "GET" {
+			allow = append(allow, "HEAD")
+		}
+	}
+	conds := make([]string, 0, len(allow))
+	for _, m := range allow {
+		conds = append(conds, "r.Method != "+g.QualifiedGoIdent(httpMethodIdent(m)))
+	}
+	g.P("		if !h.skipMethodCheck && ", strings.Join(conds, " && "), " {")
+	g.P("			w.Header().Set(\"Allow\", \"", strings.Join(allow, ", "), "\")")
+	g.P("			w.WriteHeader(", httpPackage.Ident("StatusMethodNotAllowed"), ")")
+	g.P("			_, err := ", fmtPackage.Ident("Fprintf"), "(w, \"Method Not Allowed: %s\", r.Method)")
+	g.P("			cb(ctx, w, r, nil, nil, err)")
+	g.P("			return")
+	g.P("		}")
+	g.P("")
+}
+
+func genMethodHTTPRule(g *protogen.GeneratedFile, method *protogen.Method) error {
+	// WebSocket handshake is always GET, so google.api.http option is not applied.
+	if isBidiStreaming(method) {
+		return nil
+	}
+
+	httpRule, ok := getHTTPRule(method)
+	if !ok {
+		return nil
+	}
+
+	httpMethod, pattern, ok := httpRulePattern(httpRule)
+	if !ok {
 		return nil
 	}
 
@@ -339,154 +1114,278 @@ func genMethodHTTPRule(g *protogen.GeneratedFile, method *protogen.Method) error
 		return err
 	}
 
-	queryParams := createQueryParams(method)
+	queryParams := ruleQueryParams(method, httpRule, pathParams)
+
+	// Unlike unregistrable patterns, the bindings do not fail the generation because the method is still served by the rule.
+	for _, binding := range ignoredBindings(httpRule) {
+		fmt.Fprintf(os.Stderr, "protoc-gen-gohttp: warning: %s: additional_bindings %s is ignored; only the bindings with the same path and body as google.api.http are supported\n", method.Desc.FullName(), binding)
+	}
 
 	g.P("// ", method.GoName, "HTTPRule returns HTTP method, path and ", method.Parent.GoName, "HTTPService interface's ", method.GoName, " converted to http.HandlerFunc.")
 	if method.Comments.Leading.String() != "" {
 		g.P("//")
 	}
 	g.P(method.Comments.Leading, methodSignature(g, method, "HTTPRule"), " (string, string, ", httpPackage.Ident("HandlerFunc"), ") {")
-	genDefaultCallback(g)
-	g.P("	return ", httpMethod, ", \"", pattern, "\", ", httpPackage.Ident("HandlerFunc"), "(func(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ") {")
-	g.P("		ctx := r.Context()")
-	g.P("")
-	g.P("		contentType, _, _ := ", mimePackage.Ident("ParseMediaType"), "(r.Header.Get(\"Content-Type\"))")
-	g.P("")
-	g.P("		accepts := ", stringsPackage.Ident("Split"), "(r.Header.Get(\"Accept\"), \",\")")
-	g.P("		accept := accepts[0]")
-	g.P("		if accept == \"*/*\" || accept == \"\" {")
-	g.P("			if contentType != \"\" {")
-	g.P("				accept = contentType")
-	g.P("			} else {")
-	g.P("				accept = \"application/json\"")
-	g.P("			}")
-	g.P("		}")
-	g.P("")
-	g.P("		w.Header().Set(\"Content-Type\", accept)")
-	g.P("")
-	g.P("		arg := &", genMessageName(method.Input), "{}")
-	if _, ok := httpRule.GetPattern().(*annotations.HttpRule_Get); ok {
-		g.P("if r.Method == http.MethodGet {")
-		for _, p := range queryParams {
-			for _, pattern := range pathParams {
-				if p.GoName == pattern.GoName {
-					goto Pass
+	genDefaultCallback(g, method)
+	genDefaultInterceptors(g, method)
+	g.P("	return ", httpMethodIdent(httpMethod), ", \"", pattern, "\", ", httpPackage.Ident("HandlerFunc"), "(func(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ") {")
+	genNegotiation(g, method, pattern)
+	httpMethods := httpRuleMethods(httpRule)
+	genMethodCheck(g, httpMethods)
+	// The request body of client-streaming RPC is a stream of messages,
+	// so path and query parameters are not bound to them.
+	if !method.Desc.IsStreamingClient() {
+		g.P("		arg := &", genMessageName(method.Input), "{}")
+		if _, ok := httpRule.GetPattern().(*annotations.HttpRule_Get); ok {
+			conds := []string{"r.Method == http.MethodGet", "r.Method == http.MethodHead"}
+			for _, m := range httpMethods[1:] {
+				conds = append(conds, "r.Method == "+g.QualifiedGoIdent(httpMethodIdent(m)))
+			}
+			g.P("if ", strings.Join(conds, " || "), " {")
+			for _, p := range queryParams {
+				genQueryString(g, p)
+			}
+			g.P("}")
+		} else {
+			// The body is decoded first because unmarshaling resets the message.
+			if httpRule.GetBody() != "" {
+				field, err := bodyField(method, httpRule)
+				if err != nil {
+					return err
 				}
+				g.P("		if r.Method != ", httpPackage.Ident("MethodGet"), " {")
+				genDecodeBody(g, method, field)
+				g.P("		}")
+			}
+			for _, p := range queryParams {
+				genQueryString(g, p)
 			}
-			genQueryString(g, p)
-		Pass:
 		}
-		g.P("}")
-	} else {
-		g.P("		if r.Method != ", httpPackage.Ident("MethodGet"), " {")
-		g.P("			body, err := ", ioutilPackage.Ident("ReadAll"), "(r.Body)")
-		g.P("			if err != nil {")
-		g.P("				cb(ctx, w, r, nil, nil, err)")
-		g.P("				return")
-		g.P("			}")
 		g.P("")
-		g.P("			switch contentType {")
-		g.P("			case \"application/protobuf\", \"application/x-protobuf\":")
-		g.P("				if err := ", protoPackage.Ident("Unmarshal"), "(body, arg); err != nil {")
-		g.P("					cb(ctx, w, r, nil, nil, err)")
-		g.P("					return")
-		g.P("				}")
-		g.P("			case \"application/json\":")
-		g.P("				if err := ", protojsonPackage.Ident("Unmarshal"), "(body, arg); err != nil {")
-		g.P("					cb(ctx, w, r, nil, nil, err)")
-		g.P("					return")
-		g.P("				}")
-		g.P("			default:")
-		g.P("				w.WriteHeader(", httpPackage.Ident("StatusUnsupportedMediaType"), ")")
-		g.P("				_, err := ", fmtPackage.Ident("Fprintf"), "(w, \"Unsupported Content-Type: %s\", contentType)")
-		g.P("				cb(ctx, w, r, nil, nil, err)")
-		g.P("				return")
-		g.P("			}")
-		g.P("		}")
+
+		if len(pathParams) != 0 {
+			// The verb is not a part of the last path parameter read from the path by other routers.
+			if _, verb := tokenize(pattern[1:]); verb != "" {
+				g.P("p := strings.Split(strings.TrimSuffix(r.URL.Path, \":", verb, "\"), \"/\")")
+			} else {
+				g.P("p := strings.Split(r.URL.Path, \"/\")")
+			}
+		}
+
+		for _, t := range pathParams {
+			// Messages containing the field are allocated unless they are decoded from the body.
+			names := strings.Split(t.Name, ".")
+			for i, p := range t.GetSplitedGoNames() {
+				field := findField(method.Input, strings.Join(names[:i+1], "."))
+				if field == nil || field.Message == nil {
+					return fmt.Errorf("%s: path parameter %q is not a field of %s", method.Desc.FullName(), t.Name, method.Input.Desc.FullName())
+				}
+				g.P("if arg.", p, " == nil {")
+				g.P("	arg.", p, " = &", field.Message.GoIdent, "{}")
+				g.P("}")
+			}
+
+			genPathValue(g, t)
+		}
+
+		g.P("")
+	}
+	genCall(g, method)
+	g.P("	})")
+	g.P("}")
+
+	return nil
+}
+
+// genPathValue generates the code setting the path parameter to the request message.
+// The value is rebuilt from the wildcards registered by Register{Service}HTTPHandlers,
+// or taken from the path segment when the handler is used with other routers.
+func genPathValue(g *protogen.GeneratedFile, t *pathParam) {
+	var first, lit string
+	exprs := make([]string, 0, len(t.Elems))
+	for i, elem := range t.Elems {
+		if i != 0 {
+			lit += "/"
+		}
+		if !strings.HasPrefix(elem, "{") {
+			lit += elem
+			continue
+		}
+		if lit != "" {
+			exprs = append(exprs, strconv.Quote(lit))
+			lit = ""
+		}
+		name := strings.TrimSuffix(strings.TrimSuffix(elem[1:], "}"), "...")
+		if first == "" {
+			first = name
+			exprs = append(exprs, "v")
+			continue
+		}
+		exprs = append(exprs, "r.PathValue("+strconv.Quote(name)+")")
+	}
+	if lit != "" {
+		exprs = append(exprs, strconv.Quote(lit))
 	}
-	g.P("")
 
-	if len(pathParams) != 0 {
-		g.P("p := strings.Split(r.URL.Path, \"/\")")
+	if first == "" {
+		g.P("arg.", t.GoName, " = ", strings.Join(exprs, ""))
+		return
 	}
+	g.P("if v := r.PathValue(\"", first, "\"); v != \"\" {")
+	g.P("	arg.", t.GoName, " = ", strings.Join(exprs, " + "))
+	g.P("} else {")
+	g.P("	arg.", t.GoName, " = ", t.Value)
+	g.P("}")
+}
 
-	for _, t := range pathParams {
-		for _, p := range t.GetSplitedGoNames() {
-			g.P(reflectPackage.Ident("ValueOf"), "(&arg.", p, ").Elem().Set(", reflectPackage.Ident("ValueOf"), "(", reflectPackage.Ident("New"), "(", reflectPackage.Ident("TypeOf"), "(arg.", p, ").Elem()).Interface()))")
+func genRegister(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// Register", srv.GoName, "HTTPHandlers registers all methods of ", srv.GoName, "HTTPService on mux.")
+	g.P("// Methods with google.api.http option are registered with its HTTP method and path,")
+	g.P("// other methods are registered with POST /{package}.{Service}/{Method}.")
+	if hasWebSocketMethod(srv) {
+		g.P("// Bidirectional streaming methods are registered with GET /{package}.{Service}/{Method} for WebSocket handshake.")
+	}
+	g.P("func Register", srv.GoName, "HTTPHandlers(mux *", httpPackage.Ident("ServeMux"), ", conv *", srv.GoName, "HTTPConverter) {")
+	declared := false
+	for _, method := range srv.Methods {
+		if !isGeneratedMethod(method) {
+			continue
+		}
+
+		if isBidiStreaming(method) {
+			g.P("mux.Handle(\"GET ", fullMethodName(method), "\", conv.", method.GoName, "(nil))")
+			continue
+		}
+
+		if httpRule, ok := getHTTPRule(method); ok {
+			if httpMethod, pattern, ok := httpRulePattern(httpRule); ok {
+				if _, err := serveMuxPattern(httpMethod, pattern); err != nil {
+					g.P("// ", method.GoName, " is not registered: ", err)
+					continue
+				}
+				if !declared {
+					g.P("var hf ", httpPackage.Ident("HandlerFunc"))
+					declared = true
+				}
+				g.P("_, _, hf = conv.", method.GoName, "HTTPRule(nil)")
+				for _, m := range httpRuleMethods(httpRule) {
+					muxPattern, _ := serveMuxPattern(m, pattern)
+					g.P("mux.Handle(\"", muxPattern, "\", hf)")
+				}
+				continue
+			}
 		}
 
-		g.P("arg.", t.GoName, " = p[", t.Index, "]")
+		g.P("mux.Handle(\"POST ", fullMethodName(method), "\", conv.", method.GoName, "(nil))")
 	}
+	g.P("}")
+}
 
-	g.P("")
-	g.P("		n := len(interceptors)")
-	g.P("		chained := func(ctx ", contextPackage.Ident("Context"), ", arg interface{}, info *", grpcPackage.Ident("UnaryServerInfo"), ", handler ", grpcPackage.Ident("UnaryHandler"), ") (interface{}, error) {")
-	g.P("			chainer := func(currentInter ", grpcPackage.Ident("UnaryServerInterceptor"), ", currentHandler ", grpcPackage.Ident("UnaryHandler"), ") ", grpcPackage.Ident("UnaryHandler"), " {")
-	g.P("				return func(currentCtx ", contextPackage.Ident("Context"), ", currentReq interface{}) (interface{}, error) {")
-	g.P("					return currentInter(currentCtx, currentReq, info, currentHandler)")
-	g.P("				}")
-	g.P("			}")
-	g.P("")
-	g.P("			chainedHandler := handler")
-	g.P("			for i := n - 1; i >= 0; i-- {")
-	g.P("				chainedHandler = chainer(interceptors[i], chainedHandler)")
-	g.P("			}")
-	g.P("			return chainedHandler(ctx, arg)")
-	g.P("		}")
-	g.P("")
-	g.P("		info := &", grpcPackage.Ident("UnaryServerInfo"), "{")
-	g.P("			Server:     h.srv,")
-	g.P("			FullMethod: \"/", method.Desc.ParentFile().Package(), ".", method.Parent.GoName, "/", method.GoName, "\",")
-	g.P("		}")
-	g.P("")
-	g.P("		handler := func(c ", contextPackage.Ident("Context"), ", req interface{}) (interface{}, error) {")
-	g.P("			return h.srv.", method.GoName, "(c, req.(*", genMessageName(method.Input), "))")
-	g.P("		}")
-	g.P("")
-	g.P("		iret, err := chained(ctx, arg, info, handler)")
-	g.P("		if err != nil {")
-	g.P("			cb(ctx, w, r, arg, nil, err)")
-	g.P("			return")
-	g.P("		}")
-	g.P("")
-	g.P("		ret, ok := iret.(*", genMessageName(method.Output), ")")
-	g.P("		if !ok {")
-	g.P("			cb(ctx, w, r, arg, nil, fmt.Errorf(\"/", method.Desc.ParentFile().Package(), ".", method.Parent.GoName, "/", method.GoName, ": interceptors have not return ", genMessageName(method.Output), "\"))")
-	g.P("			return")
-	g.P("		}")
-	g.P("")
-	g.P("		switch accept {")
-	g.P("		case \"application/protobuf\", \"application/x-protobuf\":")
-	g.P("			buf, err := ", protoPackage.Ident("Marshal"), "(ret)")
-	g.P("			if err != nil {")
-	g.P("				cb(ctx, w, r, arg, ret, err)")
-	g.P("				return")
-	g.P("			}")
-	g.P("			if _, err := ", ioPackage.Ident("Copy"), "(w, ", bytesPackage.Ident("NewBuffer"), "(buf)); err != nil {")
-	g.P("				cb(ctx, w, r, arg, ret, err)")
-	g.P("				return")
-	g.P("			}")
-	g.P("		case \"application/json\":")
-	g.P("			buf, err := ", protojsonPackage.Ident("Marshal"), "(ret)")
-	g.P("			if err != nil {")
-	g.P("				cb(ctx, w, r, arg, ret, err)")
-	g.P("				return")
-	g.P("			}")
-	g.P("			if _, err := ", ioPackage.Ident("Copy"), "(w, ", bytesPackage.Ident("NewBuffer"), "(buf)); err != nil {")
-	g.P("				cb(ctx, w, r, arg, ret, err)")
-	g.P("				return")
-	g.P("			}")
-	g.P("		default:")
-	g.P("			w.WriteHeader(", httpPackage.Ident("StatusUnsupportedMediaType"), ")")
-	g.P("			_, err := fmt.Fprintf(w, \"Unsupported Accept: %s\", accept)")
-	g.P("			cb(ctx, w, r, arg, ret, err)")
-	g.P("			return")
+func genRoutes(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// ", srv.GoName, "HTTPRoute is a route of ", srv.GoName, "HTTPService method.")
+	g.P("type ", srv.GoName, "HTTPRoute struct {")
+	g.P("	// Method is HTTP method of the route.")
+	g.P("	Method string")
+	g.P("	// Pattern is path template of google.api.http option, or /{package}.{Service}/{Method} if the option is not defined.")
+	g.P("	Pattern string")
+	g.P("	// Handler is ", srv.GoName, "HTTPService method converted to http.HandlerFunc.")
+	g.P("	Handler ", httpPackage.Ident("HandlerFunc"))
+	g.P("	// FullMethod is the full RPC method string, i.e., /package.service/method.")
+	g.P("	FullMethod string")
+	g.P("}")
+	g.P()
+	g.P("// ", srv.GoName, "HTTPRouter is the interface of HTTP routers that ", srv.GoName, "HTTPRoute is registered on.")
+	g.P("type ", srv.GoName, "HTTPRouter interface {")
+	g.P("	Handle(method, pattern string, handler ", httpPackage.Ident("Handler"), ")")
+	g.P("}")
+	g.P()
+	g.P("// ", srv.GoName, "HTTPRouterFunc is an adapter to use a function as ", srv.GoName, "HTTPRouter.")
+	g.P("type ", srv.GoName, "HTTPRouterFunc func(method, pattern string, handler ", httpPackage.Ident("Handler"), ")")
+	g.P()
+	g.P("// Handle calls f(method, pattern, handler).")
+	g.P("func (f ", srv.GoName, "HTTPRouterFunc) Handle(method, pattern string, handler ", httpPackage.Ident("Handler"), ") {")
+	g.P("	f(method, pattern, handler)")
+	g.P("}")
+	g.P()
+	// The routes are returned by a function because a method of the converter may collide with the methods of RPCs.
+	g.P("// ", srv.GoName, "HTTPRoutes returns routes of all methods of ", srv.GoName, "HTTPService converted by conv.")
+	g.P("func ", srv.GoName, "HTTPRoutes(conv *", srv.GoName, "HTTPConverter) []", srv.GoName, "HTTPRoute {")
+	g.P("	routes := make([]", srv.GoName, "HTTPRoute, 0)")
+	for _, method := range srv.Methods {
+		if !isGeneratedMethod(method) {
+			continue
+		}
+
+		if isBidiStreaming(method) {
+			g.P("routes = append(routes, ", srv.GoName, "HTTPRoute{")
+			g.P("	Method:     ", httpPackage.Ident("MethodGet"), ",")
+			g.P("	Pattern:    \"", fullMethodName(method), "\",")
+			g.P("	Handler:    conv.", method.GoName, "(nil),")
+			g.P("	FullMethod: \"", fullMethodName(method), "\",")
+			g.P("})")
+			continue
+		}
+
+		if httpRule, ok := getHTTPRule(method); ok {
+			if _, _, ok := httpRulePattern(httpRule); ok {
+				g.P("{")
+				g.P("	method, pattern, handler := conv.", method.GoName, "HTTPRule(nil)")
+				g.P("	routes = append(routes, ", srv.GoName, "HTTPRoute{")
+				g.P("		Method:     method,")
+				g.P("		Pattern:    pattern,")
+				g.P("		Handler:    handler,")
+				g.P("		FullMethod: \"", fullMethodName(method), "\",")
+				g.P("	})")
+				for _, m := range httpRuleMethods(httpRule)[1:] {
+					g.P("	routes = append(routes, ", srv.GoName, "HTTPRoute{")
+					g.P("		Method:     ", httpMethodIdent(m), ",")
+					g.P("		Pattern:    pattern,")
+					g.P("		Handler:    handler,")
+					g.P("		FullMethod: \"", fullMethodName(method), "\",")
+					g.P("	})")
+				}
+				g.P("}")
+				continue
+			}
+		}
+
+		g.P("routes = append(routes, ", srv.GoName, "HTTPRoute{")
+		g.P("	Method:     ", httpPackage.Ident("MethodPost"), ",")
+		g.P("	Pattern:    \"", fullMethodName(method), "\",")
+		g.P("	Handler:    conv.", method.GoName, "(nil),")
+		g.P("	FullMethod: \"", fullMethodName(method), "\",")
+		g.P("})")
+	}
+	g.P("	return routes")
+	g.P("}")
+	g.P()
+	g.P("// Register", srv.GoName, "HTTPRoutes registers all routes of ", srv.GoName, "HTTPService on router.")
+	g.P("func Register", srv.GoName, "HTTPRoutes(router ", srv.GoName, "HTTPRouter, conv *", srv.GoName, "HTTPConverter) {")
+	g.P("	for _, route := range ", srv.GoName, "HTTPRoutes(conv) {")
+	g.P("		router.Handle(route.Method, route.Pattern, route.Handler)")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// ", srv.GoName, "HTTPRoutesHandler returns http.Handler writing HTTP method, pattern and full method name of all routes")
+	g.P("// of conv as JSON.")
+	g.P("func ", srv.GoName, "HTTPRoutesHandler(conv *", srv.GoName, "HTTPConverter) ", httpPackage.Ident("Handler"), " {")
+	g.P("	type route struct {")
+	g.P("		Method     string `json:\"method\"`")
+	g.P("		Pattern    string `json:\"pattern\"`")
+	g.P("		FullMethod string `json:\"fullMethod\"`")
+	g.P("	}")
+	g.P("	routes := ", srv.GoName, "HTTPRoutes(conv)")
+	g.P("	rs := make([]route, 0, len(routes))")
+	g.P("	for _, r := range routes {")
+	g.P("		rs = append(rs, route{Method: r.Method, Pattern: r.Pattern, FullMethod: r.FullMethod})")
+	g.P("	}")
+	g.P("	return ", httpPackage.Ident("HandlerFunc"), "(func(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ") {")
+	g.P("		w.Header().Set(\"Content-Type\", \"application/json\")")
+	g.P("		if err := ", jsonPackage.Ident("NewEncoder"), "(w).Encode(rs); err != nil {")
+	g.P("			", httpPackage.Ident("Error"), "(w, err.Error(), ", httpPackage.Ident("StatusInternalServerError"), ")")
 	g.P("		}")
-	g.P("		cb(ctx, w, r, arg, ret, nil)")
 	g.P("	})")
 	g.P("}")
-
-	return nil
 }
 
 func genQueryString(g *protogen.GeneratedFile, queryParam *queryParam) {
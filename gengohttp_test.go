@@ -0,0 +1,57 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+)
+
+func TestIgnoredBindings(t *testing.T) {
+	for _, spec := range []struct {
+		name string
+		rule *annotations.HttpRule
+		want []string
+	}{
+		{
+			name: "same path and body",
+			rule: &annotations.HttpRule{
+				Pattern: &annotations.HttpRule_Patch{Patch: "/v1/{shelf.name=shelves/*}"},
+				Body:    "shelf",
+				AdditionalBindings: []*annotations.HttpRule{
+					{Pattern: &annotations.HttpRule_Put{Put: "/v1/{shelf.name=shelves/*}"}, Body: "shelf"},
+				},
+			},
+		},
+		{
+			name: "body of GET is not read",
+			rule: &annotations.HttpRule{
+				Pattern: &annotations.HttpRule_Post{Post: "/v1/messages"},
+				AdditionalBindings: []*annotations.HttpRule{
+					{Pattern: &annotations.HttpRule_Get{Get: "/v1/messages"}, Body: "*"},
+				},
+			},
+		},
+		{
+			name: "different path, body and custom pattern",
+			rule: &annotations.HttpRule{
+				Pattern: &annotations.HttpRule_Patch{Patch: "/v1/messages/{message_id}"},
+				Body:    "*",
+				AdditionalBindings: []*annotations.HttpRule{
+					{Pattern: &annotations.HttpRule_Put{Put: "/v1/messages:update"}, Body: "*"},
+					{Pattern: &annotations.HttpRule_Post{Post: "/v1/messages/{message_id}"}, Body: "text"},
+					{Pattern: &annotations.HttpRule_Custom{Custom: &annotations.CustomHttpPattern{Kind: "HEAD", Path: "/v1/messages/{message_id}"}}},
+				},
+			},
+			want: []string{
+				`put "/v1/messages:update"`,
+				`post "/v1/messages/{message_id}"`,
+				`custom "/v1/messages/{message_id}"`,
+			},
+		},
+	} {
+		if got := ignoredBindings(spec.rule); !reflect.DeepEqual(got, spec.want) {
+			t.Errorf("%s: ignoredBindings() = %q; want %q", spec.name, got, spec.want)
+		}
+	}
+}
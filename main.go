@@ -1,16 +1,33 @@
 package main
 
 import (
+	"flag"
+
 	"google.golang.org/protobuf/compiler/protogen"
 )
 
+var (
+	flags     flag.FlagSet
+	websocket = flags.Bool("websocket", false, "generate WebSocket handlers for bidirectional streaming methods")
+	openapi   = flags.Bool("openapi", false, "generate OpenAPI v3 document (.openapi.json) for each proto file")
+	// The default is the same as the maximum message size received by gRPC servers.
+	maxBodySize = flags.Int64("max_body_size", 4<<20, "default maximum size of request bodies in bytes; zero or a negative value means no limit")
+)
+
 func main() {
-	protogen.Options{}.Run(func(p *protogen.Plugin) error {
+	protogen.Options{
+		ParamFunc: flags.Set,
+	}.Run(func(p *protogen.Plugin) error {
 		for _, f := range p.Files {
 			if f.Generate {
 				if _, err := GenerateFile(p, f); err != nil {
 					return err
 				}
+				if *openapi {
+					if _, err := GenerateOpenAPI(p, f); err != nil {
+						return err
+					}
+				}
 			}
 		}
 
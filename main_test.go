@@ -20,6 +20,16 @@ func init() {
 	}
 }
 
+// goldenOptions are the parameters passed to protoc-gen-gohttp for each package in testdata.
+var goldenOptions = map[string]string{
+	"testdata/auth":       "max_body_size=1024",
+	"testdata/collision":  "openapi=true",
+	"testdata/httprule":   "openapi=true",
+	"testdata/knowntypes": "openapi=true",
+	"testdata/routechat":  "websocket=true,openapi=true",
+	"testdata/routeguide": "openapi=true",
+}
+
 func TestGolden(t *testing.T) {
 	workdir, err := ioutil.TempDir("", "protoc-gen-gohttp-test")
 	if err != nil {
@@ -43,8 +53,11 @@ func TestGolden(t *testing.T) {
 	}
 
 	// Compile each package, using this binary as protoc-gen-gohttp.
-	for _, sources := range packages {
+	for dir, sources := range packages {
 		args := []string{"-Itestdata", "--gohttp_out=" + workdir}
+		if opt, ok := goldenOptions[dir]; ok {
+			args = append(args, "--gohttp_opt="+opt)
+		}
 		args = append(args, sources...)
 		protoc(t, args)
 	}
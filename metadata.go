@@ -0,0 +1,282 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// transportStreamName returns the name of grpc.ServerTransportStream implementation shared by the services of the file.
+func transportStreamName(file protoreflect.FileDescriptor) string {
+	return runtimeName(file, "httpTransportStream")
+}
+
+// genRequestContext generates the code deriving the context of the RPC from the request.
+// The context has grpc.ServerTransportStream "ts" collecting header and trailer metadata set by the RPC.
+// route is the path template of google.api.http option passed to the tracer.
+func genRequestContext(g *protogen.GeneratedFile, method *protogen.Method, route string) {
+	file := method.Parent.Desc.ParentFile()
+	g.P("		ctx := r.Context()")
+	g.P("		if md := ", runtimeName(file, "incomingMetadata"), "(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {")
+	g.P("			ctx = ", metadataPackage.Ident("NewIncomingContext"), "(ctx, md)")
+	g.P("		}")
+	g.P("		if p := ", runtimeName(file, "requestPeer"), "(r); p != nil {")
+	g.P("			ctx = ", peerPackage.Ident("NewContext"), "(ctx, p)")
+	g.P("		}")
+	genRequestTimeout(g, file)
+	genRequestTrace(g, method, route)
+	g.P("		ts := &", transportStreamName(file), "{")
+	g.P("			method:        \"", fullMethodName(method), "\",")
+	g.P("			w:             w,")
+	g.P("			headers:       h.outgoingHeaders,")
+	g.P("			headerPrefix:  h.outgoingHeaderPrefix,")
+	g.P("			trailerPrefix: h.outgoingTrailerPrefix,")
+	g.P("		}")
+	g.P("		ctx = ", grpcPackage.Ident("NewContextWithServerTransportStream"), "(ctx, ts)")
+}
+
+// genTransportStream generates grpc.ServerTransportStream implementation writing metadata as HTTP headers.
+func genTransportStream(g *protogen.GeneratedFile, file *protogen.File) {
+	name := transportStreamName(file.Desc)
+	g.P("// ", name, " implements grpc.ServerTransportStream to write header and trailer metadata set by the RPC")
+	g.P("// as HTTP headers, so that grpc.SetHeader, grpc.SendHeader and grpc.SetTrailer work in the RPC.")
+	g.P("type ", name, " struct {")
+	g.P("	method        string")
+	g.P("	w             ", httpPackage.Ident("ResponseWriter"))
+	g.P("	headers       []string")
+	g.P("	headerPrefix  string")
+	g.P("	trailerPrefix string")
+	g.P()
+	g.P("	mu         ", syncPackage.Ident("Mutex"))
+	g.P("	header     ", metadataPackage.Ident("MD"))
+	g.P("	trailer    ", metadataPackage.Ident("MD"))
+	g.P("	headerSent bool")
+	g.P("}")
+	g.P()
+	g.P("func (s *", name, ") Method() string {")
+	g.P("	return s.method")
+	g.P("}")
+	g.P()
+	g.P("// SetHeader sets the header metadata written before the response body.")
+	g.P("func (s *", name, ") SetHeader(md ", metadataPackage.Ident("MD"), ") error {")
+	g.P("	s.mu.Lock()")
+	g.P("	defer s.mu.Unlock()")
+	g.P("	if s.headerSent {")
+	g.P("		return ", statusPackage.Ident("Error"), "(", codesPackage.Ident("Internal"), ", \"the header has already been sent\")")
+	g.P("	}")
+	g.P("	s.header = ", metadataPackage.Ident("Join"), "(s.header, md)")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// SendHeader sets the header metadata and writes it to the response headers.")
+	g.P("// The headers are sent to the client with the response body.")
+	g.P("func (s *", name, ") SendHeader(md ", metadataPackage.Ident("MD"), ") error {")
+	g.P("	s.mu.Lock()")
+	g.P("	defer s.mu.Unlock()")
+	g.P("	if s.headerSent {")
+	g.P("		return ", statusPackage.Ident("Error"), "(", codesPackage.Ident("Internal"), ", \"the header has already been sent\")")
+	g.P("	}")
+	g.P("	s.header = ", metadataPackage.Ident("Join"), "(s.header, md)")
+	g.P("	s.writeHeaderLocked()")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// SetTrailer sets the trailer metadata written after the RPC returns.")
+	g.P("func (s *", name, ") SetTrailer(md ", metadataPackage.Ident("MD"), ") error {")
+	g.P("	s.mu.Lock()")
+	g.P("	defer s.mu.Unlock()")
+	g.P("	s.trailer = ", metadataPackage.Ident("Join"), "(s.trailer, md)")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// writeHeader writes the header metadata to the response headers unless it has been written.")
+	g.P("func (s *", name, ") writeHeader() {")
+	g.P("	s.mu.Lock()")
+	g.P("	defer s.mu.Unlock()")
+	g.P("	s.writeHeaderLocked()")
+	g.P("}")
+	g.P()
+	g.P("func (s *", name, ") writeHeaderLocked() {")
+	g.P("	if s.headerSent {")
+	g.P("		return")
+	g.P("	}")
+	g.P("	s.headerSent = true")
+	g.P("	s.add(s.headerPrefix, s.header, s.headers)")
+	g.P("}")
+	g.P()
+	g.P("// writeTrailer writes the trailer metadata as HTTP trailers if asTrailer is true.")
+	g.P("// Otherwise, it is written to the response headers because the response body has not been written yet.")
+	g.P("func (s *", name, ") writeTrailer(asTrailer bool) {")
+	g.P("	s.mu.Lock()")
+	g.P("	defer s.mu.Unlock()")
+	g.P("	if asTrailer {")
+	g.P("		s.add(", httpPackage.Ident("TrailerPrefix"), "+s.trailerPrefix, s.trailer, nil)")
+	g.P("		return")
+	g.P("	}")
+	g.P("	s.add(s.trailerPrefix, s.trailer, nil)")
+	g.P("}")
+	g.P()
+	g.P("// writeStatusCode writes the status code set by the header metadata \"x-http-code\" and reports whether")
+	g.P("// the response body is allowed. The status code is ignored unless it is between 200 and 599.")
+	g.P("func (s *", name, ") writeStatusCode() bool {")
+	g.P("	s.mu.Lock()")
+	g.P("	defer s.mu.Unlock()")
+	g.P("	vs := s.header.Get(\"x-http-code\")")
+	g.P("	if len(vs) == 0 {")
+	g.P("		return true")
+	g.P("	}")
+	g.P("	code, err := ", strconvPackage.Ident("Atoi"), "(vs[len(vs)-1])")
+	g.P("	if err != nil || code < 200 || code > 599 {")
+	g.P("		return true")
+	g.P("	}")
+	g.P("	if code == ", httpPackage.Ident("StatusNoContent"), " || code == ", httpPackage.Ident("StatusNotModified"), " {")
+	g.P("		s.w.Header().Del(\"Content-Type\")")
+	g.P("		s.w.Header().Del(\"Content-Encoding\")")
+	g.P("		s.w.WriteHeader(code)")
+	g.P("		return false")
+	g.P("	}")
+	g.P("	s.w.WriteHeader(code)")
+	g.P("	return true")
+	g.P("}")
+	g.P()
+	g.P("// add adds md to the response headers with the prefix. The keys listed in headers are added without the prefix.")
+	g.P("// Values of the keys ending with \"-bin\" are encoded in base64 as gRPC does.")
+	g.P("func (s *", name, ") add(prefix string, md ", metadataPackage.Ident("MD"), ", headers []string) {")
+	g.P("	for k, vs := range md {")
+	g.P("		if k == \"x-http-code\" {")
+	g.P("			continue")
+	g.P("		}")
+	g.P("		name := prefix + k")
+	g.P("		for _, header := range headers {")
+	g.P("			if ", stringsPackage.Ident("EqualFold"), "(header, k) {")
+	g.P("				name = header")
+	g.P("				break")
+	g.P("			}")
+	g.P("		}")
+	g.P("		for _, v := range vs {")
+	g.P("			if ", stringsPackage.Ident("HasSuffix"), "(k, \"-bin\") {")
+	g.P("				v = ", base64Package.Ident("RawStdEncoding"), ".EncodeToString([]byte(v))")
+	g.P("			}")
+	g.P("			s.w.Header().Add(name, v)")
+	g.P("		}")
+	g.P("	}")
+	g.P("}")
+}
+
+// genIncomingMetadataOptions generates the options of the request headers passed to the RPC as incoming metadata.
+func genIncomingMetadataOptions(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// With", srv.GoName, "HTTPIncomingHeaders sets the request headers passed to the RPC as incoming metadata.")
+	g.P("// The default is Authorization. The metadata keys are the lowercase header names.")
+	g.P("func With", srv.GoName, "HTTPIncomingHeaders(headers ...string) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.incomingHeaders = headers")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", srv.GoName, "HTTPIncomingHeaderPrefix sets the prefix of the request headers passed to the RPC as incoming metadata.")
+	g.P("// The metadata keys are the lowercase header names without the prefix. The default is \"Grpc-Metadata-\",")
+	g.P("// and the empty prefix disables it.")
+	g.P("func With", srv.GoName, "HTTPIncomingHeaderPrefix(prefix string) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.incomingHeaderPrefix = prefix")
+	g.P("	}")
+	g.P("}")
+}
+
+// genOutgoingMetadataOptions generates the options of the response headers written from the metadata set by the RPC.
+func genOutgoingMetadataOptions(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// With", srv.GoName, "HTTPOutgoingHeaders sets the response headers written from the header metadata with the same")
+	g.P("// lowercase names, e.g. \"location\" for Location. They are written without the prefix. The default is Location.")
+	g.P("func With", srv.GoName, "HTTPOutgoingHeaders(headers ...string) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.outgoingHeaders = headers")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", srv.GoName, "HTTPOutgoingHeaderPrefix sets the prefix of the response headers written from the header metadata")
+	g.P("// set by grpc.SetHeader or grpc.SendHeader. The default is \"Grpc-Metadata-\".")
+	g.P("func With", srv.GoName, "HTTPOutgoingHeaderPrefix(prefix string) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.outgoingHeaderPrefix = prefix")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", srv.GoName, "HTTPOutgoingTrailerPrefix sets the prefix of the response headers or trailers written from the trailer metadata")
+	g.P("// set by grpc.SetTrailer. The default is \"Grpc-Trailer-\".")
+	g.P("func With", srv.GoName, "HTTPOutgoingTrailerPrefix(prefix string) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.outgoingTrailerPrefix = prefix")
+	g.P("	}")
+	g.P("}")
+}
+
+// genIncomingMetadata generates the function building the incoming metadata from the request headers.
+func genIncomingMetadata(g *protogen.GeneratedFile, file *protogen.File) {
+	name := runtimeName(file.Desc, "incomingMetadata")
+	g.P("// ", name, " returns the metadata built from the headers listed in headers and the headers with prefix.")
+	g.P("// The empty prefix disables it. Values of the keys ending with \"-bin\" are decoded from base64 as gRPC does.")
+	g.P("func ", name, "(header ", httpPackage.Ident("Header"), ", headers []string, prefix string) ", metadataPackage.Ident("MD"), " {")
+	g.P("	md := ", metadataPackage.Ident("MD"), "{}")
+	g.P("	add := func(key string, values []string) {")
+	g.P("		key = ", stringsPackage.Ident("ToLower"), "(key)")
+	g.P("		for _, v := range values {")
+	g.P("			if ", stringsPackage.Ident("HasSuffix"), "(key, \"-bin\") {")
+	g.P("				enc := ", base64Package.Ident("StdEncoding"))
+	g.P("				if len(v)%4 != 0 {")
+	g.P("					enc = ", base64Package.Ident("RawStdEncoding"))
+	g.P("				}")
+	g.P("				b, err := enc.DecodeString(v)")
+	g.P("				if err != nil {")
+	g.P("					continue")
+	g.P("				}")
+	g.P("				v = string(b)")
+	g.P("			}")
+	g.P("			md.Append(key, v)")
+	g.P("		}")
+	g.P("	}")
+	g.P()
+	g.P("	for _, key := range headers {")
+	g.P("		add(key, header.Values(key))")
+	g.P("	}")
+	g.P("	if prefix != \"\" {")
+	g.P("		for key, values := range header {")
+	g.P("			if len(key) > len(prefix) && ", stringsPackage.Ident("EqualFold"), "(key[:len(prefix)], prefix) {")
+	g.P("				add(key[len(prefix):], values)")
+	g.P("			}")
+	g.P("		}")
+	g.P("	}")
+	g.P("	return md")
+	g.P("}")
+}
+
+// genRequestPeer generates the function building peer.Peer of the RPC from the request.
+func genRequestPeer(g *protogen.GeneratedFile, file *protogen.File) {
+	name := runtimeName(file.Desc, "requestPeer")
+	g.P("// ", name, " returns the peer of the RPC built from the remote address and the TLS connection state of r.")
+	g.P("// It returns nil if the remote address is not an IP address and port.")
+	g.P("func ", name, "(r *", httpPackage.Ident("Request"), ") *", peerPackage.Ident("Peer"), " {")
+	g.P("	host, port, err := ", netPackage.Ident("SplitHostPort"), "(r.RemoteAddr)")
+	g.P("	if err != nil {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	ip := ", netPackage.Ident("ParseIP"), "(host)")
+	g.P("	if ip == nil {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	n, err := ", strconvPackage.Ident("Atoi"), "(port)")
+	g.P("	if err != nil {")
+	g.P("		return nil")
+	g.P("	}")
+	g.P("	p := &", peerPackage.Ident("Peer"), "{")
+	g.P("		Addr: &", netPackage.Ident("TCPAddr"), "{IP: ip, Port: n},")
+	g.P("	}")
+	g.P("	if r.TLS != nil {")
+	g.P("		p.AuthInfo = ", credentialsPackage.Ident("TLSInfo"), "{")
+	g.P("			State: *r.TLS,")
+	g.P("			CommonAuthInfo: ", credentialsPackage.Ident("CommonAuthInfo"), "{")
+	g.P("				SecurityLevel: ", credentialsPackage.Ident("PrivacyAndIntegrity"), ",")
+	g.P("			},")
+	g.P("		}")
+	g.P("	}")
+	g.P("	return p")
+	g.P("}")
+}
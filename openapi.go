@@ -0,0 +1,604 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The types below are the subset of OpenAPI 3.0 objects used by the generated document.
+// See https://spec.openapis.org/oas/v3.0.3 for the details.
+
+type openAPIDocument struct {
+	OpenAPI    string                      `json:"openapi"`
+	Info       openAPIInfo                 `json:"info"`
+	Tags       []openAPITag                `json:"tags,omitempty"`
+	Paths      map[string]*openAPIPathItem `json:"paths"`
+	Components openAPIComponents           `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIPathItem struct {
+	Get    *openAPIOperation `json:"get,omitempty"`
+	Put    *openAPIOperation `json:"put,omitempty"`
+	Post   *openAPIOperation `json:"post,omitempty"`
+	Delete *openAPIOperation `json:"delete,omitempty"`
+	Patch  *openAPIOperation `json:"patch,omitempty"`
+}
+
+type openAPIOperation struct {
+	Tags        []string                    `json:"tags,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	OperationID string                      `json:"operationId"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Description string                       `json:"description,omitempty"`
+	Required    bool                         `json:"required,omitempty"`
+	Content     map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// statusSchemaName is the schema name of google.rpc.Status written by the converter on errors.
+const statusSchemaName = "google.rpc.Status"
+
+// GenerateOpenAPI generates the OpenAPI document describing the HTTP handlers generated for the file.
+func GenerateOpenAPI(gen *protogen.Plugin, file *protogen.File) (*protogen.GeneratedFile, error) {
+	if !hasGeneratedMethod(file) {
+		return nil, nil
+	}
+
+	o := &openAPIGenerator{
+		doc: &openAPIDocument{
+			OpenAPI: "3.0.3",
+			Info: openAPIInfo{
+				Title:   file.Desc.Path(),
+				Version: "0.0.1",
+			},
+			Paths: map[string]*openAPIPathItem{},
+			Components: openAPIComponents{
+				Schemas: map[string]*openAPISchema{
+					statusSchemaName: {
+						Type:        "object",
+						Description: "The error returned by the converter.",
+						Properties: map[string]*openAPISchema{
+							"code":    {Type: "integer", Format: "int32", Description: "The status code of grpc/codes."},
+							"message": {Type: "string", Description: "The error message."},
+							"details": {Type: "array", Items: &openAPISchema{Type: "object"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, srv := range file.Services {
+		o.doc.Tags = append(o.doc.Tags, openAPITag{
+			Name:        srv.GoName,
+			Description: comment(srv.Comments.Leading),
+		})
+		for _, method := range srv.Methods {
+			// WebSocket handshakes cannot be described by OpenAPI.
+			if !isGeneratedMethod(method) || isBidiStreaming(method) {
+				continue
+			}
+			if err := o.addMethod(method); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(o.doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+".openapi.json", "")
+	if _, err := g.Write(append(b, '\n')); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+type openAPIGenerator struct {
+	doc *openAPIDocument
+}
+
+// comment returns the text of the comment without leading slashes.
+func comment(c protogen.Comments) string {
+	return strings.TrimSpace(string(c))
+}
+
+// fieldComment returns the leading comment of the field, or the trailing comment if it does not exist.
+func fieldComment(field *protogen.Field) string {
+	if c := comment(field.Comments.Leading); c != "" {
+		return c
+	}
+	return comment(field.Comments.Trailing)
+}
+
+func (o *openAPIGenerator) addMethod(method *protogen.Method) error {
+	httpMethod, path := "POST", fullMethodName(method)
+	var pathParams []*openAPIParameter
+	var rule *annotations.HttpRule
+	var unregistered error
+	if httpRule, ok := getHTTPRule(method); ok {
+		if m, pattern, ok := httpRulePattern(httpRule); ok {
+			p, params, err := openAPIPath(method, pattern)
+			if err != nil {
+				return err
+			}
+			httpMethod, path, pathParams, rule = m, p, params, httpRule
+			// The handler of the pattern is served by other routers even if http.ServeMux cannot register it.
+			_, unregistered = serveMuxPattern(m, pattern)
+		}
+	}
+
+	summary, description := comment(method.Comments.Leading), ""
+	if i := strings.Index(summary, "\n"); i >= 0 {
+		summary, description = summary[:i], strings.TrimSpace(summary[i+1:])
+	}
+	if unregistered != nil {
+		note := fmt.Sprintf("Register%sHTTPHandlers does not register the operation on http.ServeMux (%v). Register %sHTTPRule on other routers to serve it.", method.Parent.GoName, unregistered, method.GoName)
+		description = strings.TrimSpace(description + "\n\n" + note)
+	}
+	op := &openAPIOperation{
+		Tags:        []string{method.Parent.GoName},
+		Summary:     summary,
+		Description: description,
+		OperationID: method.Parent.GoName + "_" + method.GoName,
+		Parameters:  pathParams,
+		Responses: map[string]*openAPIResponse{
+			"200": {
+				Description: "OK",
+				Content:     o.responseContent(method),
+			},
+			"default": {
+				Description: "Error",
+				Content: map[string]*openAPIMediaType{
+					"application/json": {Schema: &openAPISchema{Ref: schemaRef(statusSchemaName)}},
+				},
+			},
+		},
+	}
+
+	switch {
+	case rule == nil || method.Desc.IsStreamingClient():
+		op.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content:  o.requestContent(method),
+		}
+	case httpMethod == "GET" || rule.GetBody() == "":
+		op.Parameters = append(op.Parameters, o.queryParameters(method, rule)...)
+	case rule.GetBody() == "*":
+		op.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content:  o.requestContent(method),
+		}
+	default:
+		field, err := bodyField(method, rule)
+		if err != nil {
+			return err
+		}
+		op.Parameters = append(op.Parameters, o.queryParameters(method, rule)...)
+		op.RequestBody = &openAPIRequestBody{
+			Description: fieldComment(field),
+			Required:    true,
+			Content:     o.fieldContent(field),
+		}
+	}
+
+	item, ok := o.doc.Paths[path]
+	if !ok {
+		item = &openAPIPathItem{}
+		o.doc.Paths[path] = item
+	}
+	item.set(httpMethod, op)
+	// The handler also serves the additional bindings with the same path and body.
+	if rule != nil {
+		for _, m := range httpRuleMethods(rule)[1:] {
+			binding := *op
+			binding.OperationID = op.OperationID + "_" + m
+			item.set(m, &binding)
+		}
+	}
+	return nil
+}
+
+// set sets the operation of the HTTP method.
+func (item *openAPIPathItem) set(httpMethod string, op *openAPIOperation) {
+	switch httpMethod {
+	case "GET":
+		item.Get = op
+	case "PUT":
+		item.Put = op
+	case "POST":
+		item.Post = op
+	case "DELETE":
+		item.Delete = op
+	case "PATCH":
+		item.Patch = op
+	}
+}
+
+// openAPIPath converts the path template to the path of OpenAPI and returns it with the path parameters.
+// Variables are named by their field path, and wildcards without field path are named "_{index}" as http.ServeMux wildcards.
+func openAPIPath(method *protogen.Method, pattern string) (string, []*openAPIParameter, error) {
+	tokens, verb := tokenize(pattern[1:])
+	p := parser{tokens: tokens}
+	segs, err := p.topLevelSegments()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var b strings.Builder
+	var params []*openAPIParameter
+	for i, seg := range segs {
+		b.WriteString("/")
+		switch s := seg.(type) {
+		case literal:
+			b.WriteString(string(s))
+		case wildcard, deepWildcard:
+			name := fmt.Sprintf("_%d", i+1)
+			fmt.Fprintf(&b, "{%s}", name)
+			params = append(params, &openAPIParameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &openAPISchema{Type: "string"},
+			})
+		case variable:
+			fmt.Fprintf(&b, "{%s}", s.path)
+			param := &openAPIParameter{
+				Name:     s.path,
+				In:       "path",
+				Required: true,
+				Schema:   &openAPISchema{Type: "string"},
+			}
+			if field := findField(method.Input, s.path); field != nil {
+				param.Description = fieldComment(field)
+			}
+			if !isSingleSegment(s) {
+				param.Description = strings.TrimSpace(param.Description + "\n\nThe value may contain \"/\".")
+			}
+			params = append(params, param)
+		}
+	}
+	if verb != "" {
+		b.WriteString(":" + verb)
+	}
+	return b.String(), params, nil
+}
+
+// isSingleSegment reports whether the variable matches exactly one path segment, i.e., {name} or {name=*}.
+func isSingleSegment(v variable) bool {
+	if len(v.segments) != 1 {
+		return false
+	}
+	_, ok := v.segments[0].(wildcard)
+	return ok
+}
+
+// findField returns the field of the message specified by the field path such as "sub.subfield".
+func findField(msg *protogen.Message, path string) *protogen.Field {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		var found *protogen.Field
+		for _, field := range msg.Fields {
+			if string(field.Desc.Name()) == name {
+				found = field
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		if i == len(names)-1 {
+			return found
+		}
+		if found.Message == nil {
+			return nil
+		}
+		msg = found.Message
+	}
+	return nil
+}
+
+// queryParameters returns the query parameters of the rule read by genQueryString.
+func (o *openAPIGenerator) queryParameters(method *protogen.Method, rule *annotations.HttpRule) []*openAPIParameter {
+	_, pattern, _ := httpRulePattern(rule)
+	pathParams, err := parsePathParam(pattern)
+	if err != nil {
+		return nil
+	}
+
+	var params []*openAPIParameter
+	for _, q := range ruleQueryParams(method, rule, pathParams) {
+		schema := querySchema(q.Field)
+		if schema == nil {
+			continue
+		}
+		if q.Desc.IsList() {
+			schema = &openAPISchema{Type: "array", Items: schema}
+		}
+		params = append(params, &openAPIParameter{
+			Name:        q.Name,
+			In:          "query",
+			Description: fieldComment(q.Field),
+			Schema:      schema,
+		})
+	}
+	return params
+}
+
+// querySchema returns the schema of the query parameter, or nil if the converter does not read the field from the query string.
+func querySchema(field *protogen.Field) *openAPISchema {
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return &openAPISchema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &openAPISchema{Type: "integer", Format: "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &openAPISchema{Type: "integer", Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case protoreflect.StringKind:
+		return &openAPISchema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &openAPISchema{Type: "string", Format: "byte"}
+	default:
+		return nil
+	}
+}
+
+// requestContent returns the media types of the request body accepted by the converter.
+func (o *openAPIGenerator) requestContent(method *protogen.Method) map[string]*openAPIMediaType {
+	schema := o.messageSchema(method.Input)
+	if method.Desc.IsStreamingClient() {
+		return map[string]*openAPIMediaType{
+			"application/x-ndjson": {Schema: schema},
+			"application/protobuf": {Schema: &openAPISchema{Type: "string", Format: "binary", Description: "Protobuf messages prefixed by varint length."}},
+		}
+	}
+	return map[string]*openAPIMediaType{
+		"application/json":     {Schema: schema},
+		"application/protobuf": {Schema: &openAPISchema{Type: "string", Format: "binary"}},
+	}
+}
+
+// fieldContent returns the media types of the request body which is the field selected by body of google.api.HttpRule.
+func (o *openAPIGenerator) fieldContent(field *protogen.Field) map[string]*openAPIMediaType {
+	content := map[string]*openAPIMediaType{
+		"application/json": {Schema: o.fieldSchema(field)},
+	}
+	if isSingularMessage(field) {
+		content["application/protobuf"] = &openAPIMediaType{Schema: &openAPISchema{Type: "string", Format: "binary"}}
+	}
+	return content
+}
+
+// responseContent returns the media types of the response written by the converter.
+func (o *openAPIGenerator) responseContent(method *protogen.Method) map[string]*openAPIMediaType {
+	schema := o.messageSchema(method.Output)
+	if method.Desc.IsStreamingServer() {
+		return map[string]*openAPIMediaType{
+			"application/x-ndjson": {Schema: schema},
+			"application/protobuf": {Schema: &openAPISchema{Type: "string", Format: "binary", Description: "Protobuf messages prefixed by varint length."}},
+			"text/event-stream":    {Schema: &openAPISchema{Type: "string", Description: "Server-Sent Events whose data is a message in JSON."}},
+		}
+	}
+	return map[string]*openAPIMediaType{
+		"application/json":     {Schema: schema},
+		"application/protobuf": {Schema: &openAPISchema{Type: "string", Format: "binary"}},
+	}
+}
+
+func schemaRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+// wellKnownSchema returns the schema of the well-known type in the JSON mapping of protojson.
+func wellKnownSchema(msg *protogen.Message) (*openAPISchema, bool) {
+	switch msg.Desc.FullName() {
+	case "google.protobuf.Timestamp":
+		return &openAPISchema{Type: "string", Format: "date-time"}, true
+	case "google.protobuf.Duration":
+		return &openAPISchema{Type: "string", Description: "Duration in seconds with the suffix \"s\", e.g., \"1.5s\"."}, true
+	case "google.protobuf.FieldMask":
+		return &openAPISchema{Type: "string", Description: "Comma-separated field paths in lowerCamelCase."}, true
+	case "google.protobuf.Empty":
+		return &openAPISchema{Type: "object"}, true
+	case "google.protobuf.Struct":
+		return &openAPISchema{Type: "object", AdditionalProperties: &openAPISchema{}}, true
+	case "google.protobuf.Value":
+		return &openAPISchema{}, true
+	case "google.protobuf.ListValue":
+		return &openAPISchema{Type: "array", Items: &openAPISchema{}}, true
+	case "google.protobuf.Any":
+		return &openAPISchema{
+			Type: "object",
+			Properties: map[string]*openAPISchema{
+				"@type": {Type: "string"},
+			},
+			AdditionalProperties: &openAPISchema{},
+		}, true
+	case "google.protobuf.BoolValue":
+		return &openAPISchema{Type: "boolean"}, true
+	case "google.protobuf.Int32Value":
+		return &openAPISchema{Type: "integer", Format: "int32"}, true
+	case "google.protobuf.UInt32Value":
+		return &openAPISchema{Type: "integer", Format: "uint32"}, true
+	case "google.protobuf.Int64Value":
+		return &openAPISchema{Type: "string", Format: "int64"}, true
+	case "google.protobuf.UInt64Value":
+		return &openAPISchema{Type: "string", Format: "uint64"}, true
+	case "google.protobuf.FloatValue":
+		return &openAPISchema{Type: "number", Format: "float"}, true
+	case "google.protobuf.DoubleValue":
+		return &openAPISchema{Type: "number", Format: "double"}, true
+	case "google.protobuf.StringValue":
+		return &openAPISchema{Type: "string"}, true
+	case "google.protobuf.BytesValue":
+		return &openAPISchema{Type: "string", Format: "byte"}, true
+	default:
+		return nil, false
+	}
+}
+
+// messageSchema returns the schema referring to the message, adding the schema of the message to the components.
+func (o *openAPIGenerator) messageSchema(msg *protogen.Message) *openAPISchema {
+	if schema, ok := wellKnownSchema(msg); ok {
+		return schema
+	}
+
+	name := string(msg.Desc.FullName())
+	if _, ok := o.doc.Components.Schemas[name]; !ok {
+		schema := &openAPISchema{
+			Type:        "object",
+			Description: comment(msg.Comments.Leading),
+			Properties:  map[string]*openAPISchema{},
+		}
+		// Register before the fields to stop the recursion of recursive messages.
+		o.doc.Components.Schemas[name] = schema
+		for _, field := range msg.Fields {
+			schema.Properties[field.Desc.JSONName()] = o.fieldSchema(field)
+		}
+	}
+	return &openAPISchema{Ref: schemaRef(name)}
+}
+
+// fieldSchema returns the schema of the field in the JSON mapping of protojson.
+func (o *openAPIGenerator) fieldSchema(field *protogen.Field) *openAPISchema {
+	if field.Desc.IsMap() {
+		return &openAPISchema{
+			Type:                 "object",
+			Description:          fieldComment(field),
+			AdditionalProperties: o.singularSchema(field.Message.Fields[1]),
+		}
+	}
+
+	schema := o.singularSchema(field)
+	if field.Desc.IsList() {
+		schema = &openAPISchema{Type: "array", Items: schema}
+	}
+	// Siblings of $ref are ignored, so the description is not added to the reference.
+	if schema.Ref == "" {
+		schema.Description = fieldComment(field)
+	}
+	return schema
+}
+
+func (o *openAPIGenerator) singularSchema(field *protogen.Field) *openAPISchema {
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return &openAPISchema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &openAPISchema{Type: "integer", Format: "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// protojson encodes 64-bit integers as strings.
+		return &openAPISchema{Type: "string", Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &openAPISchema{Type: "string", Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case protoreflect.StringKind:
+		return &openAPISchema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &openAPISchema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		schema := &openAPISchema{Type: "string"}
+		for _, v := range field.Enum.Values {
+			schema.Enum = append(schema.Enum, string(v.Desc.Name()))
+		}
+		return schema
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return o.messageSchema(field.Message)
+	default:
+		return &openAPISchema{}
+	}
+}
+
+// openAPIVarName returns the name of the variable embedding the OpenAPI document of the proto file.
+func openAPIVarName(protoPath string) string {
+	return fileVarName(protoPath, "openAPI")
+}
+
+// genOpenAPIEmbed generates the variable embedding the OpenAPI document generated by GenerateOpenAPI,
+// which is placed in the same directory as the generated Go file.
+func genOpenAPIEmbed(g *protogen.GeneratedFile, file *protogen.File) {
+	g.Import(embedPackage)
+	g.P("//go:embed ", path.Base(file.GeneratedFilenamePrefix), ".openapi.json")
+	g.P("var ", openAPIVarName(file.Desc.Path()), " []byte")
+}
+
+// genOpenAPIHandler generates the function serving the OpenAPI document of the file that declares the service.
+// It is not a method of the converter because the document does not depend on it, and a method may collide with RPCs.
+func genOpenAPIHandler(g *protogen.GeneratedFile, srv *protogen.Service) {
+	file := srv.Location.SourceFile
+	g.P("// ", srv.GoName, "HTTPOpenAPIHandler returns http.Handler serving OpenAPI document of ", file, ".")
+	g.P("func ", srv.GoName, "HTTPOpenAPIHandler() ", httpPackage.Ident("Handler"), " {")
+	g.P("	return ", httpPackage.Ident("HandlerFunc"), "(func(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ") {")
+	g.P("		w.Header().Set(\"Content-Type\", \"application/json\")")
+	g.P("		w.Write(", openAPIVarName(file), ")")
+	g.P("	})")
+	g.P("}")
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// panicHandlerSignature returns the type of the hook reporting panics recovered by the converter.
+func panicHandlerSignature(g *protogen.GeneratedFile) string {
+	return "func(ctx " + g.QualifiedGoIdent(contextPackage.Ident("Context")) + ", fullMethod string, p interface{}, stack []byte)"
+}
+
+// genRecoveryOptions generates the option enabling the panic recovery.
+func genRecoveryOptions(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// With", srv.GoName, "HTTPRecovery enables the recovery of panics in the RPC and the interceptors. The panic is converted to")
+	g.P("// *", srv.GoName, "HTTPPanicError of codes.Internal passed to the callback, and reported to handler with the stack trace")
+	g.P("// unless handler is nil. Panics are not recovered by default.")
+	g.P("func With", srv.GoName, "HTTPRecovery(handler ", panicHandlerSignature(g), ") ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.recovery = true")
+	g.P("		h.panicHandler = handler")
+	g.P("	}")
+	g.P("}")
+}
+
+// genRecoveryInterceptors generates the code prepending the interceptor recovering panics if the recovery is enabled.
+func genRecoveryInterceptors(g *protogen.GeneratedFile, method *protogen.Method) {
+	g.P("if h.recovery {")
+	if isStreaming(method) {
+		g.P("	interceptors = append([]", grpcPackage.Ident("StreamServerInterceptor"), "{h.recoverStream}, interceptors...)")
+	} else {
+		g.P("	interceptors = append([]", grpcPackage.Ident("UnaryServerInterceptor"), "{h.recoverUnary}, interceptors...)")
+	}
+	g.P("}")
+}
+
+// genRecovery generates the error type of recovered panics and the interceptors recovering them.
+func genRecovery(g *protogen.GeneratedFile, srv *protogen.Service) {
+	name := srv.GoName + "HTTPConverter"
+	errName := srv.GoName + "HTTPPanicError"
+	g.P("// ", errName, " is the error of a panic recovered by ", name, ".")
+	g.P("type ", errName, " struct {")
+	g.P("	// Value is the value passed to panic.")
+	g.P("	Value interface{}")
+	g.P("	// Stack is the stack trace of the goroutine panicked.")
+	g.P("	Stack []byte")
+	g.P("}")
+	g.P()
+	g.P("func (e *", errName, ") Error() string {")
+	g.P("	return ", fmtPackage.Ident("Sprintf"), "(\"panic: %v\", e.Value)")
+	g.P("}")
+	g.P()
+	g.P("// GRPCStatus returns the status of codes.Internal, so that status.Code returns codes.Internal for the error.")
+	g.P("// The message of the status does not contain the panic value, which must not be sent to clients.")
+	g.P("func (e *", errName, ") GRPCStatus() *", statusPackage.Ident("Status"), " {")
+	g.P("	return ", statusPackage.Ident("New"), "(", codesPackage.Ident("Internal"), ", \"internal error\")")
+	g.P("}")
+	g.P()
+	g.P("// recovered reports the panic p to the panic handler and returns it as *", errName, ".")
+	g.P("func (h *", name, ") recovered(ctx ", contextPackage.Ident("Context"), ", fullMethod string, p interface{}) error {")
+	g.P("	err := &", errName, "{Value: p, Stack: ", debugPackage.Ident("Stack"), "()}")
+	g.P("	if h.panicHandler != nil {")
+	g.P("		h.panicHandler(ctx, fullMethod, p, err.Stack)")
+	g.P("	}")
+	g.P("	return err")
+	g.P("}")
+	g.P()
+	g.P("// recoverUnary is the outermost interceptor recovering panics in the RPC and the interceptors.")
+	g.P("func (h *", name, ") recoverUnary(ctx ", contextPackage.Ident("Context"), ", req interface{}, info *", grpcPackage.Ident("UnaryServerInfo"), ", handler ", grpcPackage.Ident("UnaryHandler"), ") (resp interface{}, err error) {")
+	g.P("	defer func() {")
+	g.P("		if p := recover(); p != nil {")
+	g.P("			err = h.recovered(ctx, info.FullMethod, p)")
+	g.P("		}")
+	g.P("	}()")
+	g.P("	return handler(ctx, req)")
+	g.P("}")
+	if hasStreamingMethod(srv) {
+		g.P()
+		g.P("// recoverStream is the outermost stream interceptor recovering panics in the RPC and the interceptors.")
+		g.P("func (h *", name, ") recoverStream(srv interface{}, ss ", grpcPackage.Ident("ServerStream"), ", info *", grpcPackage.Ident("StreamServerInfo"), ", handler ", grpcPackage.Ident("StreamHandler"), ") (err error) {")
+		g.P("	defer func() {")
+		g.P("		if p := recover(); p != nil {")
+		g.P("			err = h.recovered(ss.Context(), info.FullMethod, p)")
+		g.P("		}")
+		g.P("	}()")
+		g.P("	return handler(srv, ss)")
+		g.P("}")
+	}
+}
@@ -0,0 +1,2087 @@
+// Code generated by protoc-gen-gohttp. DO NOT EDIT.
+// source: collision/collision.proto
+
+package collisionpb
+
+import (
+	bytes "bytes"
+	gzip "compress/gzip"
+	zlib "compress/zlib"
+	context "context"
+	_ "embed"
+	base64 "encoding/base64"
+	json "encoding/json"
+	errors "errors"
+	fmt "fmt"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	credentials "google.golang.org/grpc/credentials"
+	metadata "google.golang.org/grpc/metadata"
+	peer "google.golang.org/grpc/peer"
+	status "google.golang.org/grpc/status"
+	protojson "google.golang.org/protobuf/encoding/protojson"
+	proto "google.golang.org/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	io "io"
+	ioutil "io/ioutil"
+	slog "log/slog"
+	math "math"
+	mime "mime"
+	net "net"
+	http "net/http"
+	url "net/url"
+	debug "runtime/debug"
+	strconv "strconv"
+	strings "strings"
+	sync "sync"
+	time "time"
+)
+
+// NavHTTPService is the server API for Nav service.
+type NavHTTPService interface {
+	Routes(context.Context, *RoutesRequest) (*RoutesResponse, error)
+	RoutesHandler(context.Context, *RoutesRequest) (*RoutesResponse, error)
+	OpenAPIHandler(context.Context, *RoutesRequest) (*RoutesResponse, error)
+}
+
+// NavHTTPTracer is called at the start and the finish of every request handled by NavHTTPConverter,
+// e.g. to start and end a span of a tracing library without depending on it.
+type NavHTTPTracer interface {
+	// OnStart is called when the handler of method starts handling r. route is the path template of google.api.http option,
+	// or empty if the handler is not returned by the HTTPRule method. The returned context is passed to the RPC and OnFinish.
+	OnStart(ctx context.Context, method protoreflect.MethodDescriptor, route string, r *http.Request) context.Context
+	// OnFinish is called after the callback with the arguments passed to the callback, the status code of the response
+	// and the time elapsed since OnStart. The gRPC code of the RPC is status.Code(err).
+	OnFinish(ctx context.Context, method protoreflect.MethodDescriptor, arg, ret proto.Message, statusCode int, err error, elapsed time.Duration)
+}
+
+// NavHTTPObserver observes the metrics of every request handled by NavHTTPConverter,
+// e.g. to export the latency, the sizes and the status of RPCs to a metrics library without depending on it.
+type NavHTTPObserver interface {
+	// Observe is called after the callback with the method, the path template of google.api.http option
+	// (empty if the handler is not returned by the HTTPRule method), the status code of the response,
+	// the gRPC code of the RPC, the bytes read from the request body and written to the response body, and the latency.
+	Observe(ctx context.Context, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, requestSize, responseSize int64, elapsed time.Duration)
+}
+
+// NavHTTPConverter has a function to convert NavHTTPService interface to http.HandlerFunc.
+type NavHTTPConverter struct {
+	srv                   NavHTTPService
+	cb                    func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)
+	interceptors          []grpc.UnaryServerInterceptor
+	maxBodySize           int64
+	skipMethodCheck       bool
+	incomingHeaders       []string
+	incomingHeaderPrefix  string
+	outgoingHeaders       []string
+	outgoingHeaderPrefix  string
+	outgoingTrailerPrefix string
+	timeoutHeader         string
+	tracer                NavHTTPTracer
+	observer              NavHTTPObserver
+	accessLogger          *slog.Logger
+	accessLogPayloads     bool
+	recovery              bool
+	panicHandler          func(ctx context.Context, fullMethod string, p interface{}, stack []byte)
+	compressionMinSize    int
+}
+
+// NavHTTPConverterOption configures NavHTTPConverter.
+type NavHTTPConverterOption func(*NavHTTPConverter)
+
+// WithNavHTTPCallback sets the callback used when nil is passed to a convert method.
+func WithNavHTTPCallback(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.cb = cb
+	}
+}
+
+// WithNavHTTPInterceptors appends interceptors executed before the interceptors passed to a convert method.
+func WithNavHTTPInterceptors(interceptors ...grpc.UnaryServerInterceptor) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.interceptors = append(h.interceptors, interceptors...)
+	}
+}
+
+// WithNavHTTPMaxBodySize sets the maximum size of request bodies in bytes.
+// The default is 4194304 bytes set by max_body_size option of protoc-gen-gohttp. Zero or a negative value means no limit.
+// Requests with larger bodies fail with *http.MaxBytesError, and the default callback responds 413 Request Entity Too Large.
+func WithNavHTTPMaxBodySize(n int64) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.maxBodySize = n
+	}
+}
+
+// WithNavHTTPSkipMethodCheck sets whether the handlers returned by HTTPRule methods skip checking the request method.
+// By default, the handlers respond 405 Method Not Allowed with Allow header if the request method does not match google.api.http option.
+// Routers that already route requests by the method can skip the check.
+func WithNavHTTPSkipMethodCheck(skip bool) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.skipMethodCheck = skip
+	}
+}
+
+// WithNavHTTPIncomingHeaders sets the request headers passed to the RPC as incoming metadata.
+// The default is Authorization. The metadata keys are the lowercase header names.
+func WithNavHTTPIncomingHeaders(headers ...string) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.incomingHeaders = headers
+	}
+}
+
+// WithNavHTTPIncomingHeaderPrefix sets the prefix of the request headers passed to the RPC as incoming metadata.
+// The metadata keys are the lowercase header names without the prefix. The default is "Grpc-Metadata-",
+// and the empty prefix disables it.
+func WithNavHTTPIncomingHeaderPrefix(prefix string) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.incomingHeaderPrefix = prefix
+	}
+}
+
+// WithNavHTTPOutgoingHeaders sets the response headers written from the header metadata with the same
+// lowercase names, e.g. "location" for Location. They are written without the prefix. The default is Location.
+func WithNavHTTPOutgoingHeaders(headers ...string) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.outgoingHeaders = headers
+	}
+}
+
+// WithNavHTTPOutgoingHeaderPrefix sets the prefix of the response headers written from the header metadata
+// set by grpc.SetHeader or grpc.SendHeader. The default is "Grpc-Metadata-".
+func WithNavHTTPOutgoingHeaderPrefix(prefix string) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.outgoingHeaderPrefix = prefix
+	}
+}
+
+// WithNavHTTPOutgoingTrailerPrefix sets the prefix of the response headers or trailers written from the trailer metadata
+// set by grpc.SetTrailer. The default is "Grpc-Trailer-".
+func WithNavHTTPOutgoingTrailerPrefix(prefix string) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.outgoingTrailerPrefix = prefix
+	}
+}
+
+// WithNavHTTPTimeoutHeader sets the request header of the timeout of the RPC in addition to Grpc-Timeout header.
+// The value is parsed by time.ParseDuration, e.g. "1.5s". The empty name disables it, which is the default.
+func WithNavHTTPTimeoutHeader(name string) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.timeoutHeader = name
+	}
+}
+
+// WithNavHTTPTracer sets the tracer called at the start and the finish of every request.
+func WithNavHTTPTracer(tracer NavHTTPTracer) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.tracer = tracer
+	}
+}
+
+// WithNavHTTPObserver sets the observer called at the finish of every request.
+func WithNavHTTPObserver(observer NavHTTPObserver) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.observer = observer
+	}
+}
+
+// WithNavHTTPAccessLog sets the logger emitting an access log record of every request with the full method,
+// the HTTP method, the route, the status code, the gRPC code and the latency. Requests responded with 5xx are logged
+// at error level, and the others are logged at info level. The access log is disabled by default.
+func WithNavHTTPAccessLog(logger *slog.Logger) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.accessLogger = logger
+	}
+}
+
+// WithNavHTTPAccessLogPayloads sets whether the access log contains the request and response messages in JSON.
+// Fields marked with debug_redact option are removed from the messages.
+func WithNavHTTPAccessLogPayloads(payloads bool) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.accessLogPayloads = payloads
+	}
+}
+
+// WithNavHTTPRecovery enables the recovery of panics in the RPC and the interceptors. The panic is converted to
+// *NavHTTPPanicError of codes.Internal passed to the callback, and reported to handler with the stack trace
+// unless handler is nil. Panics are not recovered by default.
+func WithNavHTTPRecovery(handler func(ctx context.Context, fullMethod string, p interface{}, stack []byte)) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.recovery = true
+		h.panicHandler = handler
+	}
+}
+
+// WithNavHTTPCompression enables the compression of response bodies of unary and client-side streaming RPCs
+// with gzip or deflate negotiated by Accept-Encoding header. Only bodies of at least minSize bytes are compressed.
+// A negative value disables it, which is the default.
+func WithNavHTTPCompression(minSize int) NavHTTPConverterOption {
+	return func(h *NavHTTPConverter) {
+		h.compressionMinSize = minSize
+	}
+}
+
+// NewNavHTTPConverter returns NavHTTPConverter.
+func NewNavHTTPConverter(srv NavHTTPService, opts ...NavHTTPConverterOption) *NavHTTPConverter {
+	h := &NavHTTPConverter{
+		srv:                   srv,
+		maxBodySize:           4194304,
+		incomingHeaders:       []string{"Authorization"},
+		incomingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingHeaders:       []string{"Location"},
+		outgoingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingTrailerPrefix: "Grpc-Trailer-",
+		compressionMinSize:    -1,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// trace calls OnStart of the tracer, and returns the context returned by OnStart, w recording the response
+// and cb calling OnFinish of the tracer, Observe of the observer and the access logger after cb.
+// The body of r is replaced to count the bytes read.
+func (h *NavHTTPConverter) trace(ctx context.Context, w http.ResponseWriter, r *http.Request, name protoreflect.Name, route string, cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) (context.Context, http.ResponseWriter, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) {
+	method := File_collision_collision_proto.Services().ByName("Nav").Methods().ByName(name)
+	start := time.Now()
+	if h.tracer != nil {
+		ctx = h.tracer.OnStart(ctx, method, route, r)
+	}
+	rw := &file_collision_collision_proto_httpResponseWriter{ResponseWriter: w}
+	body := &file_collision_collision_proto_httpRequestBody{ReadCloser: r.Body}
+	r.Body = body
+	return ctx, rw, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+		cb(ctx, w, r, arg, ret, err)
+		elapsed := time.Since(start)
+		if h.tracer != nil {
+			h.tracer.OnFinish(ctx, method, arg, ret, rw.status(), err, elapsed)
+		}
+		code := status.Code(err)
+		switch {
+		case code != codes.Unknown:
+		case errors.Is(err, context.DeadlineExceeded):
+			code = codes.DeadlineExceeded
+		case errors.Is(err, context.Canceled):
+			code = codes.Canceled
+		}
+		if h.observer != nil {
+			h.observer.Observe(ctx, method, route, rw.status(), code, body.size, rw.size, elapsed)
+		}
+		if h.accessLogger != nil {
+			h.logAccess(ctx, r, method, route, rw.status(), code, err, elapsed, arg, ret)
+		}
+	}
+}
+
+// logAccess emits the access log record of the request.
+func (h *NavHTTPConverter) logAccess(ctx context.Context, r *http.Request, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, err error, elapsed time.Duration, arg, ret proto.Message) {
+	level := slog.LevelInfo
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	attrs := []slog.Attr{
+		slog.String("method", fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())),
+		slog.String("http_method", r.Method),
+		slog.String("route", route),
+		slog.Int("status", statusCode),
+		slog.String("code", code.String()),
+		slog.Duration("latency", elapsed),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if h.accessLogPayloads {
+		if arg != nil {
+			attrs = append(attrs, slog.String("request", file_collision_collision_proto_redact(arg)))
+		}
+		if ret != nil {
+			attrs = append(attrs, slog.String("response", file_collision_collision_proto_redact(ret)))
+		}
+	}
+	h.accessLogger.LogAttrs(ctx, level, "access", attrs...)
+}
+
+// NavHTTPPanicError is the error of a panic recovered by NavHTTPConverter.
+type NavHTTPPanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine panicked.
+	Stack []byte
+}
+
+func (e *NavHTTPPanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// GRPCStatus returns the status of codes.Internal, so that status.Code returns codes.Internal for the error.
+// The message of the status does not contain the panic value, which must not be sent to clients.
+func (e *NavHTTPPanicError) GRPCStatus() *status.Status {
+	return status.New(codes.Internal, "internal error")
+}
+
+// recovered reports the panic p to the panic handler and returns it as *NavHTTPPanicError.
+func (h *NavHTTPConverter) recovered(ctx context.Context, fullMethod string, p interface{}) error {
+	err := &NavHTTPPanicError{Value: p, Stack: debug.Stack()}
+	if h.panicHandler != nil {
+		h.panicHandler(ctx, fullMethod, p, err.Stack)
+	}
+	return err
+}
+
+// recoverUnary is the outermost interceptor recovering panics in the RPC and the interceptors.
+func (h *NavHTTPConverter) recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = h.recovered(ctx, info.FullMethod, p)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// Routes returns NavHTTPService interface's Routes converted to http.HandlerFunc.
+func (h *NavHTTPConverter) Routes(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
+	if cb == nil {
+		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			if err != nil {
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
+				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
+				case "application/protobuf", "application/x-protobuf":
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				case "application/json":
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				default:
+				}
+			}
+		}
+	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if md := file_collision_collision_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_collision_collision_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_collision_collision_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Routes", "", cb)
+		}
+		ts := &file_collision_collision_proto_httpTransportStream{
+			method:        "/collision.Nav/Routes",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		accepts := strings.Split(r.Header.Get("Accept"), ",")
+		accept := accepts[0]
+		if accept == "*/*" || accept == "" {
+			if contentType != "" {
+				accept = contentType
+			} else {
+				accept = "application/json"
+			}
+		}
+
+		w.Header().Set("Content-Type", accept)
+
+		arg := &RoutesRequest{}
+		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_collision_collision_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+
+			switch contentType {
+			case "application/protobuf", "application/x-protobuf":
+				if err := proto.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			case "application/json":
+				if err := protojson.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Type: %s", contentType)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+		}
+
+		n := len(interceptors)
+		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			chainer := func(currentInter grpc.UnaryServerInterceptor, currentHandler grpc.UnaryHandler) grpc.UnaryHandler {
+				return func(currentCtx context.Context, currentReq interface{}) (interface{}, error) {
+					return currentInter(currentCtx, currentReq, info, currentHandler)
+				}
+			}
+
+			chainedHandler := handler
+			for i := n - 1; i >= 0; i-- {
+				chainedHandler = chainer(interceptors[i], chainedHandler)
+			}
+			return chainedHandler(ctx, arg)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     h.srv,
+			FullMethod: "/collision.Nav/Routes",
+		}
+
+		handler := func(c context.Context, req interface{}) (interface{}, error) {
+			return h.srv.Routes(c, req.(*RoutesRequest))
+		}
+
+		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
+		if err != nil {
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+
+		ret, ok := iret.(*RoutesResponse)
+		if !ok {
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/collision.Nav/Routes: interceptors have not return RoutesResponse"))
+			return
+		}
+
+		switch accept {
+		case "application/protobuf", "application/x-protobuf":
+			buf, err := proto.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_collision_collision_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		case "application/json":
+			buf, err := protojson.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_collision_collision_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err := fmt.Fprintf(w, "Unsupported Accept: %s", accept)
+			cb(ctx, w, r, arg, ret, err)
+			return
+		}
+		cb(ctx, w, r, arg, ret, nil)
+	})
+}
+
+// RoutesWithName returns Service name, Method name and NavHTTPService interface's Routes converted to http.HandlerFunc.
+func (h *NavHTTPConverter) RoutesWithName(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	return "Nav", "Routes", h.Routes(cb, interceptors...)
+}
+
+// RoutesHTTPRule returns HTTP method, path and NavHTTPService interface's Routes converted to http.HandlerFunc.
+func (h *NavHTTPConverter) RoutesHTTPRule(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	if cb == nil {
+		cb = h.cb
+	}
+	if cb == nil {
+		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			if err != nil {
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
+				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
+				case "application/protobuf", "application/x-protobuf":
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				case "application/json":
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				default:
+				}
+			}
+		}
+	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
+	return http.MethodGet, "/v1/routes", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if md := file_collision_collision_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_collision_collision_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_collision_collision_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Routes", "/v1/routes", cb)
+		}
+		ts := &file_collision_collision_proto_httpTransportStream{
+			method:        "/collision.Nav/Routes",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		accepts := strings.Split(r.Header.Get("Accept"), ",")
+		accept := accepts[0]
+		if accept == "*/*" || accept == "" {
+			if contentType != "" {
+				accept = contentType
+			} else {
+				accept = "application/json"
+			}
+		}
+
+		w.Header().Set("Content-Type", accept)
+
+		if !h.skipMethodCheck && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, err := fmt.Fprintf(w, "Method Not Allowed: %s", r.Method)
+			cb(ctx, w, r, nil, nil, err)
+			return
+		}
+
+		arg := &RoutesRequest{}
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			if v := r.URL.Query().Get("from"); v != "" {
+				arg.From = v
+			}
+			if v := r.URL.Query().Get("to"); v != "" {
+				arg.To = v
+			}
+		}
+
+		n := len(interceptors)
+		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			chainer := func(currentInter grpc.UnaryServerInterceptor, currentHandler grpc.UnaryHandler) grpc.UnaryHandler {
+				return func(currentCtx context.Context, currentReq interface{}) (interface{}, error) {
+					return currentInter(currentCtx, currentReq, info, currentHandler)
+				}
+			}
+
+			chainedHandler := handler
+			for i := n - 1; i >= 0; i-- {
+				chainedHandler = chainer(interceptors[i], chainedHandler)
+			}
+			return chainedHandler(ctx, arg)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     h.srv,
+			FullMethod: "/collision.Nav/Routes",
+		}
+
+		handler := func(c context.Context, req interface{}) (interface{}, error) {
+			return h.srv.Routes(c, req.(*RoutesRequest))
+		}
+
+		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
+		if err != nil {
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+
+		ret, ok := iret.(*RoutesResponse)
+		if !ok {
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/collision.Nav/Routes: interceptors have not return RoutesResponse"))
+			return
+		}
+
+		switch accept {
+		case "application/protobuf", "application/x-protobuf":
+			buf, err := proto.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_collision_collision_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		case "application/json":
+			buf, err := protojson.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_collision_collision_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err := fmt.Fprintf(w, "Unsupported Accept: %s", accept)
+			cb(ctx, w, r, arg, ret, err)
+			return
+		}
+		cb(ctx, w, r, arg, ret, nil)
+	})
+}
+
+// RoutesHandler returns NavHTTPService interface's RoutesHandler converted to http.HandlerFunc.
+func (h *NavHTTPConverter) RoutesHandler(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
+	if cb == nil {
+		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			if err != nil {
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
+				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
+				case "application/protobuf", "application/x-protobuf":
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				case "application/json":
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				default:
+				}
+			}
+		}
+	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if md := file_collision_collision_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_collision_collision_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_collision_collision_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "RoutesHandler", "", cb)
+		}
+		ts := &file_collision_collision_proto_httpTransportStream{
+			method:        "/collision.Nav/RoutesHandler",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		accepts := strings.Split(r.Header.Get("Accept"), ",")
+		accept := accepts[0]
+		if accept == "*/*" || accept == "" {
+			if contentType != "" {
+				accept = contentType
+			} else {
+				accept = "application/json"
+			}
+		}
+
+		w.Header().Set("Content-Type", accept)
+
+		arg := &RoutesRequest{}
+		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_collision_collision_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+
+			switch contentType {
+			case "application/protobuf", "application/x-protobuf":
+				if err := proto.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			case "application/json":
+				if err := protojson.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Type: %s", contentType)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+		}
+
+		n := len(interceptors)
+		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			chainer := func(currentInter grpc.UnaryServerInterceptor, currentHandler grpc.UnaryHandler) grpc.UnaryHandler {
+				return func(currentCtx context.Context, currentReq interface{}) (interface{}, error) {
+					return currentInter(currentCtx, currentReq, info, currentHandler)
+				}
+			}
+
+			chainedHandler := handler
+			for i := n - 1; i >= 0; i-- {
+				chainedHandler = chainer(interceptors[i], chainedHandler)
+			}
+			return chainedHandler(ctx, arg)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     h.srv,
+			FullMethod: "/collision.Nav/RoutesHandler",
+		}
+
+		handler := func(c context.Context, req interface{}) (interface{}, error) {
+			return h.srv.RoutesHandler(c, req.(*RoutesRequest))
+		}
+
+		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
+		if err != nil {
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+
+		ret, ok := iret.(*RoutesResponse)
+		if !ok {
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/collision.Nav/RoutesHandler: interceptors have not return RoutesResponse"))
+			return
+		}
+
+		switch accept {
+		case "application/protobuf", "application/x-protobuf":
+			buf, err := proto.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_collision_collision_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		case "application/json":
+			buf, err := protojson.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_collision_collision_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err := fmt.Fprintf(w, "Unsupported Accept: %s", accept)
+			cb(ctx, w, r, arg, ret, err)
+			return
+		}
+		cb(ctx, w, r, arg, ret, nil)
+	})
+}
+
+// RoutesHandlerWithName returns Service name, Method name and NavHTTPService interface's RoutesHandler converted to http.HandlerFunc.
+func (h *NavHTTPConverter) RoutesHandlerWithName(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	return "Nav", "RoutesHandler", h.RoutesHandler(cb, interceptors...)
+}
+
+// OpenAPIHandler returns NavHTTPService interface's OpenAPIHandler converted to http.HandlerFunc.
+func (h *NavHTTPConverter) OpenAPIHandler(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
+	if cb == nil {
+		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			if err != nil {
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
+				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
+				case "application/protobuf", "application/x-protobuf":
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				case "application/json":
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				default:
+				}
+			}
+		}
+	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if md := file_collision_collision_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_collision_collision_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_collision_collision_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "OpenAPIHandler", "", cb)
+		}
+		ts := &file_collision_collision_proto_httpTransportStream{
+			method:        "/collision.Nav/OpenAPIHandler",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		accepts := strings.Split(r.Header.Get("Accept"), ",")
+		accept := accepts[0]
+		if accept == "*/*" || accept == "" {
+			if contentType != "" {
+				accept = contentType
+			} else {
+				accept = "application/json"
+			}
+		}
+
+		w.Header().Set("Content-Type", accept)
+
+		arg := &RoutesRequest{}
+		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_collision_collision_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+
+			switch contentType {
+			case "application/protobuf", "application/x-protobuf":
+				if err := proto.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			case "application/json":
+				if err := protojson.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Type: %s", contentType)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+		}
+
+		n := len(interceptors)
+		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			chainer := func(currentInter grpc.UnaryServerInterceptor, currentHandler grpc.UnaryHandler) grpc.UnaryHandler {
+				return func(currentCtx context.Context, currentReq interface{}) (interface{}, error) {
+					return currentInter(currentCtx, currentReq, info, currentHandler)
+				}
+			}
+
+			chainedHandler := handler
+			for i := n - 1; i >= 0; i-- {
+				chainedHandler = chainer(interceptors[i], chainedHandler)
+			}
+			return chainedHandler(ctx, arg)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     h.srv,
+			FullMethod: "/collision.Nav/OpenAPIHandler",
+		}
+
+		handler := func(c context.Context, req interface{}) (interface{}, error) {
+			return h.srv.OpenAPIHandler(c, req.(*RoutesRequest))
+		}
+
+		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
+		if err != nil {
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+
+		ret, ok := iret.(*RoutesResponse)
+		if !ok {
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/collision.Nav/OpenAPIHandler: interceptors have not return RoutesResponse"))
+			return
+		}
+
+		switch accept {
+		case "application/protobuf", "application/x-protobuf":
+			buf, err := proto.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_collision_collision_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		case "application/json":
+			buf, err := protojson.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_collision_collision_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err := fmt.Fprintf(w, "Unsupported Accept: %s", accept)
+			cb(ctx, w, r, arg, ret, err)
+			return
+		}
+		cb(ctx, w, r, arg, ret, nil)
+	})
+}
+
+// OpenAPIHandlerWithName returns Service name, Method name and NavHTTPService interface's OpenAPIHandler converted to http.HandlerFunc.
+func (h *NavHTTPConverter) OpenAPIHandlerWithName(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	return "Nav", "OpenAPIHandler", h.OpenAPIHandler(cb, interceptors...)
+}
+
+// RegisterNavHTTPHandlers registers all methods of NavHTTPService on mux.
+// Methods with google.api.http option are registered with its HTTP method and path,
+// other methods are registered with POST /{package}.{Service}/{Method}.
+func RegisterNavHTTPHandlers(mux *http.ServeMux, conv *NavHTTPConverter) {
+	var hf http.HandlerFunc
+	_, _, hf = conv.RoutesHTTPRule(nil)
+	mux.Handle("GET /v1/routes", hf)
+	mux.Handle("POST /collision.Nav/RoutesHandler", conv.RoutesHandler(nil))
+	mux.Handle("POST /collision.Nav/OpenAPIHandler", conv.OpenAPIHandler(nil))
+}
+
+// NavHTTPRoute is a route of NavHTTPService method.
+type NavHTTPRoute struct {
+	// Method is HTTP method of the route.
+	Method string
+	// Pattern is path template of google.api.http option, or /{package}.{Service}/{Method} if the option is not defined.
+	Pattern string
+	// Handler is NavHTTPService method converted to http.HandlerFunc.
+	Handler http.HandlerFunc
+	// FullMethod is the full RPC method string, i.e., /package.service/method.
+	FullMethod string
+}
+
+// NavHTTPRouter is the interface of HTTP routers that NavHTTPRoute is registered on.
+type NavHTTPRouter interface {
+	Handle(method, pattern string, handler http.Handler)
+}
+
+// NavHTTPRouterFunc is an adapter to use a function as NavHTTPRouter.
+type NavHTTPRouterFunc func(method, pattern string, handler http.Handler)
+
+// Handle calls f(method, pattern, handler).
+func (f NavHTTPRouterFunc) Handle(method, pattern string, handler http.Handler) {
+	f(method, pattern, handler)
+}
+
+// NavHTTPRoutes returns routes of all methods of NavHTTPService converted by conv.
+func NavHTTPRoutes(conv *NavHTTPConverter) []NavHTTPRoute {
+	routes := make([]NavHTTPRoute, 0)
+	{
+		method, pattern, handler := conv.RoutesHTTPRule(nil)
+		routes = append(routes, NavHTTPRoute{
+			Method:     method,
+			Pattern:    pattern,
+			Handler:    handler,
+			FullMethod: "/collision.Nav/Routes",
+		})
+	}
+	routes = append(routes, NavHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/collision.Nav/RoutesHandler",
+		Handler:    conv.RoutesHandler(nil),
+		FullMethod: "/collision.Nav/RoutesHandler",
+	})
+	routes = append(routes, NavHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/collision.Nav/OpenAPIHandler",
+		Handler:    conv.OpenAPIHandler(nil),
+		FullMethod: "/collision.Nav/OpenAPIHandler",
+	})
+	return routes
+}
+
+// RegisterNavHTTPRoutes registers all routes of NavHTTPService on router.
+func RegisterNavHTTPRoutes(router NavHTTPRouter, conv *NavHTTPConverter) {
+	for _, route := range NavHTTPRoutes(conv) {
+		router.Handle(route.Method, route.Pattern, route.Handler)
+	}
+}
+
+// NavHTTPRoutesHandler returns http.Handler writing HTTP method, pattern and full method name of all routes
+// of conv as JSON.
+func NavHTTPRoutesHandler(conv *NavHTTPConverter) http.Handler {
+	type route struct {
+		Method     string `json:"method"`
+		Pattern    string `json:"pattern"`
+		FullMethod string `json:"fullMethod"`
+	}
+	routes := NavHTTPRoutes(conv)
+	rs := make([]route, 0, len(routes))
+	for _, r := range routes {
+		rs = append(rs, route{Method: r.Method, Pattern: r.Pattern, FullMethod: r.FullMethod})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(rs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// NavHTTPOpenAPIHandler returns http.Handler serving OpenAPI document of collision/collision.proto.
+func NavHTTPOpenAPIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(file_collision_collision_proto_openAPI)
+	})
+}
+
+// NavHTTPClient is the client API for Nav service over HTTP.
+// Only unary methods are implemented.
+type NavHTTPClient struct {
+	baseURL       string
+	client        *http.Client
+	contentType   string
+	interceptors  []grpc.UnaryClientInterceptor
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+}
+
+// NavHTTPClientOption configures NavHTTPClient.
+type NavHTTPClientOption func(*NavHTTPClient)
+
+// WithNavHTTPClient sets http.Client used to send requests. http.DefaultClient is used by default.
+func WithNavHTTPClient(client *http.Client) NavHTTPClientOption {
+	return func(c *NavHTTPClient) {
+		c.client = client
+	}
+}
+
+// WithNavHTTPClientContentType sets the media type of requests and responses.
+// "application/json" (default), "application/protobuf" and "application/x-protobuf" are supported.
+func WithNavHTTPClientContentType(contentType string) NavHTTPClientOption {
+	return func(c *NavHTTPClient) {
+		c.contentType = contentType
+	}
+}
+
+// WithNavHTTPClientInterceptors appends interceptors executed in left-to-right order for each call.
+// The method passed to the interceptors is the full method name, e.g., "/collision.Nav/Method", and cc is always nil.
+func WithNavHTTPClientInterceptors(interceptors ...grpc.UnaryClientInterceptor) NavHTTPClientOption {
+	return func(c *NavHTTPClient) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithNavHTTPClientHeaders sets the outgoing metadata keys sent as the request headers of the same names without the prefix.
+// The response headers of the names are also read as the header metadata.
+// The default is Authorization, the same as the incoming headers of the converter.
+func WithNavHTTPClientHeaders(headers ...string) NavHTTPClientOption {
+	return func(c *NavHTTPClient) {
+		c.headers = headers
+	}
+}
+
+// WithNavHTTPClientHeaderPrefix sets the prefix of the request headers sending the other outgoing metadata,
+// and of the response headers read as the header metadata.
+// The default is "Grpc-Metadata-", the same as the incoming and outgoing header prefixes of the converter.
+func WithNavHTTPClientHeaderPrefix(prefix string) NavHTTPClientOption {
+	return func(c *NavHTTPClient) {
+		c.headerPrefix = prefix
+	}
+}
+
+// WithNavHTTPClientTrailerPrefix sets the prefix of the response headers read as the trailer metadata.
+// The default is "Grpc-Trailer-", the same as the outgoing trailer prefix of the converter.
+func WithNavHTTPClientTrailerPrefix(prefix string) NavHTTPClientOption {
+	return func(c *NavHTTPClient) {
+		c.trailerPrefix = prefix
+	}
+}
+
+// NewNavHTTPClient returns NavHTTPClient sending requests to baseURL, e.g., "http://localhost:8080".
+func NewNavHTTPClient(baseURL string, opts ...NavHTTPClientOption) *NavHTTPClient {
+	c := &NavHTTPClient{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		client:        http.DefaultClient,
+		contentType:   "application/json",
+		headers:       []string{"Authorization"},
+		headerPrefix:  "Grpc-Metadata-",
+		trailerPrefix: "Grpc-Trailer-",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Routes calls Routes with GET /v1/routes.
+func (c *NavHTTPClient) Routes(ctx context.Context, in *RoutesRequest) (*RoutesResponse, error) {
+	out := &RoutesResponse{}
+	if err := c.invoke(ctx, "/collision.Nav/Routes", in, out, c.invokeRoutes); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeRoutes is the grpc.UnaryInvoker sending the request of Routes.
+func (c *NavHTTPClient) invokeRoutes(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*RoutesRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*RoutesResponse)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	path := "/v1/routes"
+	query := url.Values{}
+	if v := in.GetFrom(); v != "" {
+		query.Set("from", v)
+	}
+	if v := in.GetTo(); v != "" {
+		query.Set("to", v)
+	}
+	return c.do(ctx, http.MethodGet, path, query, nil, "", out, opts...)
+}
+
+// RoutesHandler calls RoutesHandler with POST /collision.Nav/RoutesHandler.
+func (c *NavHTTPClient) RoutesHandler(ctx context.Context, in *RoutesRequest) (*RoutesResponse, error) {
+	out := &RoutesResponse{}
+	if err := c.invoke(ctx, "/collision.Nav/RoutesHandler", in, out, c.invokeRoutesHandler); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeRoutesHandler is the grpc.UnaryInvoker sending the request of RoutesHandler.
+func (c *NavHTTPClient) invokeRoutesHandler(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*RoutesRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*RoutesResponse)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/collision.Nav/RoutesHandler", nil, in, "", out, opts...)
+}
+
+// OpenAPIHandler calls OpenAPIHandler with POST /collision.Nav/OpenAPIHandler.
+func (c *NavHTTPClient) OpenAPIHandler(ctx context.Context, in *RoutesRequest) (*RoutesResponse, error) {
+	out := &RoutesResponse{}
+	if err := c.invoke(ctx, "/collision.Nav/OpenAPIHandler", in, out, c.invokeOpenAPIHandler); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeOpenAPIHandler is the grpc.UnaryInvoker sending the request of OpenAPIHandler.
+func (c *NavHTTPClient) invokeOpenAPIHandler(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*RoutesRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*RoutesResponse)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/collision.Nav/OpenAPIHandler", nil, in, "", out, opts...)
+}
+
+var _ NavHTTPService = (*NavHTTPClient)(nil)
+
+// NavHTTPClientConn implements grpc.ClientConnInterface by NavHTTPClient, so the client can be used as
+// the connection of gRPC clients, e.g., NewNavClient(NewNavHTTPClientConn(NewNavHTTPClient(baseURL))).
+type NavHTTPClientConn struct {
+	c *NavHTTPClient
+}
+
+// NewNavHTTPClientConn returns NavHTTPClientConn sending the RPCs by c.
+func NewNavHTTPClientConn(c *NavHTTPClient) *NavHTTPClientConn {
+	return &NavHTTPClientConn{c: c}
+}
+
+// Invoke sends the unary RPC of method over HTTP. The HTTP method and path are decided by
+// google.api.http option of the method in the same way as the methods of NavHTTPClient.
+// The response metadata is set to grpc.Header and grpc.Trailer of opts, and the other options are ignored.
+func (cc *NavHTTPClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	switch method {
+	case "/collision.Nav/Routes":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeRoutes, opts...)
+	case "/collision.Nav/RoutesHandler":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeRoutesHandler, opts...)
+	case "/collision.Nav/OpenAPIHandler":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeOpenAPIHandler, opts...)
+	}
+	return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by NavHTTPClient.
+func (cc *NavHTTPClientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streaming RPC %s is not supported over HTTP", method)
+}
+
+var _ grpc.ClientConnInterface = (*NavHTTPClientConn)(nil)
+
+// Invoke is the same as Invoke of NavHTTPClientConn. Invoke and NewStream implement grpc.ClientConnInterface,
+// so the client can be used as the connection of gRPC clients, e.g., NewNavClient(NewNavHTTPClient(baseURL)).
+func (c *NavHTTPClient) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return NewNavHTTPClientConn(c).Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by NavHTTPClient.
+func (c *NavHTTPClient) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return NewNavHTTPClientConn(c).NewStream(ctx, desc, method, opts...)
+}
+
+var _ grpc.ClientConnInterface = (*NavHTTPClient)(nil)
+
+// invoke calls invoker through the interceptors.
+func (c *NavHTTPClient) invoke(ctx context.Context, method string, req, reply interface{}, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	chainer := func(currentInter grpc.UnaryClientInterceptor, currentInvoker grpc.UnaryInvoker) grpc.UnaryInvoker {
+		return func(currentCtx context.Context, currentMethod string, currentReq, currentReply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return currentInter(currentCtx, currentMethod, currentReq, currentReply, cc, currentInvoker, opts...)
+		}
+	}
+	chainedInvoker := invoker
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		chainedInvoker = chainer(c.interceptors[i], chainedInvoker)
+	}
+	return chainedInvoker(ctx, method, req, reply, nil, opts...)
+}
+
+// do sends in, or the field of in if field is not empty, as the request body unless in is nil,
+// and unmarshals the response into out. The response metadata is set to grpc.Header and grpc.Trailer of opts.
+func (c *NavHTTPClient) do(ctx context.Context, method, path string, query url.Values, in proto.Message, field protoreflect.Name, out proto.Message, opts ...grpc.CallOption) error {
+	u := c.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		buf, err := c.marshal(in, field)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	// Content-Type is also set to GET requests because the converter encodes errors by it.
+	req.Header.Set("Content-Type", c.contentType)
+	req.Header.Set("Accept", c.contentType)
+	// Outgoing metadata set by interceptors is sent as headers which the converter reads as incoming metadata.
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for k, vs := range md {
+		key := c.headerPrefix + k
+		for _, h := range c.headers {
+			if strings.EqualFold(h, k) {
+				key = k
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			req.Header.Add(key, v)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	c.setMetadata(resp.Header, opts)
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return navHTTPClientError(resp.StatusCode, contentType, buf)
+	}
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		return proto.Unmarshal(buf, out)
+	default:
+		return protojson.Unmarshal(buf, out)
+	}
+}
+
+// setMetadata sets the metadata read from the response headers to grpc.Header and grpc.Trailer of opts.
+// Values of the keys ending with "-bin" are decoded from base64 as gRPC does. The other options are ignored.
+func (c *NavHTTPClient) setMetadata(header http.Header, opts []grpc.CallOption) {
+	headerMD, trailerMD := metadata.MD{}, metadata.MD{}
+	add := func(md metadata.MD, key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+	hasPrefix := func(key, prefix string) bool {
+		return prefix != "" && len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix)
+	}
+
+	for _, key := range c.headers {
+		add(headerMD, key, header.Values(key))
+	}
+	for key, values := range header {
+		switch {
+		case hasPrefix(key, c.trailerPrefix):
+			add(trailerMD, key[len(c.trailerPrefix):], values)
+		case hasPrefix(key, c.headerPrefix):
+			add(headerMD, key[len(c.headerPrefix):], values)
+		}
+	}
+
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			*o.HeaderAddr = headerMD
+		case grpc.TrailerCallOption:
+			*o.TrailerAddr = trailerMD
+		}
+	}
+}
+
+// marshal marshals in, or the field of in if field is not empty, in the content type of the client.
+// Only message fields can be marshaled in protobuf.
+func (c *NavHTTPClient) marshal(in proto.Message, field protoreflect.Name) ([]byte, error) {
+	protobuf := c.contentType == "application/protobuf" || c.contentType == "application/x-protobuf"
+	if field == "" {
+		if protobuf {
+			return proto.Marshal(in)
+		}
+		return protojson.Marshal(in)
+	}
+
+	m := in.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName(field)
+	if protobuf {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, status.Errorf(codes.Unimplemented, "field %s cannot be sent in %s", field, c.contentType)
+		}
+		return proto.Marshal(m.Get(fd).Message().Interface())
+	}
+	// The JSON of the field is taken from the message which has only the field.
+	only := m.New()
+	if m.Has(fd) {
+		only.Set(fd, m.Get(fd))
+	}
+	buf, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(only.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return nil, err
+	}
+	return fields[fd.JSONName()], nil
+}
+
+// navHTTPClientError converts the error response to the error of grpc/status.
+// If the body is not google.rpc.Status, the code is decided by the HTTP status code as gRPC clients do.
+func navHTTPClientError(code int, contentType string, body []byte) error {
+	p := status.New(codes.Unknown, "").Proto()
+	var err error
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		err = proto.Unmarshal(body, p)
+	case "application/json":
+		err = protojson.Unmarshal(body, p)
+	default:
+		err = fmt.Errorf("unexpected Content-Type: %s", contentType)
+	}
+	if err == nil && codes.Code(p.GetCode()) != codes.OK {
+		return status.ErrorProto(p)
+	}
+
+	c := codes.Unknown
+	switch code {
+	case http.StatusBadRequest:
+		c = codes.Internal
+	case http.StatusUnauthorized:
+		c = codes.Unauthenticated
+	case http.StatusForbidden:
+		c = codes.PermissionDenied
+	case http.StatusNotFound:
+		c = codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		c = codes.Unavailable
+	}
+	return status.Errorf(c, "unexpected HTTP status code %d: %s", code, body)
+}
+
+// file_collision_collision_proto_incomingMetadata returns the metadata built from the headers listed in headers and the headers with prefix.
+// The empty prefix disables it. Values of the keys ending with "-bin" are decoded from base64 as gRPC does.
+func file_collision_collision_proto_incomingMetadata(header http.Header, headers []string, prefix string) metadata.MD {
+	md := metadata.MD{}
+	add := func(key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+
+	for _, key := range headers {
+		add(key, header.Values(key))
+	}
+	if prefix != "" {
+		for key, values := range header {
+			if len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+				add(key[len(prefix):], values)
+			}
+		}
+	}
+	return md
+}
+
+// file_collision_collision_proto_httpTransportStream implements grpc.ServerTransportStream to write header and trailer metadata set by the RPC
+// as HTTP headers, so that grpc.SetHeader, grpc.SendHeader and grpc.SetTrailer work in the RPC.
+type file_collision_collision_proto_httpTransportStream struct {
+	method        string
+	w             http.ResponseWriter
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+
+	mu         sync.Mutex
+	header     metadata.MD
+	trailer    metadata.MD
+	headerSent bool
+}
+
+func (s *file_collision_collision_proto_httpTransportStream) Method() string {
+	return s.method
+}
+
+// SetHeader sets the header metadata written before the response body.
+func (s *file_collision_collision_proto_httpTransportStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader sets the header metadata and writes it to the response headers.
+// The headers are sent to the client with the response body.
+func (s *file_collision_collision_proto_httpTransportStream) SendHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	s.writeHeaderLocked()
+	return nil
+}
+
+// SetTrailer sets the trailer metadata written after the RPC returns.
+func (s *file_collision_collision_proto_httpTransportStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// writeHeader writes the header metadata to the response headers unless it has been written.
+func (s *file_collision_collision_proto_httpTransportStream) writeHeader() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeHeaderLocked()
+}
+
+func (s *file_collision_collision_proto_httpTransportStream) writeHeaderLocked() {
+	if s.headerSent {
+		return
+	}
+	s.headerSent = true
+	s.add(s.headerPrefix, s.header, s.headers)
+}
+
+// writeTrailer writes the trailer metadata as HTTP trailers if asTrailer is true.
+// Otherwise, it is written to the response headers because the response body has not been written yet.
+func (s *file_collision_collision_proto_httpTransportStream) writeTrailer(asTrailer bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if asTrailer {
+		s.add(http.TrailerPrefix+s.trailerPrefix, s.trailer, nil)
+		return
+	}
+	s.add(s.trailerPrefix, s.trailer, nil)
+}
+
+// writeStatusCode writes the status code set by the header metadata "x-http-code" and reports whether
+// the response body is allowed. The status code is ignored unless it is between 200 and 599.
+func (s *file_collision_collision_proto_httpTransportStream) writeStatusCode() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vs := s.header.Get("x-http-code")
+	if len(vs) == 0 {
+		return true
+	}
+	code, err := strconv.Atoi(vs[len(vs)-1])
+	if err != nil || code < 200 || code > 599 {
+		return true
+	}
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		s.w.Header().Del("Content-Type")
+		s.w.Header().Del("Content-Encoding")
+		s.w.WriteHeader(code)
+		return false
+	}
+	s.w.WriteHeader(code)
+	return true
+}
+
+// add adds md to the response headers with the prefix. The keys listed in headers are added without the prefix.
+// Values of the keys ending with "-bin" are encoded in base64 as gRPC does.
+func (s *file_collision_collision_proto_httpTransportStream) add(prefix string, md metadata.MD, headers []string) {
+	for k, vs := range md {
+		if k == "x-http-code" {
+			continue
+		}
+		name := prefix + k
+		for _, header := range headers {
+			if strings.EqualFold(header, k) {
+				name = header
+				break
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			s.w.Header().Add(name, v)
+		}
+	}
+}
+
+// file_collision_collision_proto_timeout returns the timeout of the RPC set by Grpc-Timeout header or the header named name,
+// whose value is parsed by time.ParseDuration. The shorter one is used if both are set. Invalid values are ignored.
+func file_collision_collision_proto_timeout(header http.Header, name string) (time.Duration, bool) {
+	timeout, ok := file_collision_collision_proto_parseGRPCTimeout(header.Get("Grpc-Timeout"))
+	if name == "" {
+		return timeout, ok
+	}
+	v := header.Get(name)
+	if v == "" {
+		return timeout, ok
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return timeout, ok
+	}
+	if !ok || d < timeout {
+		return d, true
+	}
+	return timeout, true
+}
+
+// file_collision_collision_proto_parseGRPCTimeout parses v in the format of Grpc-Timeout header, that is at most 8 digits followed by the unit,
+// H (hours), M (minutes), S (seconds), m (milliseconds), u (microseconds) or n (nanoseconds).
+func file_collision_collision_proto_parseGRPCTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 || len(v) > 9 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch v[len(v)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	n, err := strconv.ParseUint(v[:len(v)-1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	if time.Duration(n) > math.MaxInt64/unit {
+		return math.MaxInt64, true
+	}
+	return time.Duration(n) * unit, true
+}
+
+// file_collision_collision_proto_requestPeer returns the peer of the RPC built from the remote address and the TLS connection state of r.
+// It returns nil if the remote address is not an IP address and port.
+func file_collision_collision_proto_requestPeer(r *http.Request) *peer.Peer {
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return nil
+	}
+	p := &peer.Peer{
+		Addr: &net.TCPAddr{IP: ip, Port: n},
+	}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{
+			State: *r.TLS,
+			CommonAuthInfo: credentials.CommonAuthInfo{
+				SecurityLevel: credentials.PrivacyAndIntegrity,
+			},
+		}
+	}
+	return p
+}
+
+// file_collision_collision_proto_httpRequestBody counts the bytes read from the request body.
+type file_collision_collision_proto_httpRequestBody struct {
+	io.ReadCloser
+	size int64
+}
+
+func (b *file_collision_collision_proto_httpRequestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+// file_collision_collision_proto_httpResponseWriter records the status code and the size of the response written to http.ResponseWriter.
+type file_collision_collision_proto_httpResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int64
+}
+
+func (w *file_collision_collision_proto_httpResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *file_collision_collision_proto_httpResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher to flush streaming responses.
+func (w *file_collision_collision_proto_httpResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter for http.ResponseController.
+func (w *file_collision_collision_proto_httpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// status returns the status code of the response. It is 200 if nothing has been written as net/http does.
+func (w *file_collision_collision_proto_httpResponseWriter) status() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+	return w.statusCode
+}
+
+// file_collision_collision_proto_redact returns m marshaled in JSON without the fields marked with debug_redact option.
+func file_collision_collision_proto_redact(m proto.Message) string {
+	buf, err := protojson.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(buf)
+}
+
+// file_collision_collision_proto_decodeBody replaces the body of r with the reader decoding encoding, which is gzip, x-gzip or deflate.
+// A malformed body fails with codes.InvalidArgument.
+func file_collision_collision_proto_decodeBody(r *http.Request, encoding string) error {
+	var zr io.ReadCloser
+	var err error
+	if encoding == "deflate" {
+		zr, err = zlib.NewReader(r.Body)
+	} else {
+		zr, err = gzip.NewReader(r.Body)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", encoding, err)
+	}
+	r.Body = &file_collision_collision_proto_decodedBody{ReadCloser: zr, encoding: encoding}
+	return nil
+}
+
+// file_collision_collision_proto_decodedBody is the request body decoded by Content-Encoding header.
+// Reading it fails with codes.InvalidArgument if the rest of the body turns out to be malformed.
+type file_collision_collision_proto_decodedBody struct {
+	io.ReadCloser
+	encoding string
+}
+
+func (b *file_collision_collision_proto_decodedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", b.encoding, err)
+	}
+	return n, err
+}
+
+// file_collision_collision_proto_compress returns buf compressed by the content coding negotiated by Accept-Encoding header of r
+// if buf is at least minSize bytes, or identity is not accepted. A negative minSize disables the compression.
+// Otherwise, it returns buf as is.
+func file_collision_collision_proto_compress(w http.ResponseWriter, r *http.Request, buf []byte, minSize int) []byte {
+	if minSize < 0 {
+		return buf
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	encoding, identity := file_collision_collision_proto_acceptEncoding(r)
+	if identity && len(buf) < minSize {
+		return buf
+	}
+	var b bytes.Buffer
+	var zw io.WriteCloser
+	switch encoding {
+	case "gzip":
+		zw = gzip.NewWriter(&b)
+	case "deflate":
+		zw = zlib.NewWriter(&b)
+	default:
+		return buf
+	}
+	if _, err := zw.Write(buf); err != nil {
+		return buf
+	}
+	if err := zw.Close(); err != nil {
+		return buf
+	}
+	w.Header().Set("Content-Encoding", encoding)
+	return b.Bytes()
+}
+
+// file_collision_collision_proto_acceptEncoding returns gzip or deflate accepted by Accept-Encoding header of r. gzip is preferred to deflate.
+// It returns the empty string if neither is accepted. Codings with q=0 are not accepted, and * applies to the codings
+// not listed in the header. identity reports whether the response may be sent without any coding.
+func file_collision_collision_proto_acceptEncoding(r *http.Request) (encoding string, identity bool) {
+	qs := make(map[string]float64)
+	for _, v := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(v, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		if coding == "x-gzip" {
+			coding = "gzip"
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		qs[coding] = q
+	}
+	accepted := func(coding string) bool {
+		if q, ok := qs[coding]; ok {
+			return q > 0
+		}
+		if q, ok := qs["*"]; ok {
+			return q > 0
+		}
+		// identity is acceptable unless it is excluded explicitly.
+		return coding == "identity"
+	}
+
+	switch {
+	case accepted("gzip"):
+		encoding = "gzip"
+	case accepted("deflate"):
+		encoding = "deflate"
+	}
+	return encoding, accepted("identity")
+}
+
+//go:embed collision.openapi.json
+var file_collision_collision_proto_openAPI []byte
@@ -0,0 +1,1452 @@
+// Code generated by protoc-gen-gohttp. DO NOT EDIT.
+// source: hellostreamingworld/hellostreamingworld.proto
+
+package hellostreamingworldpb
+
+import (
+	bufio "bufio"
+	bytes "bytes"
+	gzip "compress/gzip"
+	zlib "compress/zlib"
+	context "context"
+	base64 "encoding/base64"
+	binary "encoding/binary"
+	json "encoding/json"
+	errors "errors"
+	fmt "fmt"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	credentials "google.golang.org/grpc/credentials"
+	metadata "google.golang.org/grpc/metadata"
+	peer "google.golang.org/grpc/peer"
+	status "google.golang.org/grpc/status"
+	protojson "google.golang.org/protobuf/encoding/protojson"
+	protowire "google.golang.org/protobuf/encoding/protowire"
+	proto "google.golang.org/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	io "io"
+	ioutil "io/ioutil"
+	slog "log/slog"
+	math "math"
+	mime "mime"
+	net "net"
+	http "net/http"
+	url "net/url"
+	debug "runtime/debug"
+	strconv "strconv"
+	strings "strings"
+	sync "sync"
+	time "time"
+)
+
+// MultiGreeterHTTPService is the server API for MultiGreeter service.
+type MultiGreeterHTTPService interface {
+	SayHello(*HelloRequest, MultiGreeter_SayHelloServer) error
+}
+
+// MultiGreeterHTTPTracer is called at the start and the finish of every request handled by MultiGreeterHTTPConverter,
+// e.g. to start and end a span of a tracing library without depending on it.
+type MultiGreeterHTTPTracer interface {
+	// OnStart is called when the handler of method starts handling r. route is the path template of google.api.http option,
+	// or empty if the handler is not returned by the HTTPRule method. The returned context is passed to the RPC and OnFinish.
+	OnStart(ctx context.Context, method protoreflect.MethodDescriptor, route string, r *http.Request) context.Context
+	// OnFinish is called after the callback with the arguments passed to the callback, the status code of the response
+	// and the time elapsed since OnStart. The gRPC code of the RPC is status.Code(err).
+	OnFinish(ctx context.Context, method protoreflect.MethodDescriptor, arg, ret proto.Message, statusCode int, err error, elapsed time.Duration)
+}
+
+// MultiGreeterHTTPObserver observes the metrics of every request handled by MultiGreeterHTTPConverter,
+// e.g. to export the latency, the sizes and the status of RPCs to a metrics library without depending on it.
+type MultiGreeterHTTPObserver interface {
+	// Observe is called after the callback with the method, the path template of google.api.http option
+	// (empty if the handler is not returned by the HTTPRule method), the status code of the response,
+	// the gRPC code of the RPC, the bytes read from the request body and written to the response body, and the latency.
+	Observe(ctx context.Context, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, requestSize, responseSize int64, elapsed time.Duration)
+}
+
+// MultiGreeterHTTPConverter has a function to convert MultiGreeterHTTPService interface to http.HandlerFunc.
+type MultiGreeterHTTPConverter struct {
+	srv                   MultiGreeterHTTPService
+	cb                    func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)
+	interceptors          []grpc.UnaryServerInterceptor
+	streamInterceptors    []grpc.StreamServerInterceptor
+	maxBodySize           int64
+	skipMethodCheck       bool
+	incomingHeaders       []string
+	incomingHeaderPrefix  string
+	outgoingHeaders       []string
+	outgoingHeaderPrefix  string
+	outgoingTrailerPrefix string
+	timeoutHeader         string
+	tracer                MultiGreeterHTTPTracer
+	observer              MultiGreeterHTTPObserver
+	accessLogger          *slog.Logger
+	accessLogPayloads     bool
+	recovery              bool
+	panicHandler          func(ctx context.Context, fullMethod string, p interface{}, stack []byte)
+	compressionMinSize    int
+}
+
+// MultiGreeterHTTPConverterOption configures MultiGreeterHTTPConverter.
+type MultiGreeterHTTPConverterOption func(*MultiGreeterHTTPConverter)
+
+// WithMultiGreeterHTTPCallback sets the callback used when nil is passed to a convert method.
+func WithMultiGreeterHTTPCallback(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.cb = cb
+	}
+}
+
+// WithMultiGreeterHTTPInterceptors appends interceptors executed before the interceptors passed to a convert method.
+func WithMultiGreeterHTTPInterceptors(interceptors ...grpc.UnaryServerInterceptor) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.interceptors = append(h.interceptors, interceptors...)
+	}
+}
+
+// WithMultiGreeterHTTPStreamInterceptors appends stream interceptors executed before the interceptors passed to a convert method of streaming RPC.
+func WithMultiGreeterHTTPStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.streamInterceptors = append(h.streamInterceptors, interceptors...)
+	}
+}
+
+// WithMultiGreeterHTTPMaxBodySize sets the maximum size of request bodies in bytes.
+// The default is 4194304 bytes set by max_body_size option of protoc-gen-gohttp. Zero or a negative value means no limit.
+// Requests with larger bodies fail with *http.MaxBytesError, and the default callback responds 413 Request Entity Too Large.
+func WithMultiGreeterHTTPMaxBodySize(n int64) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.maxBodySize = n
+	}
+}
+
+// WithMultiGreeterHTTPSkipMethodCheck sets whether the handlers returned by HTTPRule methods skip checking the request method.
+// By default, the handlers respond 405 Method Not Allowed with Allow header if the request method does not match google.api.http option.
+// Routers that already route requests by the method can skip the check.
+func WithMultiGreeterHTTPSkipMethodCheck(skip bool) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.skipMethodCheck = skip
+	}
+}
+
+// WithMultiGreeterHTTPIncomingHeaders sets the request headers passed to the RPC as incoming metadata.
+// The default is Authorization. The metadata keys are the lowercase header names.
+func WithMultiGreeterHTTPIncomingHeaders(headers ...string) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.incomingHeaders = headers
+	}
+}
+
+// WithMultiGreeterHTTPIncomingHeaderPrefix sets the prefix of the request headers passed to the RPC as incoming metadata.
+// The metadata keys are the lowercase header names without the prefix. The default is "Grpc-Metadata-",
+// and the empty prefix disables it.
+func WithMultiGreeterHTTPIncomingHeaderPrefix(prefix string) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.incomingHeaderPrefix = prefix
+	}
+}
+
+// WithMultiGreeterHTTPOutgoingHeaders sets the response headers written from the header metadata with the same
+// lowercase names, e.g. "location" for Location. They are written without the prefix. The default is Location.
+func WithMultiGreeterHTTPOutgoingHeaders(headers ...string) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.outgoingHeaders = headers
+	}
+}
+
+// WithMultiGreeterHTTPOutgoingHeaderPrefix sets the prefix of the response headers written from the header metadata
+// set by grpc.SetHeader or grpc.SendHeader. The default is "Grpc-Metadata-".
+func WithMultiGreeterHTTPOutgoingHeaderPrefix(prefix string) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.outgoingHeaderPrefix = prefix
+	}
+}
+
+// WithMultiGreeterHTTPOutgoingTrailerPrefix sets the prefix of the response headers or trailers written from the trailer metadata
+// set by grpc.SetTrailer. The default is "Grpc-Trailer-".
+func WithMultiGreeterHTTPOutgoingTrailerPrefix(prefix string) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.outgoingTrailerPrefix = prefix
+	}
+}
+
+// WithMultiGreeterHTTPTimeoutHeader sets the request header of the timeout of the RPC in addition to Grpc-Timeout header.
+// The value is parsed by time.ParseDuration, e.g. "1.5s". The empty name disables it, which is the default.
+func WithMultiGreeterHTTPTimeoutHeader(name string) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.timeoutHeader = name
+	}
+}
+
+// WithMultiGreeterHTTPTracer sets the tracer called at the start and the finish of every request.
+func WithMultiGreeterHTTPTracer(tracer MultiGreeterHTTPTracer) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.tracer = tracer
+	}
+}
+
+// WithMultiGreeterHTTPObserver sets the observer called at the finish of every request.
+func WithMultiGreeterHTTPObserver(observer MultiGreeterHTTPObserver) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.observer = observer
+	}
+}
+
+// WithMultiGreeterHTTPAccessLog sets the logger emitting an access log record of every request with the full method,
+// the HTTP method, the route, the status code, the gRPC code and the latency. Requests responded with 5xx are logged
+// at error level, and the others are logged at info level. The access log is disabled by default.
+func WithMultiGreeterHTTPAccessLog(logger *slog.Logger) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.accessLogger = logger
+	}
+}
+
+// WithMultiGreeterHTTPAccessLogPayloads sets whether the access log contains the request and response messages in JSON.
+// Fields marked with debug_redact option are removed from the messages.
+func WithMultiGreeterHTTPAccessLogPayloads(payloads bool) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.accessLogPayloads = payloads
+	}
+}
+
+// WithMultiGreeterHTTPRecovery enables the recovery of panics in the RPC and the interceptors. The panic is converted to
+// *MultiGreeterHTTPPanicError of codes.Internal passed to the callback, and reported to handler with the stack trace
+// unless handler is nil. Panics are not recovered by default.
+func WithMultiGreeterHTTPRecovery(handler func(ctx context.Context, fullMethod string, p interface{}, stack []byte)) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.recovery = true
+		h.panicHandler = handler
+	}
+}
+
+// WithMultiGreeterHTTPCompression enables the compression of response bodies of unary and client-side streaming RPCs
+// with gzip or deflate negotiated by Accept-Encoding header. Only bodies of at least minSize bytes are compressed.
+// A negative value disables it, which is the default.
+func WithMultiGreeterHTTPCompression(minSize int) MultiGreeterHTTPConverterOption {
+	return func(h *MultiGreeterHTTPConverter) {
+		h.compressionMinSize = minSize
+	}
+}
+
+// NewMultiGreeterHTTPConverter returns MultiGreeterHTTPConverter.
+func NewMultiGreeterHTTPConverter(srv MultiGreeterHTTPService, opts ...MultiGreeterHTTPConverterOption) *MultiGreeterHTTPConverter {
+	h := &MultiGreeterHTTPConverter{
+		srv:                   srv,
+		maxBodySize:           4194304,
+		incomingHeaders:       []string{"Authorization"},
+		incomingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingHeaders:       []string{"Location"},
+		outgoingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingTrailerPrefix: "Grpc-Trailer-",
+		compressionMinSize:    -1,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// trace calls OnStart of the tracer, and returns the context returned by OnStart, w recording the response
+// and cb calling OnFinish of the tracer, Observe of the observer and the access logger after cb.
+// The body of r is replaced to count the bytes read.
+func (h *MultiGreeterHTTPConverter) trace(ctx context.Context, w http.ResponseWriter, r *http.Request, name protoreflect.Name, route string, cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) (context.Context, http.ResponseWriter, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) {
+	method := File_hellostreamingworld_hellostreamingworld_proto.Services().ByName("MultiGreeter").Methods().ByName(name)
+	start := time.Now()
+	if h.tracer != nil {
+		ctx = h.tracer.OnStart(ctx, method, route, r)
+	}
+	rw := &file_hellostreamingworld_hellostreamingworld_proto_httpResponseWriter{ResponseWriter: w}
+	body := &file_hellostreamingworld_hellostreamingworld_proto_httpRequestBody{ReadCloser: r.Body}
+	r.Body = body
+	return ctx, rw, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+		cb(ctx, w, r, arg, ret, err)
+		elapsed := time.Since(start)
+		if h.tracer != nil {
+			h.tracer.OnFinish(ctx, method, arg, ret, rw.status(), err, elapsed)
+		}
+		code := status.Code(err)
+		switch {
+		case code != codes.Unknown:
+		case errors.Is(err, context.DeadlineExceeded):
+			code = codes.DeadlineExceeded
+		case errors.Is(err, context.Canceled):
+			code = codes.Canceled
+		}
+		if h.observer != nil {
+			h.observer.Observe(ctx, method, route, rw.status(), code, body.size, rw.size, elapsed)
+		}
+		if h.accessLogger != nil {
+			h.logAccess(ctx, r, method, route, rw.status(), code, err, elapsed, arg, ret)
+		}
+	}
+}
+
+// logAccess emits the access log record of the request.
+func (h *MultiGreeterHTTPConverter) logAccess(ctx context.Context, r *http.Request, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, err error, elapsed time.Duration, arg, ret proto.Message) {
+	level := slog.LevelInfo
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	attrs := []slog.Attr{
+		slog.String("method", fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())),
+		slog.String("http_method", r.Method),
+		slog.String("route", route),
+		slog.Int("status", statusCode),
+		slog.String("code", code.String()),
+		slog.Duration("latency", elapsed),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if h.accessLogPayloads {
+		if arg != nil {
+			attrs = append(attrs, slog.String("request", file_hellostreamingworld_hellostreamingworld_proto_redact(arg)))
+		}
+		if ret != nil {
+			attrs = append(attrs, slog.String("response", file_hellostreamingworld_hellostreamingworld_proto_redact(ret)))
+		}
+	}
+	h.accessLogger.LogAttrs(ctx, level, "access", attrs...)
+}
+
+// MultiGreeterHTTPPanicError is the error of a panic recovered by MultiGreeterHTTPConverter.
+type MultiGreeterHTTPPanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine panicked.
+	Stack []byte
+}
+
+func (e *MultiGreeterHTTPPanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// GRPCStatus returns the status of codes.Internal, so that status.Code returns codes.Internal for the error.
+// The message of the status does not contain the panic value, which must not be sent to clients.
+func (e *MultiGreeterHTTPPanicError) GRPCStatus() *status.Status {
+	return status.New(codes.Internal, "internal error")
+}
+
+// recovered reports the panic p to the panic handler and returns it as *MultiGreeterHTTPPanicError.
+func (h *MultiGreeterHTTPConverter) recovered(ctx context.Context, fullMethod string, p interface{}) error {
+	err := &MultiGreeterHTTPPanicError{Value: p, Stack: debug.Stack()}
+	if h.panicHandler != nil {
+		h.panicHandler(ctx, fullMethod, p, err.Stack)
+	}
+	return err
+}
+
+// recoverUnary is the outermost interceptor recovering panics in the RPC and the interceptors.
+func (h *MultiGreeterHTTPConverter) recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = h.recovered(ctx, info.FullMethod, p)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// recoverStream is the outermost stream interceptor recovering panics in the RPC and the interceptors.
+func (h *MultiGreeterHTTPConverter) recoverStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = h.recovered(ss.Context(), info.FullMethod, p)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+type multiGreeterSayHelloHTTPServer struct {
+	grpc.ServerStream
+}
+
+func (x *multiGreeterSayHelloHTTPServer) Send(m *HelloReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// SayHello returns MultiGreeterHTTPService interface's SayHello converted to http.HandlerFunc.
+func (h *MultiGreeterHTTPConverter) SayHello(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.StreamServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
+	if cb == nil {
+		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			if err != nil {
+				// The stream has already ended with the status.
+				if _, ok := w.Header()[http.TrailerPrefix+"Grpc-Status"]; ok {
+					return
+				}
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
+				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
+				case "application/protobuf", "application/x-protobuf":
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				case "application/json":
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				default:
+				}
+			}
+		}
+	}
+	interceptors = append(append([]grpc.StreamServerInterceptor{}, h.streamInterceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.StreamServerInterceptor{h.recoverStream}, interceptors...)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if md := file_hellostreamingworld_hellostreamingworld_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_hellostreamingworld_hellostreamingworld_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_hellostreamingworld_hellostreamingworld_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "sayHello", "", cb)
+		}
+		ts := &file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream{
+			method:        "/hellostreamingworld.MultiGreeter/sayHello",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		accepts := strings.Split(r.Header.Get("Accept"), ",")
+		accept := accepts[0]
+		if accept == "*/*" || accept == "" {
+			if contentType != "" {
+				accept = contentType
+			} else {
+				accept = "application/json"
+			}
+		}
+
+		switch accept {
+		case "application/json", "application/x-ndjson":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		case "text/event-stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+		default:
+			w.Header().Set("Content-Type", accept)
+		}
+
+		arg := &HelloRequest{}
+		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_hellostreamingworld_hellostreamingworld_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+
+			switch contentType {
+			case "application/protobuf", "application/x-protobuf":
+				if err := proto.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			case "application/json":
+				if err := protojson.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Type: %s", contentType)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+		}
+
+		switch accept {
+		case "application/protobuf", "application/x-protobuf", "application/json", "application/x-ndjson", "text/event-stream":
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err := fmt.Fprintf(w, "Unsupported Accept: %s", accept)
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream := &file_hellostreamingworld_hellostreamingworld_proto_httpServerStream{
+			ctx:    ctx,
+			cancel: cancel,
+			w:      w,
+			accept: accept,
+			ts:     ts,
+		}
+		n := len(interceptors)
+		chained := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			chainer := func(currentInter grpc.StreamServerInterceptor, currentHandler grpc.StreamHandler) grpc.StreamHandler {
+				return func(currentSrv interface{}, currentStream grpc.ServerStream) error {
+					return currentInter(currentSrv, currentStream, info, currentHandler)
+				}
+			}
+
+			chainedHandler := handler
+			for i := n - 1; i >= 0; i-- {
+				chainedHandler = chainer(interceptors[i], chainedHandler)
+			}
+			return chainedHandler(srv, ss)
+		}
+
+		info := &grpc.StreamServerInfo{
+			FullMethod:     "/hellostreamingworld.MultiGreeter/sayHello",
+			IsClientStream: false,
+			IsServerStream: true,
+		}
+
+		handler := func(srv interface{}, ss grpc.ServerStream) error {
+			return h.srv.SayHello(arg, &multiGreeterSayHelloHTTPServer{ss})
+		}
+
+		err := chained(h.srv, stream, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(stream.wrote)
+		stream.finish(err)
+		if err != nil {
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+		cb(ctx, w, r, arg, nil, nil)
+	})
+}
+
+// SayHelloWithName returns Service name, Method name and MultiGreeterHTTPService interface's SayHello converted to http.HandlerFunc.
+func (h *MultiGreeterHTTPConverter) SayHelloWithName(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.StreamServerInterceptor) (string, string, http.HandlerFunc) {
+	return "MultiGreeter", "SayHello", h.SayHello(cb, interceptors...)
+}
+
+// RegisterMultiGreeterHTTPHandlers registers all methods of MultiGreeterHTTPService on mux.
+// Methods with google.api.http option are registered with its HTTP method and path,
+// other methods are registered with POST /{package}.{Service}/{Method}.
+func RegisterMultiGreeterHTTPHandlers(mux *http.ServeMux, conv *MultiGreeterHTTPConverter) {
+	mux.Handle("POST /hellostreamingworld.MultiGreeter/sayHello", conv.SayHello(nil))
+}
+
+// MultiGreeterHTTPRoute is a route of MultiGreeterHTTPService method.
+type MultiGreeterHTTPRoute struct {
+	// Method is HTTP method of the route.
+	Method string
+	// Pattern is path template of google.api.http option, or /{package}.{Service}/{Method} if the option is not defined.
+	Pattern string
+	// Handler is MultiGreeterHTTPService method converted to http.HandlerFunc.
+	Handler http.HandlerFunc
+	// FullMethod is the full RPC method string, i.e., /package.service/method.
+	FullMethod string
+}
+
+// MultiGreeterHTTPRouter is the interface of HTTP routers that MultiGreeterHTTPRoute is registered on.
+type MultiGreeterHTTPRouter interface {
+	Handle(method, pattern string, handler http.Handler)
+}
+
+// MultiGreeterHTTPRouterFunc is an adapter to use a function as MultiGreeterHTTPRouter.
+type MultiGreeterHTTPRouterFunc func(method, pattern string, handler http.Handler)
+
+// Handle calls f(method, pattern, handler).
+func (f MultiGreeterHTTPRouterFunc) Handle(method, pattern string, handler http.Handler) {
+	f(method, pattern, handler)
+}
+
+// MultiGreeterHTTPRoutes returns routes of all methods of MultiGreeterHTTPService converted by conv.
+func MultiGreeterHTTPRoutes(conv *MultiGreeterHTTPConverter) []MultiGreeterHTTPRoute {
+	routes := make([]MultiGreeterHTTPRoute, 0)
+	routes = append(routes, MultiGreeterHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/hellostreamingworld.MultiGreeter/sayHello",
+		Handler:    conv.SayHello(nil),
+		FullMethod: "/hellostreamingworld.MultiGreeter/sayHello",
+	})
+	return routes
+}
+
+// RegisterMultiGreeterHTTPRoutes registers all routes of MultiGreeterHTTPService on router.
+func RegisterMultiGreeterHTTPRoutes(router MultiGreeterHTTPRouter, conv *MultiGreeterHTTPConverter) {
+	for _, route := range MultiGreeterHTTPRoutes(conv) {
+		router.Handle(route.Method, route.Pattern, route.Handler)
+	}
+}
+
+// MultiGreeterHTTPRoutesHandler returns http.Handler writing HTTP method, pattern and full method name of all routes
+// of conv as JSON.
+func MultiGreeterHTTPRoutesHandler(conv *MultiGreeterHTTPConverter) http.Handler {
+	type route struct {
+		Method     string `json:"method"`
+		Pattern    string `json:"pattern"`
+		FullMethod string `json:"fullMethod"`
+	}
+	routes := MultiGreeterHTTPRoutes(conv)
+	rs := make([]route, 0, len(routes))
+	for _, r := range routes {
+		rs = append(rs, route{Method: r.Method, Pattern: r.Pattern, FullMethod: r.FullMethod})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(rs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// MultiGreeterHTTPClient is the client API for MultiGreeter service over HTTP.
+// Only unary methods are implemented.
+type MultiGreeterHTTPClient struct {
+	baseURL       string
+	client        *http.Client
+	contentType   string
+	interceptors  []grpc.UnaryClientInterceptor
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+}
+
+// MultiGreeterHTTPClientOption configures MultiGreeterHTTPClient.
+type MultiGreeterHTTPClientOption func(*MultiGreeterHTTPClient)
+
+// WithMultiGreeterHTTPClient sets http.Client used to send requests. http.DefaultClient is used by default.
+func WithMultiGreeterHTTPClient(client *http.Client) MultiGreeterHTTPClientOption {
+	return func(c *MultiGreeterHTTPClient) {
+		c.client = client
+	}
+}
+
+// WithMultiGreeterHTTPClientContentType sets the media type of requests and responses.
+// "application/json" (default), "application/protobuf" and "application/x-protobuf" are supported.
+func WithMultiGreeterHTTPClientContentType(contentType string) MultiGreeterHTTPClientOption {
+	return func(c *MultiGreeterHTTPClient) {
+		c.contentType = contentType
+	}
+}
+
+// WithMultiGreeterHTTPClientInterceptors appends interceptors executed in left-to-right order for each call.
+// The method passed to the interceptors is the full method name, e.g., "/hellostreamingworld.MultiGreeter/Method", and cc is always nil.
+func WithMultiGreeterHTTPClientInterceptors(interceptors ...grpc.UnaryClientInterceptor) MultiGreeterHTTPClientOption {
+	return func(c *MultiGreeterHTTPClient) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithMultiGreeterHTTPClientHeaders sets the outgoing metadata keys sent as the request headers of the same names without the prefix.
+// The response headers of the names are also read as the header metadata.
+// The default is Authorization, the same as the incoming headers of the converter.
+func WithMultiGreeterHTTPClientHeaders(headers ...string) MultiGreeterHTTPClientOption {
+	return func(c *MultiGreeterHTTPClient) {
+		c.headers = headers
+	}
+}
+
+// WithMultiGreeterHTTPClientHeaderPrefix sets the prefix of the request headers sending the other outgoing metadata,
+// and of the response headers read as the header metadata.
+// The default is "Grpc-Metadata-", the same as the incoming and outgoing header prefixes of the converter.
+func WithMultiGreeterHTTPClientHeaderPrefix(prefix string) MultiGreeterHTTPClientOption {
+	return func(c *MultiGreeterHTTPClient) {
+		c.headerPrefix = prefix
+	}
+}
+
+// WithMultiGreeterHTTPClientTrailerPrefix sets the prefix of the response headers read as the trailer metadata.
+// The default is "Grpc-Trailer-", the same as the outgoing trailer prefix of the converter.
+func WithMultiGreeterHTTPClientTrailerPrefix(prefix string) MultiGreeterHTTPClientOption {
+	return func(c *MultiGreeterHTTPClient) {
+		c.trailerPrefix = prefix
+	}
+}
+
+// NewMultiGreeterHTTPClient returns MultiGreeterHTTPClient sending requests to baseURL, e.g., "http://localhost:8080".
+func NewMultiGreeterHTTPClient(baseURL string, opts ...MultiGreeterHTTPClientOption) *MultiGreeterHTTPClient {
+	c := &MultiGreeterHTTPClient{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		client:        http.DefaultClient,
+		contentType:   "application/json",
+		headers:       []string{"Authorization"},
+		headerPrefix:  "Grpc-Metadata-",
+		trailerPrefix: "Grpc-Trailer-",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// MultiGreeterHTTPClientConn implements grpc.ClientConnInterface by MultiGreeterHTTPClient, so the client can be used as
+// the connection of gRPC clients, e.g., NewMultiGreeterClient(NewMultiGreeterHTTPClientConn(NewMultiGreeterHTTPClient(baseURL))).
+type MultiGreeterHTTPClientConn struct {
+	c *MultiGreeterHTTPClient
+}
+
+// NewMultiGreeterHTTPClientConn returns MultiGreeterHTTPClientConn sending the RPCs by c.
+func NewMultiGreeterHTTPClientConn(c *MultiGreeterHTTPClient) *MultiGreeterHTTPClientConn {
+	return &MultiGreeterHTTPClientConn{c: c}
+}
+
+// Invoke sends the unary RPC of method over HTTP. The HTTP method and path are decided by
+// google.api.http option of the method in the same way as the methods of MultiGreeterHTTPClient.
+// The response metadata is set to grpc.Header and grpc.Trailer of opts, and the other options are ignored.
+func (cc *MultiGreeterHTTPClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	switch method {
+	}
+	return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by MultiGreeterHTTPClient.
+func (cc *MultiGreeterHTTPClientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streaming RPC %s is not supported over HTTP", method)
+}
+
+var _ grpc.ClientConnInterface = (*MultiGreeterHTTPClientConn)(nil)
+
+// Invoke is the same as Invoke of MultiGreeterHTTPClientConn. Invoke and NewStream implement grpc.ClientConnInterface,
+// so the client can be used as the connection of gRPC clients, e.g., NewMultiGreeterClient(NewMultiGreeterHTTPClient(baseURL)).
+func (c *MultiGreeterHTTPClient) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return NewMultiGreeterHTTPClientConn(c).Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by MultiGreeterHTTPClient.
+func (c *MultiGreeterHTTPClient) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return NewMultiGreeterHTTPClientConn(c).NewStream(ctx, desc, method, opts...)
+}
+
+var _ grpc.ClientConnInterface = (*MultiGreeterHTTPClient)(nil)
+
+// invoke calls invoker through the interceptors.
+func (c *MultiGreeterHTTPClient) invoke(ctx context.Context, method string, req, reply interface{}, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	chainer := func(currentInter grpc.UnaryClientInterceptor, currentInvoker grpc.UnaryInvoker) grpc.UnaryInvoker {
+		return func(currentCtx context.Context, currentMethod string, currentReq, currentReply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return currentInter(currentCtx, currentMethod, currentReq, currentReply, cc, currentInvoker, opts...)
+		}
+	}
+	chainedInvoker := invoker
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		chainedInvoker = chainer(c.interceptors[i], chainedInvoker)
+	}
+	return chainedInvoker(ctx, method, req, reply, nil, opts...)
+}
+
+// do sends in, or the field of in if field is not empty, as the request body unless in is nil,
+// and unmarshals the response into out. The response metadata is set to grpc.Header and grpc.Trailer of opts.
+func (c *MultiGreeterHTTPClient) do(ctx context.Context, method, path string, query url.Values, in proto.Message, field protoreflect.Name, out proto.Message, opts ...grpc.CallOption) error {
+	u := c.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		buf, err := c.marshal(in, field)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	// Content-Type is also set to GET requests because the converter encodes errors by it.
+	req.Header.Set("Content-Type", c.contentType)
+	req.Header.Set("Accept", c.contentType)
+	// Outgoing metadata set by interceptors is sent as headers which the converter reads as incoming metadata.
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for k, vs := range md {
+		key := c.headerPrefix + k
+		for _, h := range c.headers {
+			if strings.EqualFold(h, k) {
+				key = k
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			req.Header.Add(key, v)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	c.setMetadata(resp.Header, opts)
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return multiGreeterHTTPClientError(resp.StatusCode, contentType, buf)
+	}
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		return proto.Unmarshal(buf, out)
+	default:
+		return protojson.Unmarshal(buf, out)
+	}
+}
+
+// setMetadata sets the metadata read from the response headers to grpc.Header and grpc.Trailer of opts.
+// Values of the keys ending with "-bin" are decoded from base64 as gRPC does. The other options are ignored.
+func (c *MultiGreeterHTTPClient) setMetadata(header http.Header, opts []grpc.CallOption) {
+	headerMD, trailerMD := metadata.MD{}, metadata.MD{}
+	add := func(md metadata.MD, key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+	hasPrefix := func(key, prefix string) bool {
+		return prefix != "" && len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix)
+	}
+
+	for _, key := range c.headers {
+		add(headerMD, key, header.Values(key))
+	}
+	for key, values := range header {
+		switch {
+		case hasPrefix(key, c.trailerPrefix):
+			add(trailerMD, key[len(c.trailerPrefix):], values)
+		case hasPrefix(key, c.headerPrefix):
+			add(headerMD, key[len(c.headerPrefix):], values)
+		}
+	}
+
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			*o.HeaderAddr = headerMD
+		case grpc.TrailerCallOption:
+			*o.TrailerAddr = trailerMD
+		}
+	}
+}
+
+// marshal marshals in, or the field of in if field is not empty, in the content type of the client.
+// Only message fields can be marshaled in protobuf.
+func (c *MultiGreeterHTTPClient) marshal(in proto.Message, field protoreflect.Name) ([]byte, error) {
+	protobuf := c.contentType == "application/protobuf" || c.contentType == "application/x-protobuf"
+	if field == "" {
+		if protobuf {
+			return proto.Marshal(in)
+		}
+		return protojson.Marshal(in)
+	}
+
+	m := in.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName(field)
+	if protobuf {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, status.Errorf(codes.Unimplemented, "field %s cannot be sent in %s", field, c.contentType)
+		}
+		return proto.Marshal(m.Get(fd).Message().Interface())
+	}
+	// The JSON of the field is taken from the message which has only the field.
+	only := m.New()
+	if m.Has(fd) {
+		only.Set(fd, m.Get(fd))
+	}
+	buf, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(only.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return nil, err
+	}
+	return fields[fd.JSONName()], nil
+}
+
+// multiGreeterHTTPClientError converts the error response to the error of grpc/status.
+// If the body is not google.rpc.Status, the code is decided by the HTTP status code as gRPC clients do.
+func multiGreeterHTTPClientError(code int, contentType string, body []byte) error {
+	p := status.New(codes.Unknown, "").Proto()
+	var err error
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		err = proto.Unmarshal(body, p)
+	case "application/json":
+		err = protojson.Unmarshal(body, p)
+	default:
+		err = fmt.Errorf("unexpected Content-Type: %s", contentType)
+	}
+	if err == nil && codes.Code(p.GetCode()) != codes.OK {
+		return status.ErrorProto(p)
+	}
+
+	c := codes.Unknown
+	switch code {
+	case http.StatusBadRequest:
+		c = codes.Internal
+	case http.StatusUnauthorized:
+		c = codes.Unauthenticated
+	case http.StatusForbidden:
+		c = codes.PermissionDenied
+	case http.StatusNotFound:
+		c = codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		c = codes.Unavailable
+	}
+	return status.Errorf(c, "unexpected HTTP status code %d: %s", code, body)
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_incomingMetadata returns the metadata built from the headers listed in headers and the headers with prefix.
+// The empty prefix disables it. Values of the keys ending with "-bin" are decoded from base64 as gRPC does.
+func file_hellostreamingworld_hellostreamingworld_proto_incomingMetadata(header http.Header, headers []string, prefix string) metadata.MD {
+	md := metadata.MD{}
+	add := func(key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+
+	for _, key := range headers {
+		add(key, header.Values(key))
+	}
+	if prefix != "" {
+		for key, values := range header {
+			if len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+				add(key[len(prefix):], values)
+			}
+		}
+	}
+	return md
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream implements grpc.ServerTransportStream to write header and trailer metadata set by the RPC
+// as HTTP headers, so that grpc.SetHeader, grpc.SendHeader and grpc.SetTrailer work in the RPC.
+type file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream struct {
+	method        string
+	w             http.ResponseWriter
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+
+	mu         sync.Mutex
+	header     metadata.MD
+	trailer    metadata.MD
+	headerSent bool
+}
+
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream) Method() string {
+	return s.method
+}
+
+// SetHeader sets the header metadata written before the response body.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader sets the header metadata and writes it to the response headers.
+// The headers are sent to the client with the response body.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream) SendHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	s.writeHeaderLocked()
+	return nil
+}
+
+// SetTrailer sets the trailer metadata written after the RPC returns.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// writeHeader writes the header metadata to the response headers unless it has been written.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream) writeHeader() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeHeaderLocked()
+}
+
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream) writeHeaderLocked() {
+	if s.headerSent {
+		return
+	}
+	s.headerSent = true
+	s.add(s.headerPrefix, s.header, s.headers)
+}
+
+// writeTrailer writes the trailer metadata as HTTP trailers if asTrailer is true.
+// Otherwise, it is written to the response headers because the response body has not been written yet.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream) writeTrailer(asTrailer bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if asTrailer {
+		s.add(http.TrailerPrefix+s.trailerPrefix, s.trailer, nil)
+		return
+	}
+	s.add(s.trailerPrefix, s.trailer, nil)
+}
+
+// writeStatusCode writes the status code set by the header metadata "x-http-code" and reports whether
+// the response body is allowed. The status code is ignored unless it is between 200 and 599.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream) writeStatusCode() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vs := s.header.Get("x-http-code")
+	if len(vs) == 0 {
+		return true
+	}
+	code, err := strconv.Atoi(vs[len(vs)-1])
+	if err != nil || code < 200 || code > 599 {
+		return true
+	}
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		s.w.Header().Del("Content-Type")
+		s.w.Header().Del("Content-Encoding")
+		s.w.WriteHeader(code)
+		return false
+	}
+	s.w.WriteHeader(code)
+	return true
+}
+
+// add adds md to the response headers with the prefix. The keys listed in headers are added without the prefix.
+// Values of the keys ending with "-bin" are encoded in base64 as gRPC does.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream) add(prefix string, md metadata.MD, headers []string) {
+	for k, vs := range md {
+		if k == "x-http-code" {
+			continue
+		}
+		name := prefix + k
+		for _, header := range headers {
+			if strings.EqualFold(header, k) {
+				name = header
+				break
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			s.w.Header().Add(name, v)
+		}
+	}
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_timeout returns the timeout of the RPC set by Grpc-Timeout header or the header named name,
+// whose value is parsed by time.ParseDuration. The shorter one is used if both are set. Invalid values are ignored.
+func file_hellostreamingworld_hellostreamingworld_proto_timeout(header http.Header, name string) (time.Duration, bool) {
+	timeout, ok := file_hellostreamingworld_hellostreamingworld_proto_parseGRPCTimeout(header.Get("Grpc-Timeout"))
+	if name == "" {
+		return timeout, ok
+	}
+	v := header.Get(name)
+	if v == "" {
+		return timeout, ok
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return timeout, ok
+	}
+	if !ok || d < timeout {
+		return d, true
+	}
+	return timeout, true
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_parseGRPCTimeout parses v in the format of Grpc-Timeout header, that is at most 8 digits followed by the unit,
+// H (hours), M (minutes), S (seconds), m (milliseconds), u (microseconds) or n (nanoseconds).
+func file_hellostreamingworld_hellostreamingworld_proto_parseGRPCTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 || len(v) > 9 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch v[len(v)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	n, err := strconv.ParseUint(v[:len(v)-1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	if time.Duration(n) > math.MaxInt64/unit {
+		return math.MaxInt64, true
+	}
+	return time.Duration(n) * unit, true
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_requestPeer returns the peer of the RPC built from the remote address and the TLS connection state of r.
+// It returns nil if the remote address is not an IP address and port.
+func file_hellostreamingworld_hellostreamingworld_proto_requestPeer(r *http.Request) *peer.Peer {
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return nil
+	}
+	p := &peer.Peer{
+		Addr: &net.TCPAddr{IP: ip, Port: n},
+	}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{
+			State: *r.TLS,
+			CommonAuthInfo: credentials.CommonAuthInfo{
+				SecurityLevel: credentials.PrivacyAndIntegrity,
+			},
+		}
+	}
+	return p
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_httpRequestBody counts the bytes read from the request body.
+type file_hellostreamingworld_hellostreamingworld_proto_httpRequestBody struct {
+	io.ReadCloser
+	size int64
+}
+
+func (b *file_hellostreamingworld_hellostreamingworld_proto_httpRequestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_httpResponseWriter records the status code and the size of the response written to http.ResponseWriter.
+type file_hellostreamingworld_hellostreamingworld_proto_httpResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int64
+}
+
+func (w *file_hellostreamingworld_hellostreamingworld_proto_httpResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *file_hellostreamingworld_hellostreamingworld_proto_httpResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher to flush streaming responses.
+func (w *file_hellostreamingworld_hellostreamingworld_proto_httpResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter for http.ResponseController.
+func (w *file_hellostreamingworld_hellostreamingworld_proto_httpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// status returns the status code of the response. It is 200 if nothing has been written as net/http does.
+func (w *file_hellostreamingworld_hellostreamingworld_proto_httpResponseWriter) status() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+	return w.statusCode
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_redact returns m marshaled in JSON without the fields marked with debug_redact option.
+func file_hellostreamingworld_hellostreamingworld_proto_redact(m proto.Message) string {
+	buf, err := protojson.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(buf)
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_decodeBody replaces the body of r with the reader decoding encoding, which is gzip, x-gzip or deflate.
+// A malformed body fails with codes.InvalidArgument.
+func file_hellostreamingworld_hellostreamingworld_proto_decodeBody(r *http.Request, encoding string) error {
+	var zr io.ReadCloser
+	var err error
+	if encoding == "deflate" {
+		zr, err = zlib.NewReader(r.Body)
+	} else {
+		zr, err = gzip.NewReader(r.Body)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", encoding, err)
+	}
+	r.Body = &file_hellostreamingworld_hellostreamingworld_proto_decodedBody{ReadCloser: zr, encoding: encoding}
+	return nil
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_decodedBody is the request body decoded by Content-Encoding header.
+// Reading it fails with codes.InvalidArgument if the rest of the body turns out to be malformed.
+type file_hellostreamingworld_hellostreamingworld_proto_decodedBody struct {
+	io.ReadCloser
+	encoding string
+}
+
+func (b *file_hellostreamingworld_hellostreamingworld_proto_decodedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", b.encoding, err)
+	}
+	return n, err
+}
+
+// file_hellostreamingworld_hellostreamingworld_proto_httpServerStream implements grpc.ServerStream on HTTP.
+// Messages are read from the request body as newline-delimited JSON or varint length-prefixed protobuf,
+// and written as newline-delimited JSON, varint length-prefixed protobuf or Server-Sent Events.
+type file_hellostreamingworld_hellostreamingworld_proto_httpServerStream struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	w           http.ResponseWriter
+	body        *bufio.Reader
+	contentType string
+	accept      string
+
+	// clientStream is true if the RPC is client-streaming.
+	// The response of client-streaming RPC is held in ret and written after the method returns.
+	clientStream bool
+	ret          proto.Message
+
+	ts    *file_hellostreamingworld_hellostreamingworld_proto_httpTransportStream
+	wrote bool
+}
+
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpServerStream) SetHeader(md metadata.MD) error {
+	return s.ts.SetHeader(md)
+}
+
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpServerStream) SendHeader(md metadata.MD) error {
+	return s.ts.SendHeader(md)
+}
+
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpServerStream) SetTrailer(md metadata.MD) {
+	_ = s.ts.SetTrailer(md)
+}
+
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpServerStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%T is not proto.Message", m)
+	}
+
+	if s.clientStream {
+		if s.ret != nil {
+			return fmt.Errorf("the response has already been sent")
+		}
+		s.ret = msg
+		return nil
+	}
+
+	var buf []byte
+	switch s.accept {
+	case "application/protobuf", "application/x-protobuf":
+		b, err := proto.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		buf = append(protowire.AppendVarint(nil, uint64(len(b))), b...)
+	case "text/event-stream":
+		b, err := protojson.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		buf = append(append([]byte("data: "), b...), '\n', '\n')
+	default:
+		b, err := protojson.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		buf = append(b, '\n')
+	}
+
+	return s.write(buf)
+}
+
+// RecvMsg reads the next message from the request body. It returns io.EOF at the end of the body.
+// Server-streaming RPC has no body to read because the request message is passed to the method as an argument.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpServerStream) RecvMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%T is not proto.Message", m)
+	}
+	if s.body == nil {
+		return io.EOF
+	}
+
+	switch s.contentType {
+	case "application/protobuf", "application/x-protobuf":
+		size, err := binary.ReadUvarint(s.body)
+		if err != nil {
+			return err
+		}
+		buf, err := io.ReadAll(io.LimitReader(s.body, int64(size)))
+		if err != nil {
+			return err
+		}
+		if uint64(len(buf)) != size {
+			return io.ErrUnexpectedEOF
+		}
+		return proto.Unmarshal(buf, msg)
+	default:
+		for {
+			line, err := s.body.ReadBytes('\n')
+			// The last line may not end with a newline, but the line is incomplete on other errors.
+			if err != nil && err != io.EOF {
+				return err
+			}
+			if len(bytes.TrimSpace(line)) != 0 {
+				return protojson.Unmarshal(line, msg)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// write writes buf and flushes it. The context is canceled if the client has gone away.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpServerStream) write(buf []byte) error {
+	s.ts.writeHeader()
+	s.wrote = true
+	if _, err := s.w.Write(buf); err != nil {
+		s.cancel()
+		return err
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// finish writes the status of the RPC after the messages so that the client can tell a failed stream from a complete one.
+// Server-Sent Events end with the status event, and newline-delimited JSON ends with an {"error": status} line
+// if the RPC fails. Every framing also ends with the Grpc-Status and Grpc-Message trailers once the response has started.
+// Before the response has started, the status is left to the callback.
+func (s *file_hellostreamingworld_hellostreamingworld_proto_httpServerStream) finish(err error) {
+	st := status.Convert(err)
+	switch {
+	case s.accept == "text/event-stream":
+		if b, merr := protojson.Marshal(st.Proto()); merr == nil {
+			_ = s.write(append(append([]byte("event: status\ndata: "), b...), '\n', '\n'))
+		}
+	case !s.wrote:
+	case err != nil && s.accept != "application/protobuf" && s.accept != "application/x-protobuf":
+		if b, merr := protojson.Marshal(st.Proto()); merr == nil {
+			_ = s.write(append(append([]byte(`{"error":`), b...), '}', '\n'))
+		}
+	}
+	if !s.wrote {
+		return
+	}
+	s.w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(int(st.Code())))
+	if msg := st.Message(); msg != "" {
+		s.w.Header().Set(http.TrailerPrefix+"Grpc-Message", url.PathEscape(msg))
+	}
+}
@@ -5,20 +5,36 @@ package httprulepb
 
 import (
 	bytes "bytes"
+	gzip "compress/gzip"
+	zlib "compress/zlib"
 	context "context"
+	_ "embed"
 	base64 "encoding/base64"
+	json "encoding/json"
+	errors "errors"
 	fmt "fmt"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
+	credentials "google.golang.org/grpc/credentials"
+	metadata "google.golang.org/grpc/metadata"
+	peer "google.golang.org/grpc/peer"
 	status "google.golang.org/grpc/status"
 	protojson "google.golang.org/protobuf/encoding/protojson"
 	proto "google.golang.org/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	io "io"
 	ioutil "io/ioutil"
+	slog "log/slog"
+	math "math"
 	mime "mime"
+	net "net"
 	http "net/http"
+	url "net/url"
+	debug "runtime/debug"
 	strconv "strconv"
 	strings "strings"
+	sync "sync"
+	time "time"
 )
 
 // AllPatternHTTPService is the server API for AllPattern service.
@@ -26,25 +42,324 @@ type AllPatternHTTPService interface {
 	AllPattern(context.Context, *AllPatternRequest) (*AllPatternResponse, error)
 }
 
+// AllPatternHTTPTracer is called at the start and the finish of every request handled by AllPatternHTTPConverter,
+// e.g. to start and end a span of a tracing library without depending on it.
+type AllPatternHTTPTracer interface {
+	// OnStart is called when the handler of method starts handling r. route is the path template of google.api.http option,
+	// or empty if the handler is not returned by the HTTPRule method. The returned context is passed to the RPC and OnFinish.
+	OnStart(ctx context.Context, method protoreflect.MethodDescriptor, route string, r *http.Request) context.Context
+	// OnFinish is called after the callback with the arguments passed to the callback, the status code of the response
+	// and the time elapsed since OnStart. The gRPC code of the RPC is status.Code(err).
+	OnFinish(ctx context.Context, method protoreflect.MethodDescriptor, arg, ret proto.Message, statusCode int, err error, elapsed time.Duration)
+}
+
+// AllPatternHTTPObserver observes the metrics of every request handled by AllPatternHTTPConverter,
+// e.g. to export the latency, the sizes and the status of RPCs to a metrics library without depending on it.
+type AllPatternHTTPObserver interface {
+	// Observe is called after the callback with the method, the path template of google.api.http option
+	// (empty if the handler is not returned by the HTTPRule method), the status code of the response,
+	// the gRPC code of the RPC, the bytes read from the request body and written to the response body, and the latency.
+	Observe(ctx context.Context, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, requestSize, responseSize int64, elapsed time.Duration)
+}
+
 // AllPatternHTTPConverter has a function to convert AllPatternHTTPService interface to http.HandlerFunc.
 type AllPatternHTTPConverter struct {
-	srv AllPatternHTTPService
+	srv                   AllPatternHTTPService
+	cb                    func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)
+	interceptors          []grpc.UnaryServerInterceptor
+	maxBodySize           int64
+	skipMethodCheck       bool
+	incomingHeaders       []string
+	incomingHeaderPrefix  string
+	outgoingHeaders       []string
+	outgoingHeaderPrefix  string
+	outgoingTrailerPrefix string
+	timeoutHeader         string
+	tracer                AllPatternHTTPTracer
+	observer              AllPatternHTTPObserver
+	accessLogger          *slog.Logger
+	accessLogPayloads     bool
+	recovery              bool
+	panicHandler          func(ctx context.Context, fullMethod string, p interface{}, stack []byte)
+	compressionMinSize    int
+}
+
+// AllPatternHTTPConverterOption configures AllPatternHTTPConverter.
+type AllPatternHTTPConverterOption func(*AllPatternHTTPConverter)
+
+// WithAllPatternHTTPCallback sets the callback used when nil is passed to a convert method.
+func WithAllPatternHTTPCallback(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.cb = cb
+	}
+}
+
+// WithAllPatternHTTPInterceptors appends interceptors executed before the interceptors passed to a convert method.
+func WithAllPatternHTTPInterceptors(interceptors ...grpc.UnaryServerInterceptor) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.interceptors = append(h.interceptors, interceptors...)
+	}
+}
+
+// WithAllPatternHTTPMaxBodySize sets the maximum size of request bodies in bytes.
+// The default is 4194304 bytes set by max_body_size option of protoc-gen-gohttp. Zero or a negative value means no limit.
+// Requests with larger bodies fail with *http.MaxBytesError, and the default callback responds 413 Request Entity Too Large.
+func WithAllPatternHTTPMaxBodySize(n int64) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.maxBodySize = n
+	}
+}
+
+// WithAllPatternHTTPSkipMethodCheck sets whether the handlers returned by HTTPRule methods skip checking the request method.
+// By default, the handlers respond 405 Method Not Allowed with Allow header if the request method does not match google.api.http option.
+// Routers that already route requests by the method can skip the check.
+func WithAllPatternHTTPSkipMethodCheck(skip bool) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.skipMethodCheck = skip
+	}
+}
+
+// WithAllPatternHTTPIncomingHeaders sets the request headers passed to the RPC as incoming metadata.
+// The default is Authorization. The metadata keys are the lowercase header names.
+func WithAllPatternHTTPIncomingHeaders(headers ...string) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.incomingHeaders = headers
+	}
+}
+
+// WithAllPatternHTTPIncomingHeaderPrefix sets the prefix of the request headers passed to the RPC as incoming metadata.
+// The metadata keys are the lowercase header names without the prefix. The default is "Grpc-Metadata-",
+// and the empty prefix disables it.
+func WithAllPatternHTTPIncomingHeaderPrefix(prefix string) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.incomingHeaderPrefix = prefix
+	}
+}
+
+// WithAllPatternHTTPOutgoingHeaders sets the response headers written from the header metadata with the same
+// lowercase names, e.g. "location" for Location. They are written without the prefix. The default is Location.
+func WithAllPatternHTTPOutgoingHeaders(headers ...string) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.outgoingHeaders = headers
+	}
+}
+
+// WithAllPatternHTTPOutgoingHeaderPrefix sets the prefix of the response headers written from the header metadata
+// set by grpc.SetHeader or grpc.SendHeader. The default is "Grpc-Metadata-".
+func WithAllPatternHTTPOutgoingHeaderPrefix(prefix string) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.outgoingHeaderPrefix = prefix
+	}
+}
+
+// WithAllPatternHTTPOutgoingTrailerPrefix sets the prefix of the response headers or trailers written from the trailer metadata
+// set by grpc.SetTrailer. The default is "Grpc-Trailer-".
+func WithAllPatternHTTPOutgoingTrailerPrefix(prefix string) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.outgoingTrailerPrefix = prefix
+	}
+}
+
+// WithAllPatternHTTPTimeoutHeader sets the request header of the timeout of the RPC in addition to Grpc-Timeout header.
+// The value is parsed by time.ParseDuration, e.g. "1.5s". The empty name disables it, which is the default.
+func WithAllPatternHTTPTimeoutHeader(name string) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.timeoutHeader = name
+	}
+}
+
+// WithAllPatternHTTPTracer sets the tracer called at the start and the finish of every request.
+func WithAllPatternHTTPTracer(tracer AllPatternHTTPTracer) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.tracer = tracer
+	}
+}
+
+// WithAllPatternHTTPObserver sets the observer called at the finish of every request.
+func WithAllPatternHTTPObserver(observer AllPatternHTTPObserver) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.observer = observer
+	}
+}
+
+// WithAllPatternHTTPAccessLog sets the logger emitting an access log record of every request with the full method,
+// the HTTP method, the route, the status code, the gRPC code and the latency. Requests responded with 5xx are logged
+// at error level, and the others are logged at info level. The access log is disabled by default.
+func WithAllPatternHTTPAccessLog(logger *slog.Logger) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.accessLogger = logger
+	}
+}
+
+// WithAllPatternHTTPAccessLogPayloads sets whether the access log contains the request and response messages in JSON.
+// Fields marked with debug_redact option are removed from the messages.
+func WithAllPatternHTTPAccessLogPayloads(payloads bool) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.accessLogPayloads = payloads
+	}
+}
+
+// WithAllPatternHTTPRecovery enables the recovery of panics in the RPC and the interceptors. The panic is converted to
+// *AllPatternHTTPPanicError of codes.Internal passed to the callback, and reported to handler with the stack trace
+// unless handler is nil. Panics are not recovered by default.
+func WithAllPatternHTTPRecovery(handler func(ctx context.Context, fullMethod string, p interface{}, stack []byte)) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.recovery = true
+		h.panicHandler = handler
+	}
+}
+
+// WithAllPatternHTTPCompression enables the compression of response bodies of unary and client-side streaming RPCs
+// with gzip or deflate negotiated by Accept-Encoding header. Only bodies of at least minSize bytes are compressed.
+// A negative value disables it, which is the default.
+func WithAllPatternHTTPCompression(minSize int) AllPatternHTTPConverterOption {
+	return func(h *AllPatternHTTPConverter) {
+		h.compressionMinSize = minSize
+	}
 }
 
 // NewAllPatternHTTPConverter returns AllPatternHTTPConverter.
-func NewAllPatternHTTPConverter(srv AllPatternHTTPService) *AllPatternHTTPConverter {
-	return &AllPatternHTTPConverter{
-		srv: srv,
+func NewAllPatternHTTPConverter(srv AllPatternHTTPService, opts ...AllPatternHTTPConverterOption) *AllPatternHTTPConverter {
+	h := &AllPatternHTTPConverter{
+		srv:                   srv,
+		maxBodySize:           4194304,
+		incomingHeaders:       []string{"Authorization"},
+		incomingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingHeaders:       []string{"Location"},
+		outgoingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingTrailerPrefix: "Grpc-Trailer-",
+		compressionMinSize:    -1,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// trace calls OnStart of the tracer, and returns the context returned by OnStart, w recording the response
+// and cb calling OnFinish of the tracer, Observe of the observer and the access logger after cb.
+// The body of r is replaced to count the bytes read.
+func (h *AllPatternHTTPConverter) trace(ctx context.Context, w http.ResponseWriter, r *http.Request, name protoreflect.Name, route string, cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) (context.Context, http.ResponseWriter, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) {
+	method := File_httprule_all_pattern_proto.Services().ByName("AllPattern").Methods().ByName(name)
+	start := time.Now()
+	if h.tracer != nil {
+		ctx = h.tracer.OnStart(ctx, method, route, r)
+	}
+	rw := &file_httprule_all_pattern_proto_httpResponseWriter{ResponseWriter: w}
+	body := &file_httprule_all_pattern_proto_httpRequestBody{ReadCloser: r.Body}
+	r.Body = body
+	return ctx, rw, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+		cb(ctx, w, r, arg, ret, err)
+		elapsed := time.Since(start)
+		if h.tracer != nil {
+			h.tracer.OnFinish(ctx, method, arg, ret, rw.status(), err, elapsed)
+		}
+		code := status.Code(err)
+		switch {
+		case code != codes.Unknown:
+		case errors.Is(err, context.DeadlineExceeded):
+			code = codes.DeadlineExceeded
+		case errors.Is(err, context.Canceled):
+			code = codes.Canceled
+		}
+		if h.observer != nil {
+			h.observer.Observe(ctx, method, route, rw.status(), code, body.size, rw.size, elapsed)
+		}
+		if h.accessLogger != nil {
+			h.logAccess(ctx, r, method, route, rw.status(), code, err, elapsed, arg, ret)
+		}
+	}
+}
+
+// logAccess emits the access log record of the request.
+func (h *AllPatternHTTPConverter) logAccess(ctx context.Context, r *http.Request, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, err error, elapsed time.Duration, arg, ret proto.Message) {
+	level := slog.LevelInfo
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	attrs := []slog.Attr{
+		slog.String("method", fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())),
+		slog.String("http_method", r.Method),
+		slog.String("route", route),
+		slog.Int("status", statusCode),
+		slog.String("code", code.String()),
+		slog.Duration("latency", elapsed),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if h.accessLogPayloads {
+		if arg != nil {
+			attrs = append(attrs, slog.String("request", file_httprule_all_pattern_proto_redact(arg)))
+		}
+		if ret != nil {
+			attrs = append(attrs, slog.String("response", file_httprule_all_pattern_proto_redact(ret)))
+		}
+	}
+	h.accessLogger.LogAttrs(ctx, level, "access", attrs...)
+}
+
+// AllPatternHTTPPanicError is the error of a panic recovered by AllPatternHTTPConverter.
+type AllPatternHTTPPanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine panicked.
+	Stack []byte
+}
+
+func (e *AllPatternHTTPPanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// GRPCStatus returns the status of codes.Internal, so that status.Code returns codes.Internal for the error.
+// The message of the status does not contain the panic value, which must not be sent to clients.
+func (e *AllPatternHTTPPanicError) GRPCStatus() *status.Status {
+	return status.New(codes.Internal, "internal error")
+}
+
+// recovered reports the panic p to the panic handler and returns it as *AllPatternHTTPPanicError.
+func (h *AllPatternHTTPConverter) recovered(ctx context.Context, fullMethod string, p interface{}) error {
+	err := &AllPatternHTTPPanicError{Value: p, Stack: debug.Stack()}
+	if h.panicHandler != nil {
+		h.panicHandler(ctx, fullMethod, p, err.Stack)
 	}
+	return err
+}
+
+// recoverUnary is the outermost interceptor recovering panics in the RPC and the interceptors.
+func (h *AllPatternHTTPConverter) recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = h.recovered(ctx, info.FullMethod, p)
+		}
+	}()
+	return handler(ctx, req)
 }
 
 // AllPattern returns AllPatternHTTPService interface's AllPattern converted to http.HandlerFunc.
 func (h *AllPatternHTTPConverter) AllPattern(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -67,8 +382,35 @@ func (h *AllPatternHTTPConverter) AllPattern(cb func(ctx context.Context, w http
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_httprule_all_pattern_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_all_pattern_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_all_pattern_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "AllPattern", "", cb)
+		}
+		ts := &file_httprule_all_pattern_proto_httpTransportStream{
+			method:        "/httprule.AllPattern/AllPattern",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -86,6 +428,22 @@ func (h *AllPatternHTTPConverter) AllPattern(cb func(ctx context.Context, w http
 
 		arg := &AllPatternRequest{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_all_pattern_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -136,6 +494,8 @@ func (h *AllPatternHTTPConverter) AllPattern(cb func(ctx context.Context, w http
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -154,6 +514,11 @@ func (h *AllPatternHTTPConverter) AllPattern(cb func(ctx context.Context, w http
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_all_pattern_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -164,6 +529,11 @@ func (h *AllPatternHTTPConverter) AllPattern(cb func(ctx context.Context, w http
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_all_pattern_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -185,11 +555,28 @@ func (h *AllPatternHTTPConverter) AllPatternWithName(cb func(ctx context.Context
 
 // AllPatternHTTPRule returns HTTP method, path and AllPatternHTTPService interface's AllPattern converted to http.HandlerFunc.
 func (h *AllPatternHTTPConverter) AllPatternHTTPRule(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -212,8 +599,35 @@ func (h *AllPatternHTTPConverter) AllPatternHTTPRule(cb func(ctx context.Context
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.MethodGet, "/all/pattern", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_httprule_all_pattern_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_all_pattern_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_all_pattern_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "AllPattern", "/all/pattern", cb)
+		}
+		ts := &file_httprule_all_pattern_proto_httpTransportStream{
+			method:        "/httprule.AllPattern/AllPattern",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -229,8 +643,16 @@ func (h *AllPatternHTTPConverter) AllPatternHTTPRule(cb func(ctx context.Context
 
 		w.Header().Set("Content-Type", accept)
 
+		if !h.skipMethodCheck && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, err := fmt.Fprintf(w, "Method Not Allowed: %s", r.Method)
+			cb(ctx, w, r, nil, nil, err)
+			return
+		}
+
 		arg := &AllPatternRequest{}
-		if r.Method == http.MethodGet {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
 			if v := r.URL.Query().Get("double"); v != "" {
 				c, err := strconv.ParseFloat(v, 64)
 				if err != nil {
@@ -508,6 +930,8 @@ func (h *AllPatternHTTPConverter) AllPatternHTTPRule(cb func(ctx context.Context
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -526,6 +950,11 @@ func (h *AllPatternHTTPConverter) AllPatternHTTPRule(cb func(ctx context.Context
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_all_pattern_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -536,6 +965,11 @@ func (h *AllPatternHTTPConverter) AllPatternHTTPRule(cb func(ctx context.Context
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_all_pattern_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -549,3 +983,923 @@ func (h *AllPatternHTTPConverter) AllPatternHTTPRule(cb func(ctx context.Context
 		cb(ctx, w, r, arg, ret, nil)
 	})
 }
+
+// RegisterAllPatternHTTPHandlers registers all methods of AllPatternHTTPService on mux.
+// Methods with google.api.http option are registered with its HTTP method and path,
+// other methods are registered with POST /{package}.{Service}/{Method}.
+func RegisterAllPatternHTTPHandlers(mux *http.ServeMux, conv *AllPatternHTTPConverter) {
+	var hf http.HandlerFunc
+	_, _, hf = conv.AllPatternHTTPRule(nil)
+	mux.Handle("GET /all/pattern", hf)
+}
+
+// AllPatternHTTPRoute is a route of AllPatternHTTPService method.
+type AllPatternHTTPRoute struct {
+	// Method is HTTP method of the route.
+	Method string
+	// Pattern is path template of google.api.http option, or /{package}.{Service}/{Method} if the option is not defined.
+	Pattern string
+	// Handler is AllPatternHTTPService method converted to http.HandlerFunc.
+	Handler http.HandlerFunc
+	// FullMethod is the full RPC method string, i.e., /package.service/method.
+	FullMethod string
+}
+
+// AllPatternHTTPRouter is the interface of HTTP routers that AllPatternHTTPRoute is registered on.
+type AllPatternHTTPRouter interface {
+	Handle(method, pattern string, handler http.Handler)
+}
+
+// AllPatternHTTPRouterFunc is an adapter to use a function as AllPatternHTTPRouter.
+type AllPatternHTTPRouterFunc func(method, pattern string, handler http.Handler)
+
+// Handle calls f(method, pattern, handler).
+func (f AllPatternHTTPRouterFunc) Handle(method, pattern string, handler http.Handler) {
+	f(method, pattern, handler)
+}
+
+// AllPatternHTTPRoutes returns routes of all methods of AllPatternHTTPService converted by conv.
+func AllPatternHTTPRoutes(conv *AllPatternHTTPConverter) []AllPatternHTTPRoute {
+	routes := make([]AllPatternHTTPRoute, 0)
+	{
+		method, pattern, handler := conv.AllPatternHTTPRule(nil)
+		routes = append(routes, AllPatternHTTPRoute{
+			Method:     method,
+			Pattern:    pattern,
+			Handler:    handler,
+			FullMethod: "/httprule.AllPattern/AllPattern",
+		})
+	}
+	return routes
+}
+
+// RegisterAllPatternHTTPRoutes registers all routes of AllPatternHTTPService on router.
+func RegisterAllPatternHTTPRoutes(router AllPatternHTTPRouter, conv *AllPatternHTTPConverter) {
+	for _, route := range AllPatternHTTPRoutes(conv) {
+		router.Handle(route.Method, route.Pattern, route.Handler)
+	}
+}
+
+// AllPatternHTTPRoutesHandler returns http.Handler writing HTTP method, pattern and full method name of all routes
+// of conv as JSON.
+func AllPatternHTTPRoutesHandler(conv *AllPatternHTTPConverter) http.Handler {
+	type route struct {
+		Method     string `json:"method"`
+		Pattern    string `json:"pattern"`
+		FullMethod string `json:"fullMethod"`
+	}
+	routes := AllPatternHTTPRoutes(conv)
+	rs := make([]route, 0, len(routes))
+	for _, r := range routes {
+		rs = append(rs, route{Method: r.Method, Pattern: r.Pattern, FullMethod: r.FullMethod})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(rs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// AllPatternHTTPOpenAPIHandler returns http.Handler serving OpenAPI document of httprule/all_pattern.proto.
+func AllPatternHTTPOpenAPIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(file_httprule_all_pattern_proto_openAPI)
+	})
+}
+
+// AllPatternHTTPClient is the client API for AllPattern service over HTTP.
+// Only unary methods are implemented.
+type AllPatternHTTPClient struct {
+	baseURL       string
+	client        *http.Client
+	contentType   string
+	interceptors  []grpc.UnaryClientInterceptor
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+}
+
+// AllPatternHTTPClientOption configures AllPatternHTTPClient.
+type AllPatternHTTPClientOption func(*AllPatternHTTPClient)
+
+// WithAllPatternHTTPClient sets http.Client used to send requests. http.DefaultClient is used by default.
+func WithAllPatternHTTPClient(client *http.Client) AllPatternHTTPClientOption {
+	return func(c *AllPatternHTTPClient) {
+		c.client = client
+	}
+}
+
+// WithAllPatternHTTPClientContentType sets the media type of requests and responses.
+// "application/json" (default), "application/protobuf" and "application/x-protobuf" are supported.
+func WithAllPatternHTTPClientContentType(contentType string) AllPatternHTTPClientOption {
+	return func(c *AllPatternHTTPClient) {
+		c.contentType = contentType
+	}
+}
+
+// WithAllPatternHTTPClientInterceptors appends interceptors executed in left-to-right order for each call.
+// The method passed to the interceptors is the full method name, e.g., "/httprule.AllPattern/Method", and cc is always nil.
+func WithAllPatternHTTPClientInterceptors(interceptors ...grpc.UnaryClientInterceptor) AllPatternHTTPClientOption {
+	return func(c *AllPatternHTTPClient) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithAllPatternHTTPClientHeaders sets the outgoing metadata keys sent as the request headers of the same names without the prefix.
+// The response headers of the names are also read as the header metadata.
+// The default is Authorization, the same as the incoming headers of the converter.
+func WithAllPatternHTTPClientHeaders(headers ...string) AllPatternHTTPClientOption {
+	return func(c *AllPatternHTTPClient) {
+		c.headers = headers
+	}
+}
+
+// WithAllPatternHTTPClientHeaderPrefix sets the prefix of the request headers sending the other outgoing metadata,
+// and of the response headers read as the header metadata.
+// The default is "Grpc-Metadata-", the same as the incoming and outgoing header prefixes of the converter.
+func WithAllPatternHTTPClientHeaderPrefix(prefix string) AllPatternHTTPClientOption {
+	return func(c *AllPatternHTTPClient) {
+		c.headerPrefix = prefix
+	}
+}
+
+// WithAllPatternHTTPClientTrailerPrefix sets the prefix of the response headers read as the trailer metadata.
+// The default is "Grpc-Trailer-", the same as the outgoing trailer prefix of the converter.
+func WithAllPatternHTTPClientTrailerPrefix(prefix string) AllPatternHTTPClientOption {
+	return func(c *AllPatternHTTPClient) {
+		c.trailerPrefix = prefix
+	}
+}
+
+// NewAllPatternHTTPClient returns AllPatternHTTPClient sending requests to baseURL, e.g., "http://localhost:8080".
+func NewAllPatternHTTPClient(baseURL string, opts ...AllPatternHTTPClientOption) *AllPatternHTTPClient {
+	c := &AllPatternHTTPClient{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		client:        http.DefaultClient,
+		contentType:   "application/json",
+		headers:       []string{"Authorization"},
+		headerPrefix:  "Grpc-Metadata-",
+		trailerPrefix: "Grpc-Trailer-",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// AllPattern calls AllPattern with GET /all/pattern.
+func (c *AllPatternHTTPClient) AllPattern(ctx context.Context, in *AllPatternRequest) (*AllPatternResponse, error) {
+	out := &AllPatternResponse{}
+	if err := c.invoke(ctx, "/httprule.AllPattern/AllPattern", in, out, c.invokeAllPattern); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeAllPattern is the grpc.UnaryInvoker sending the request of AllPattern.
+func (c *AllPatternHTTPClient) invokeAllPattern(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*AllPatternRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*AllPatternResponse)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	path := "/all/pattern"
+	query := url.Values{}
+	if v := in.GetDouble(); v != 0 {
+		query.Set("double", strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	if v := in.GetFloat(); v != 0 {
+		query.Set("float", strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	if v := in.GetInt32(); v != 0 {
+		query.Set("int32", strconv.FormatInt(int64(v), 10))
+	}
+	if v := in.GetInt64(); v != 0 {
+		query.Set("int64", strconv.FormatInt(int64(v), 10))
+	}
+	if v := in.GetUint32(); v != 0 {
+		query.Set("uint32", strconv.FormatUint(uint64(v), 10))
+	}
+	if v := in.GetUint64(); v != 0 {
+		query.Set("uint64", strconv.FormatUint(uint64(v), 10))
+	}
+	if v := in.GetFixed32(); v != 0 {
+		query.Set("fixed32", strconv.FormatUint(uint64(v), 10))
+	}
+	if v := in.GetFixed64(); v != 0 {
+		query.Set("fixed64", strconv.FormatUint(uint64(v), 10))
+	}
+	if v := in.GetSfixed32(); v != 0 {
+		query.Set("sfixed32", strconv.FormatInt(int64(v), 10))
+	}
+	if v := in.GetSfixed64(); v != 0 {
+		query.Set("sfixed64", strconv.FormatInt(int64(v), 10))
+	}
+	if v := in.GetBool(); v != false {
+		query.Set("bool", strconv.FormatBool(v))
+	}
+	if v := in.GetString_(); v != "" {
+		query.Set("string", v)
+	}
+	if v := in.GetBytes(); len(v) != 0 {
+		query.Set("bytes", base64.StdEncoding.EncodeToString(v))
+	}
+	for _, v := range in.GetRepeatedDouble() {
+		query.Add("repeated_double", strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	for _, v := range in.GetRepeatedFloat() {
+		query.Add("repeated_float", strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	for _, v := range in.GetRepeatedInt32() {
+		query.Add("repeated_int32", strconv.FormatInt(int64(v), 10))
+	}
+	for _, v := range in.GetRepeatedInt64() {
+		query.Add("repeated_int64", strconv.FormatInt(int64(v), 10))
+	}
+	for _, v := range in.GetRepeatedUint32() {
+		query.Add("repeated_uint32", strconv.FormatUint(uint64(v), 10))
+	}
+	for _, v := range in.GetRepeatedUint64() {
+		query.Add("repeated_uint64", strconv.FormatUint(uint64(v), 10))
+	}
+	for _, v := range in.GetRepeatedFixed32() {
+		query.Add("repeated_fixed32", strconv.FormatUint(uint64(v), 10))
+	}
+	for _, v := range in.GetRepeatedFixed64() {
+		query.Add("repeated_fixed64", strconv.FormatUint(uint64(v), 10))
+	}
+	for _, v := range in.GetRepeatedSfixed32() {
+		query.Add("repeated_sfixed32", strconv.FormatInt(int64(v), 10))
+	}
+	for _, v := range in.GetRepeatedSfixed64() {
+		query.Add("repeated_sfixed64", strconv.FormatInt(int64(v), 10))
+	}
+	for _, v := range in.GetRepeatedBool() {
+		query.Add("repeated_bool", strconv.FormatBool(v))
+	}
+	for _, v := range in.GetRepeatedString() {
+		query.Add("repeated_string", v)
+	}
+	for _, v := range in.GetRepeatedBytes() {
+		query.Add("repeated_bytes", base64.StdEncoding.EncodeToString(v))
+	}
+	return c.do(ctx, http.MethodGet, path, query, nil, "", out, opts...)
+}
+
+var _ AllPatternHTTPService = (*AllPatternHTTPClient)(nil)
+
+// AllPatternHTTPClientConn implements grpc.ClientConnInterface by AllPatternHTTPClient, so the client can be used as
+// the connection of gRPC clients, e.g., NewAllPatternClient(NewAllPatternHTTPClientConn(NewAllPatternHTTPClient(baseURL))).
+type AllPatternHTTPClientConn struct {
+	c *AllPatternHTTPClient
+}
+
+// NewAllPatternHTTPClientConn returns AllPatternHTTPClientConn sending the RPCs by c.
+func NewAllPatternHTTPClientConn(c *AllPatternHTTPClient) *AllPatternHTTPClientConn {
+	return &AllPatternHTTPClientConn{c: c}
+}
+
+// Invoke sends the unary RPC of method over HTTP. The HTTP method and path are decided by
+// google.api.http option of the method in the same way as the methods of AllPatternHTTPClient.
+// The response metadata is set to grpc.Header and grpc.Trailer of opts, and the other options are ignored.
+func (cc *AllPatternHTTPClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	switch method {
+	case "/httprule.AllPattern/AllPattern":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeAllPattern, opts...)
+	}
+	return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by AllPatternHTTPClient.
+func (cc *AllPatternHTTPClientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streaming RPC %s is not supported over HTTP", method)
+}
+
+var _ grpc.ClientConnInterface = (*AllPatternHTTPClientConn)(nil)
+
+// Invoke is the same as Invoke of AllPatternHTTPClientConn. Invoke and NewStream implement grpc.ClientConnInterface,
+// so the client can be used as the connection of gRPC clients, e.g., NewAllPatternClient(NewAllPatternHTTPClient(baseURL)).
+func (c *AllPatternHTTPClient) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return NewAllPatternHTTPClientConn(c).Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by AllPatternHTTPClient.
+func (c *AllPatternHTTPClient) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return NewAllPatternHTTPClientConn(c).NewStream(ctx, desc, method, opts...)
+}
+
+var _ grpc.ClientConnInterface = (*AllPatternHTTPClient)(nil)
+
+// invoke calls invoker through the interceptors.
+func (c *AllPatternHTTPClient) invoke(ctx context.Context, method string, req, reply interface{}, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	chainer := func(currentInter grpc.UnaryClientInterceptor, currentInvoker grpc.UnaryInvoker) grpc.UnaryInvoker {
+		return func(currentCtx context.Context, currentMethod string, currentReq, currentReply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return currentInter(currentCtx, currentMethod, currentReq, currentReply, cc, currentInvoker, opts...)
+		}
+	}
+	chainedInvoker := invoker
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		chainedInvoker = chainer(c.interceptors[i], chainedInvoker)
+	}
+	return chainedInvoker(ctx, method, req, reply, nil, opts...)
+}
+
+// do sends in, or the field of in if field is not empty, as the request body unless in is nil,
+// and unmarshals the response into out. The response metadata is set to grpc.Header and grpc.Trailer of opts.
+func (c *AllPatternHTTPClient) do(ctx context.Context, method, path string, query url.Values, in proto.Message, field protoreflect.Name, out proto.Message, opts ...grpc.CallOption) error {
+	u := c.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		buf, err := c.marshal(in, field)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	// Content-Type is also set to GET requests because the converter encodes errors by it.
+	req.Header.Set("Content-Type", c.contentType)
+	req.Header.Set("Accept", c.contentType)
+	// Outgoing metadata set by interceptors is sent as headers which the converter reads as incoming metadata.
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for k, vs := range md {
+		key := c.headerPrefix + k
+		for _, h := range c.headers {
+			if strings.EqualFold(h, k) {
+				key = k
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			req.Header.Add(key, v)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	c.setMetadata(resp.Header, opts)
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return allPatternHTTPClientError(resp.StatusCode, contentType, buf)
+	}
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		return proto.Unmarshal(buf, out)
+	default:
+		return protojson.Unmarshal(buf, out)
+	}
+}
+
+// setMetadata sets the metadata read from the response headers to grpc.Header and grpc.Trailer of opts.
+// Values of the keys ending with "-bin" are decoded from base64 as gRPC does. The other options are ignored.
+func (c *AllPatternHTTPClient) setMetadata(header http.Header, opts []grpc.CallOption) {
+	headerMD, trailerMD := metadata.MD{}, metadata.MD{}
+	add := func(md metadata.MD, key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+	hasPrefix := func(key, prefix string) bool {
+		return prefix != "" && len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix)
+	}
+
+	for _, key := range c.headers {
+		add(headerMD, key, header.Values(key))
+	}
+	for key, values := range header {
+		switch {
+		case hasPrefix(key, c.trailerPrefix):
+			add(trailerMD, key[len(c.trailerPrefix):], values)
+		case hasPrefix(key, c.headerPrefix):
+			add(headerMD, key[len(c.headerPrefix):], values)
+		}
+	}
+
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			*o.HeaderAddr = headerMD
+		case grpc.TrailerCallOption:
+			*o.TrailerAddr = trailerMD
+		}
+	}
+}
+
+// marshal marshals in, or the field of in if field is not empty, in the content type of the client.
+// Only message fields can be marshaled in protobuf.
+func (c *AllPatternHTTPClient) marshal(in proto.Message, field protoreflect.Name) ([]byte, error) {
+	protobuf := c.contentType == "application/protobuf" || c.contentType == "application/x-protobuf"
+	if field == "" {
+		if protobuf {
+			return proto.Marshal(in)
+		}
+		return protojson.Marshal(in)
+	}
+
+	m := in.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName(field)
+	if protobuf {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, status.Errorf(codes.Unimplemented, "field %s cannot be sent in %s", field, c.contentType)
+		}
+		return proto.Marshal(m.Get(fd).Message().Interface())
+	}
+	// The JSON of the field is taken from the message which has only the field.
+	only := m.New()
+	if m.Has(fd) {
+		only.Set(fd, m.Get(fd))
+	}
+	buf, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(only.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return nil, err
+	}
+	return fields[fd.JSONName()], nil
+}
+
+// allPatternHTTPClientError converts the error response to the error of grpc/status.
+// If the body is not google.rpc.Status, the code is decided by the HTTP status code as gRPC clients do.
+func allPatternHTTPClientError(code int, contentType string, body []byte) error {
+	p := status.New(codes.Unknown, "").Proto()
+	var err error
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		err = proto.Unmarshal(body, p)
+	case "application/json":
+		err = protojson.Unmarshal(body, p)
+	default:
+		err = fmt.Errorf("unexpected Content-Type: %s", contentType)
+	}
+	if err == nil && codes.Code(p.GetCode()) != codes.OK {
+		return status.ErrorProto(p)
+	}
+
+	c := codes.Unknown
+	switch code {
+	case http.StatusBadRequest:
+		c = codes.Internal
+	case http.StatusUnauthorized:
+		c = codes.Unauthenticated
+	case http.StatusForbidden:
+		c = codes.PermissionDenied
+	case http.StatusNotFound:
+		c = codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		c = codes.Unavailable
+	}
+	return status.Errorf(c, "unexpected HTTP status code %d: %s", code, body)
+}
+
+// file_httprule_all_pattern_proto_incomingMetadata returns the metadata built from the headers listed in headers and the headers with prefix.
+// The empty prefix disables it. Values of the keys ending with "-bin" are decoded from base64 as gRPC does.
+func file_httprule_all_pattern_proto_incomingMetadata(header http.Header, headers []string, prefix string) metadata.MD {
+	md := metadata.MD{}
+	add := func(key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+
+	for _, key := range headers {
+		add(key, header.Values(key))
+	}
+	if prefix != "" {
+		for key, values := range header {
+			if len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+				add(key[len(prefix):], values)
+			}
+		}
+	}
+	return md
+}
+
+// file_httprule_all_pattern_proto_httpTransportStream implements grpc.ServerTransportStream to write header and trailer metadata set by the RPC
+// as HTTP headers, so that grpc.SetHeader, grpc.SendHeader and grpc.SetTrailer work in the RPC.
+type file_httprule_all_pattern_proto_httpTransportStream struct {
+	method        string
+	w             http.ResponseWriter
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+
+	mu         sync.Mutex
+	header     metadata.MD
+	trailer    metadata.MD
+	headerSent bool
+}
+
+func (s *file_httprule_all_pattern_proto_httpTransportStream) Method() string {
+	return s.method
+}
+
+// SetHeader sets the header metadata written before the response body.
+func (s *file_httprule_all_pattern_proto_httpTransportStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader sets the header metadata and writes it to the response headers.
+// The headers are sent to the client with the response body.
+func (s *file_httprule_all_pattern_proto_httpTransportStream) SendHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	s.writeHeaderLocked()
+	return nil
+}
+
+// SetTrailer sets the trailer metadata written after the RPC returns.
+func (s *file_httprule_all_pattern_proto_httpTransportStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// writeHeader writes the header metadata to the response headers unless it has been written.
+func (s *file_httprule_all_pattern_proto_httpTransportStream) writeHeader() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeHeaderLocked()
+}
+
+func (s *file_httprule_all_pattern_proto_httpTransportStream) writeHeaderLocked() {
+	if s.headerSent {
+		return
+	}
+	s.headerSent = true
+	s.add(s.headerPrefix, s.header, s.headers)
+}
+
+// writeTrailer writes the trailer metadata as HTTP trailers if asTrailer is true.
+// Otherwise, it is written to the response headers because the response body has not been written yet.
+func (s *file_httprule_all_pattern_proto_httpTransportStream) writeTrailer(asTrailer bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if asTrailer {
+		s.add(http.TrailerPrefix+s.trailerPrefix, s.trailer, nil)
+		return
+	}
+	s.add(s.trailerPrefix, s.trailer, nil)
+}
+
+// writeStatusCode writes the status code set by the header metadata "x-http-code" and reports whether
+// the response body is allowed. The status code is ignored unless it is between 200 and 599.
+func (s *file_httprule_all_pattern_proto_httpTransportStream) writeStatusCode() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vs := s.header.Get("x-http-code")
+	if len(vs) == 0 {
+		return true
+	}
+	code, err := strconv.Atoi(vs[len(vs)-1])
+	if err != nil || code < 200 || code > 599 {
+		return true
+	}
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		s.w.Header().Del("Content-Type")
+		s.w.Header().Del("Content-Encoding")
+		s.w.WriteHeader(code)
+		return false
+	}
+	s.w.WriteHeader(code)
+	return true
+}
+
+// add adds md to the response headers with the prefix. The keys listed in headers are added without the prefix.
+// Values of the keys ending with "-bin" are encoded in base64 as gRPC does.
+func (s *file_httprule_all_pattern_proto_httpTransportStream) add(prefix string, md metadata.MD, headers []string) {
+	for k, vs := range md {
+		if k == "x-http-code" {
+			continue
+		}
+		name := prefix + k
+		for _, header := range headers {
+			if strings.EqualFold(header, k) {
+				name = header
+				break
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			s.w.Header().Add(name, v)
+		}
+	}
+}
+
+// file_httprule_all_pattern_proto_timeout returns the timeout of the RPC set by Grpc-Timeout header or the header named name,
+// whose value is parsed by time.ParseDuration. The shorter one is used if both are set. Invalid values are ignored.
+func file_httprule_all_pattern_proto_timeout(header http.Header, name string) (time.Duration, bool) {
+	timeout, ok := file_httprule_all_pattern_proto_parseGRPCTimeout(header.Get("Grpc-Timeout"))
+	if name == "" {
+		return timeout, ok
+	}
+	v := header.Get(name)
+	if v == "" {
+		return timeout, ok
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return timeout, ok
+	}
+	if !ok || d < timeout {
+		return d, true
+	}
+	return timeout, true
+}
+
+// file_httprule_all_pattern_proto_parseGRPCTimeout parses v in the format of Grpc-Timeout header, that is at most 8 digits followed by the unit,
+// H (hours), M (minutes), S (seconds), m (milliseconds), u (microseconds) or n (nanoseconds).
+func file_httprule_all_pattern_proto_parseGRPCTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 || len(v) > 9 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch v[len(v)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	n, err := strconv.ParseUint(v[:len(v)-1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	if time.Duration(n) > math.MaxInt64/unit {
+		return math.MaxInt64, true
+	}
+	return time.Duration(n) * unit, true
+}
+
+// file_httprule_all_pattern_proto_requestPeer returns the peer of the RPC built from the remote address and the TLS connection state of r.
+// It returns nil if the remote address is not an IP address and port.
+func file_httprule_all_pattern_proto_requestPeer(r *http.Request) *peer.Peer {
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return nil
+	}
+	p := &peer.Peer{
+		Addr: &net.TCPAddr{IP: ip, Port: n},
+	}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{
+			State: *r.TLS,
+			CommonAuthInfo: credentials.CommonAuthInfo{
+				SecurityLevel: credentials.PrivacyAndIntegrity,
+			},
+		}
+	}
+	return p
+}
+
+// file_httprule_all_pattern_proto_httpRequestBody counts the bytes read from the request body.
+type file_httprule_all_pattern_proto_httpRequestBody struct {
+	io.ReadCloser
+	size int64
+}
+
+func (b *file_httprule_all_pattern_proto_httpRequestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+// file_httprule_all_pattern_proto_httpResponseWriter records the status code and the size of the response written to http.ResponseWriter.
+type file_httprule_all_pattern_proto_httpResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int64
+}
+
+func (w *file_httprule_all_pattern_proto_httpResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *file_httprule_all_pattern_proto_httpResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher to flush streaming responses.
+func (w *file_httprule_all_pattern_proto_httpResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter for http.ResponseController.
+func (w *file_httprule_all_pattern_proto_httpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// status returns the status code of the response. It is 200 if nothing has been written as net/http does.
+func (w *file_httprule_all_pattern_proto_httpResponseWriter) status() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+	return w.statusCode
+}
+
+// file_httprule_all_pattern_proto_redact returns m marshaled in JSON without the fields marked with debug_redact option.
+func file_httprule_all_pattern_proto_redact(m proto.Message) string {
+	buf, err := protojson.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(buf)
+}
+
+// file_httprule_all_pattern_proto_decodeBody replaces the body of r with the reader decoding encoding, which is gzip, x-gzip or deflate.
+// A malformed body fails with codes.InvalidArgument.
+func file_httprule_all_pattern_proto_decodeBody(r *http.Request, encoding string) error {
+	var zr io.ReadCloser
+	var err error
+	if encoding == "deflate" {
+		zr, err = zlib.NewReader(r.Body)
+	} else {
+		zr, err = gzip.NewReader(r.Body)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", encoding, err)
+	}
+	r.Body = &file_httprule_all_pattern_proto_decodedBody{ReadCloser: zr, encoding: encoding}
+	return nil
+}
+
+// file_httprule_all_pattern_proto_decodedBody is the request body decoded by Content-Encoding header.
+// Reading it fails with codes.InvalidArgument if the rest of the body turns out to be malformed.
+type file_httprule_all_pattern_proto_decodedBody struct {
+	io.ReadCloser
+	encoding string
+}
+
+func (b *file_httprule_all_pattern_proto_decodedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", b.encoding, err)
+	}
+	return n, err
+}
+
+// file_httprule_all_pattern_proto_compress returns buf compressed by the content coding negotiated by Accept-Encoding header of r
+// if buf is at least minSize bytes, or identity is not accepted. A negative minSize disables the compression.
+// Otherwise, it returns buf as is.
+func file_httprule_all_pattern_proto_compress(w http.ResponseWriter, r *http.Request, buf []byte, minSize int) []byte {
+	if minSize < 0 {
+		return buf
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	encoding, identity := file_httprule_all_pattern_proto_acceptEncoding(r)
+	if identity && len(buf) < minSize {
+		return buf
+	}
+	var b bytes.Buffer
+	var zw io.WriteCloser
+	switch encoding {
+	case "gzip":
+		zw = gzip.NewWriter(&b)
+	case "deflate":
+		zw = zlib.NewWriter(&b)
+	default:
+		return buf
+	}
+	if _, err := zw.Write(buf); err != nil {
+		return buf
+	}
+	if err := zw.Close(); err != nil {
+		return buf
+	}
+	w.Header().Set("Content-Encoding", encoding)
+	return b.Bytes()
+}
+
+// file_httprule_all_pattern_proto_acceptEncoding returns gzip or deflate accepted by Accept-Encoding header of r. gzip is preferred to deflate.
+// It returns the empty string if neither is accepted. Codings with q=0 are not accepted, and * applies to the codings
+// not listed in the header. identity reports whether the response may be sent without any coding.
+func file_httprule_all_pattern_proto_acceptEncoding(r *http.Request) (encoding string, identity bool) {
+	qs := make(map[string]float64)
+	for _, v := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(v, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		if coding == "x-gzip" {
+			coding = "gzip"
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		qs[coding] = q
+	}
+	accepted := func(coding string) bool {
+		if q, ok := qs[coding]; ok {
+			return q > 0
+		}
+		if q, ok := qs["*"]; ok {
+			return q > 0
+		}
+		// identity is acceptable unless it is excluded explicitly.
+		return coding == "identity"
+	}
+
+	switch {
+	case accepted("gzip"):
+		encoding = "gzip"
+	case accepted("deflate"):
+		encoding = "deflate"
+	}
+	return encoding, accepted("identity")
+}
+
+//go:embed all_pattern.openapi.json
+var file_httprule_all_pattern_proto_openAPI []byte
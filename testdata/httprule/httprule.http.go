@@ -5,48 +5,366 @@ package httprulepb
 
 import (
 	bytes "bytes"
+	gzip "compress/gzip"
+	zlib "compress/zlib"
 	context "context"
+	_ "embed"
+	base64 "encoding/base64"
+	json "encoding/json"
+	errors "errors"
 	fmt "fmt"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
+	credentials "google.golang.org/grpc/credentials"
+	metadata "google.golang.org/grpc/metadata"
+	peer "google.golang.org/grpc/peer"
 	status "google.golang.org/grpc/status"
 	protojson "google.golang.org/protobuf/encoding/protojson"
+	protowire "google.golang.org/protobuf/encoding/protowire"
 	proto "google.golang.org/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	io "io"
 	ioutil "io/ioutil"
+	slog "log/slog"
+	math "math"
 	mime "mime"
+	net "net"
 	http "net/http"
-	reflect "reflect"
+	url "net/url"
+	debug "runtime/debug"
 	strconv "strconv"
 	strings "strings"
+	sync "sync"
+	time "time"
 )
 
 // MessagingHTTPService is the server API for Messaging service.
 type MessagingHTTPService interface {
 	GetMessage(context.Context, *GetMessageRequest) (*Message, error)
 	UpdateMessage(context.Context, *UpdateMessageRequest) (*Message, error)
+	PatchMessage(context.Context, *UpdateMessageRequest) (*Message, error)
+	CancelMessage(context.Context, *GetMessageRequest) (*Message, error)
 	SubFieldMessage(context.Context, *SubFieldMessageRequest) (*Message, error)
 }
 
+// MessagingHTTPTracer is called at the start and the finish of every request handled by MessagingHTTPConverter,
+// e.g. to start and end a span of a tracing library without depending on it.
+type MessagingHTTPTracer interface {
+	// OnStart is called when the handler of method starts handling r. route is the path template of google.api.http option,
+	// or empty if the handler is not returned by the HTTPRule method. The returned context is passed to the RPC and OnFinish.
+	OnStart(ctx context.Context, method protoreflect.MethodDescriptor, route string, r *http.Request) context.Context
+	// OnFinish is called after the callback with the arguments passed to the callback, the status code of the response
+	// and the time elapsed since OnStart. The gRPC code of the RPC is status.Code(err).
+	OnFinish(ctx context.Context, method protoreflect.MethodDescriptor, arg, ret proto.Message, statusCode int, err error, elapsed time.Duration)
+}
+
+// MessagingHTTPObserver observes the metrics of every request handled by MessagingHTTPConverter,
+// e.g. to export the latency, the sizes and the status of RPCs to a metrics library without depending on it.
+type MessagingHTTPObserver interface {
+	// Observe is called after the callback with the method, the path template of google.api.http option
+	// (empty if the handler is not returned by the HTTPRule method), the status code of the response,
+	// the gRPC code of the RPC, the bytes read from the request body and written to the response body, and the latency.
+	Observe(ctx context.Context, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, requestSize, responseSize int64, elapsed time.Duration)
+}
+
 // MessagingHTTPConverter has a function to convert MessagingHTTPService interface to http.HandlerFunc.
 type MessagingHTTPConverter struct {
-	srv MessagingHTTPService
+	srv                   MessagingHTTPService
+	cb                    func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)
+	interceptors          []grpc.UnaryServerInterceptor
+	maxBodySize           int64
+	skipMethodCheck       bool
+	incomingHeaders       []string
+	incomingHeaderPrefix  string
+	outgoingHeaders       []string
+	outgoingHeaderPrefix  string
+	outgoingTrailerPrefix string
+	timeoutHeader         string
+	tracer                MessagingHTTPTracer
+	observer              MessagingHTTPObserver
+	accessLogger          *slog.Logger
+	accessLogPayloads     bool
+	recovery              bool
+	panicHandler          func(ctx context.Context, fullMethod string, p interface{}, stack []byte)
+	compressionMinSize    int
+}
+
+// MessagingHTTPConverterOption configures MessagingHTTPConverter.
+type MessagingHTTPConverterOption func(*MessagingHTTPConverter)
+
+// WithMessagingHTTPCallback sets the callback used when nil is passed to a convert method.
+func WithMessagingHTTPCallback(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.cb = cb
+	}
+}
+
+// WithMessagingHTTPInterceptors appends interceptors executed before the interceptors passed to a convert method.
+func WithMessagingHTTPInterceptors(interceptors ...grpc.UnaryServerInterceptor) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.interceptors = append(h.interceptors, interceptors...)
+	}
+}
+
+// WithMessagingHTTPMaxBodySize sets the maximum size of request bodies in bytes.
+// The default is 4194304 bytes set by max_body_size option of protoc-gen-gohttp. Zero or a negative value means no limit.
+// Requests with larger bodies fail with *http.MaxBytesError, and the default callback responds 413 Request Entity Too Large.
+func WithMessagingHTTPMaxBodySize(n int64) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.maxBodySize = n
+	}
+}
+
+// WithMessagingHTTPSkipMethodCheck sets whether the handlers returned by HTTPRule methods skip checking the request method.
+// By default, the handlers respond 405 Method Not Allowed with Allow header if the request method does not match google.api.http option.
+// Routers that already route requests by the method can skip the check.
+func WithMessagingHTTPSkipMethodCheck(skip bool) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.skipMethodCheck = skip
+	}
+}
+
+// WithMessagingHTTPIncomingHeaders sets the request headers passed to the RPC as incoming metadata.
+// The default is Authorization. The metadata keys are the lowercase header names.
+func WithMessagingHTTPIncomingHeaders(headers ...string) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.incomingHeaders = headers
+	}
+}
+
+// WithMessagingHTTPIncomingHeaderPrefix sets the prefix of the request headers passed to the RPC as incoming metadata.
+// The metadata keys are the lowercase header names without the prefix. The default is "Grpc-Metadata-",
+// and the empty prefix disables it.
+func WithMessagingHTTPIncomingHeaderPrefix(prefix string) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.incomingHeaderPrefix = prefix
+	}
+}
+
+// WithMessagingHTTPOutgoingHeaders sets the response headers written from the header metadata with the same
+// lowercase names, e.g. "location" for Location. They are written without the prefix. The default is Location.
+func WithMessagingHTTPOutgoingHeaders(headers ...string) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.outgoingHeaders = headers
+	}
+}
+
+// WithMessagingHTTPOutgoingHeaderPrefix sets the prefix of the response headers written from the header metadata
+// set by grpc.SetHeader or grpc.SendHeader. The default is "Grpc-Metadata-".
+func WithMessagingHTTPOutgoingHeaderPrefix(prefix string) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.outgoingHeaderPrefix = prefix
+	}
+}
+
+// WithMessagingHTTPOutgoingTrailerPrefix sets the prefix of the response headers or trailers written from the trailer metadata
+// set by grpc.SetTrailer. The default is "Grpc-Trailer-".
+func WithMessagingHTTPOutgoingTrailerPrefix(prefix string) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.outgoingTrailerPrefix = prefix
+	}
+}
+
+// WithMessagingHTTPTimeoutHeader sets the request header of the timeout of the RPC in addition to Grpc-Timeout header.
+// The value is parsed by time.ParseDuration, e.g. "1.5s". The empty name disables it, which is the default.
+func WithMessagingHTTPTimeoutHeader(name string) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.timeoutHeader = name
+	}
+}
+
+// WithMessagingHTTPTracer sets the tracer called at the start and the finish of every request.
+func WithMessagingHTTPTracer(tracer MessagingHTTPTracer) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.tracer = tracer
+	}
+}
+
+// WithMessagingHTTPObserver sets the observer called at the finish of every request.
+func WithMessagingHTTPObserver(observer MessagingHTTPObserver) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.observer = observer
+	}
+}
+
+// WithMessagingHTTPAccessLog sets the logger emitting an access log record of every request with the full method,
+// the HTTP method, the route, the status code, the gRPC code and the latency. Requests responded with 5xx are logged
+// at error level, and the others are logged at info level. The access log is disabled by default.
+func WithMessagingHTTPAccessLog(logger *slog.Logger) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.accessLogger = logger
+	}
+}
+
+// WithMessagingHTTPAccessLogPayloads sets whether the access log contains the request and response messages in JSON.
+// Fields marked with debug_redact option are removed from the messages.
+func WithMessagingHTTPAccessLogPayloads(payloads bool) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.accessLogPayloads = payloads
+	}
+}
+
+// WithMessagingHTTPRecovery enables the recovery of panics in the RPC and the interceptors. The panic is converted to
+// *MessagingHTTPPanicError of codes.Internal passed to the callback, and reported to handler with the stack trace
+// unless handler is nil. Panics are not recovered by default.
+func WithMessagingHTTPRecovery(handler func(ctx context.Context, fullMethod string, p interface{}, stack []byte)) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.recovery = true
+		h.panicHandler = handler
+	}
+}
+
+// WithMessagingHTTPCompression enables the compression of response bodies of unary and client-side streaming RPCs
+// with gzip or deflate negotiated by Accept-Encoding header. Only bodies of at least minSize bytes are compressed.
+// A negative value disables it, which is the default.
+func WithMessagingHTTPCompression(minSize int) MessagingHTTPConverterOption {
+	return func(h *MessagingHTTPConverter) {
+		h.compressionMinSize = minSize
+	}
 }
 
 // NewMessagingHTTPConverter returns MessagingHTTPConverter.
-func NewMessagingHTTPConverter(srv MessagingHTTPService) *MessagingHTTPConverter {
-	return &MessagingHTTPConverter{
-		srv: srv,
+func NewMessagingHTTPConverter(srv MessagingHTTPService, opts ...MessagingHTTPConverterOption) *MessagingHTTPConverter {
+	h := &MessagingHTTPConverter{
+		srv:                   srv,
+		maxBodySize:           4194304,
+		incomingHeaders:       []string{"Authorization"},
+		incomingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingHeaders:       []string{"Location"},
+		outgoingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingTrailerPrefix: "Grpc-Trailer-",
+		compressionMinSize:    -1,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// trace calls OnStart of the tracer, and returns the context returned by OnStart, w recording the response
+// and cb calling OnFinish of the tracer, Observe of the observer and the access logger after cb.
+// The body of r is replaced to count the bytes read.
+func (h *MessagingHTTPConverter) trace(ctx context.Context, w http.ResponseWriter, r *http.Request, name protoreflect.Name, route string, cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) (context.Context, http.ResponseWriter, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) {
+	method := File_httprule_httprule_proto.Services().ByName("Messaging").Methods().ByName(name)
+	start := time.Now()
+	if h.tracer != nil {
+		ctx = h.tracer.OnStart(ctx, method, route, r)
+	}
+	rw := &file_httprule_httprule_proto_httpResponseWriter{ResponseWriter: w}
+	body := &file_httprule_httprule_proto_httpRequestBody{ReadCloser: r.Body}
+	r.Body = body
+	return ctx, rw, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+		cb(ctx, w, r, arg, ret, err)
+		elapsed := time.Since(start)
+		if h.tracer != nil {
+			h.tracer.OnFinish(ctx, method, arg, ret, rw.status(), err, elapsed)
+		}
+		code := status.Code(err)
+		switch {
+		case code != codes.Unknown:
+		case errors.Is(err, context.DeadlineExceeded):
+			code = codes.DeadlineExceeded
+		case errors.Is(err, context.Canceled):
+			code = codes.Canceled
+		}
+		if h.observer != nil {
+			h.observer.Observe(ctx, method, route, rw.status(), code, body.size, rw.size, elapsed)
+		}
+		if h.accessLogger != nil {
+			h.logAccess(ctx, r, method, route, rw.status(), code, err, elapsed, arg, ret)
+		}
+	}
+}
+
+// logAccess emits the access log record of the request.
+func (h *MessagingHTTPConverter) logAccess(ctx context.Context, r *http.Request, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, err error, elapsed time.Duration, arg, ret proto.Message) {
+	level := slog.LevelInfo
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	attrs := []slog.Attr{
+		slog.String("method", fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())),
+		slog.String("http_method", r.Method),
+		slog.String("route", route),
+		slog.Int("status", statusCode),
+		slog.String("code", code.String()),
+		slog.Duration("latency", elapsed),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if h.accessLogPayloads {
+		if arg != nil {
+			attrs = append(attrs, slog.String("request", file_httprule_httprule_proto_redact(arg)))
+		}
+		if ret != nil {
+			attrs = append(attrs, slog.String("response", file_httprule_httprule_proto_redact(ret)))
+		}
+	}
+	h.accessLogger.LogAttrs(ctx, level, "access", attrs...)
+}
+
+// MessagingHTTPPanicError is the error of a panic recovered by MessagingHTTPConverter.
+type MessagingHTTPPanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine panicked.
+	Stack []byte
+}
+
+func (e *MessagingHTTPPanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// GRPCStatus returns the status of codes.Internal, so that status.Code returns codes.Internal for the error.
+// The message of the status does not contain the panic value, which must not be sent to clients.
+func (e *MessagingHTTPPanicError) GRPCStatus() *status.Status {
+	return status.New(codes.Internal, "internal error")
+}
+
+// recovered reports the panic p to the panic handler and returns it as *MessagingHTTPPanicError.
+func (h *MessagingHTTPConverter) recovered(ctx context.Context, fullMethod string, p interface{}) error {
+	err := &MessagingHTTPPanicError{Value: p, Stack: debug.Stack()}
+	if h.panicHandler != nil {
+		h.panicHandler(ctx, fullMethod, p, err.Stack)
 	}
+	return err
+}
+
+// recoverUnary is the outermost interceptor recovering panics in the RPC and the interceptors.
+func (h *MessagingHTTPConverter) recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = h.recovered(ctx, info.FullMethod, p)
+		}
+	}()
+	return handler(ctx, req)
 }
 
 // GetMessage returns MessagingHTTPService interface's GetMessage converted to http.HandlerFunc.
 func (h *MessagingHTTPConverter) GetMessage(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -69,8 +387,35 @@ func (h *MessagingHTTPConverter) GetMessage(cb func(ctx context.Context, w http.
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "GetMessage", "", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/GetMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -88,6 +433,22 @@ func (h *MessagingHTTPConverter) GetMessage(cb func(ctx context.Context, w http.
 
 		arg := &GetMessageRequest{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_httprule_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -138,6 +499,8 @@ func (h *MessagingHTTPConverter) GetMessage(cb func(ctx context.Context, w http.
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -156,6 +519,11 @@ func (h *MessagingHTTPConverter) GetMessage(cb func(ctx context.Context, w http.
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -166,6 +534,11 @@ func (h *MessagingHTTPConverter) GetMessage(cb func(ctx context.Context, w http.
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -187,11 +560,28 @@ func (h *MessagingHTTPConverter) GetMessageWithName(cb func(ctx context.Context,
 
 // GetMessageHTTPRule returns HTTP method, path and MessagingHTTPService interface's GetMessage converted to http.HandlerFunc.
 func (h *MessagingHTTPConverter) GetMessageHTTPRule(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -214,8 +604,35 @@ func (h *MessagingHTTPConverter) GetMessageHTTPRule(cb func(ctx context.Context,
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.MethodGet, "/v1/messages/{message_id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "GetMessage", "/v1/messages/{message_id}", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/GetMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -231,8 +648,16 @@ func (h *MessagingHTTPConverter) GetMessageHTTPRule(cb func(ctx context.Context,
 
 		w.Header().Set("Content-Type", accept)
 
+		if !h.skipMethodCheck && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, err := fmt.Fprintf(w, "Method Not Allowed: %s", r.Method)
+			cb(ctx, w, r, nil, nil, err)
+			return
+		}
+
 		arg := &GetMessageRequest{}
-		if r.Method == http.MethodGet {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
 			if v := r.URL.Query().Get("revision"); v != "" {
 				c, err := strconv.ParseInt(v, 10, 64)
 				if err != nil {
@@ -247,7 +672,11 @@ func (h *MessagingHTTPConverter) GetMessageHTTPRule(cb func(ctx context.Context,
 		}
 
 		p := strings.Split(r.URL.Path, "/")
-		arg.MessageId = p[3]
+		if v := r.PathValue("message_id"); v != "" {
+			arg.MessageId = v
+		} else {
+			arg.MessageId = p[3]
+		}
 
 		n := len(interceptors)
 		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -274,6 +703,8 @@ func (h *MessagingHTTPConverter) GetMessageHTTPRule(cb func(ctx context.Context,
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -292,6 +723,11 @@ func (h *MessagingHTTPConverter) GetMessageHTTPRule(cb func(ctx context.Context,
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -302,6 +738,11 @@ func (h *MessagingHTTPConverter) GetMessageHTTPRule(cb func(ctx context.Context,
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -318,11 +759,28 @@ func (h *MessagingHTTPConverter) GetMessageHTTPRule(cb func(ctx context.Context,
 
 // UpdateMessage returns MessagingHTTPService interface's UpdateMessage converted to http.HandlerFunc.
 func (h *MessagingHTTPConverter) UpdateMessage(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -345,8 +803,35 @@ func (h *MessagingHTTPConverter) UpdateMessage(cb func(ctx context.Context, w ht
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "UpdateMessage", "", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/UpdateMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -364,6 +849,22 @@ func (h *MessagingHTTPConverter) UpdateMessage(cb func(ctx context.Context, w ht
 
 		arg := &UpdateMessageRequest{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_httprule_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -414,6 +915,8 @@ func (h *MessagingHTTPConverter) UpdateMessage(cb func(ctx context.Context, w ht
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -432,6 +935,11 @@ func (h *MessagingHTTPConverter) UpdateMessage(cb func(ctx context.Context, w ht
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -442,6 +950,11 @@ func (h *MessagingHTTPConverter) UpdateMessage(cb func(ctx context.Context, w ht
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -463,11 +976,28 @@ func (h *MessagingHTTPConverter) UpdateMessageWithName(cb func(ctx context.Conte
 
 // UpdateMessageHTTPRule returns HTTP method, path and MessagingHTTPService interface's UpdateMessage converted to http.HandlerFunc.
 func (h *MessagingHTTPConverter) UpdateMessageHTTPRule(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -490,8 +1020,35 @@ func (h *MessagingHTTPConverter) UpdateMessageHTTPRule(cb func(ctx context.Conte
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.MethodPut, "/v1/messages/{message_id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "UpdateMessage", "/v1/messages/{message_id}", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/UpdateMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -507,8 +1064,32 @@ func (h *MessagingHTTPConverter) UpdateMessageHTTPRule(cb func(ctx context.Conte
 
 		w.Header().Set("Content-Type", accept)
 
+		if !h.skipMethodCheck && r.Method != http.MethodPut && r.Method != http.MethodPost {
+			w.Header().Set("Allow", "PUT, POST")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, err := fmt.Fprintf(w, "Method Not Allowed: %s", r.Method)
+			cb(ctx, w, r, nil, nil, err)
+			return
+		}
+
 		arg := &UpdateMessageRequest{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_httprule_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -535,7 +1116,11 @@ func (h *MessagingHTTPConverter) UpdateMessageHTTPRule(cb func(ctx context.Conte
 		}
 
 		p := strings.Split(r.URL.Path, "/")
-		arg.MessageId = p[3]
+		if v := r.PathValue("message_id"); v != "" {
+			arg.MessageId = v
+		} else {
+			arg.MessageId = p[3]
+		}
 
 		n := len(interceptors)
 		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -562,6 +1147,8 @@ func (h *MessagingHTTPConverter) UpdateMessageHTTPRule(cb func(ctx context.Conte
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -580,6 +1167,11 @@ func (h *MessagingHTTPConverter) UpdateMessageHTTPRule(cb func(ctx context.Conte
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -590,6 +1182,11 @@ func (h *MessagingHTTPConverter) UpdateMessageHTTPRule(cb func(ctx context.Conte
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -604,13 +1201,30 @@ func (h *MessagingHTTPConverter) UpdateMessageHTTPRule(cb func(ctx context.Conte
 	})
 }
 
-// SubFieldMessage returns MessagingHTTPService interface's SubFieldMessage converted to http.HandlerFunc.
-func (h *MessagingHTTPConverter) SubFieldMessage(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+// PatchMessage returns MessagingHTTPService interface's PatchMessage converted to http.HandlerFunc.
+func (h *MessagingHTTPConverter) PatchMessage(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -633,8 +1247,35 @@ func (h *MessagingHTTPConverter) SubFieldMessage(cb func(ctx context.Context, w
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "PatchMessage", "", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/PatchMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -650,8 +1291,24 @@ func (h *MessagingHTTPConverter) SubFieldMessage(cb func(ctx context.Context, w
 
 		w.Header().Set("Content-Type", accept)
 
-		arg := &SubFieldMessageRequest{}
+		arg := &UpdateMessageRequest{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_httprule_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -694,14 +1351,16 @@ func (h *MessagingHTTPConverter) SubFieldMessage(cb func(ctx context.Context, w
 
 		info := &grpc.UnaryServerInfo{
 			Server:     h.srv,
-			FullMethod: "/httprule.Messaging/SubFieldMessage",
+			FullMethod: "/httprule.Messaging/PatchMessage",
 		}
 
 		handler := func(c context.Context, req interface{}) (interface{}, error) {
-			return h.srv.SubFieldMessage(c, req.(*SubFieldMessageRequest))
+			return h.srv.PatchMessage(c, req.(*UpdateMessageRequest))
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -709,7 +1368,7 @@ func (h *MessagingHTTPConverter) SubFieldMessage(cb func(ctx context.Context, w
 
 		ret, ok := iret.(*Message)
 		if !ok {
-			cb(ctx, w, r, arg, nil, fmt.Errorf("/httprule.Messaging/SubFieldMessage: interceptors have not return Message"))
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/httprule.Messaging/PatchMessage: interceptors have not return Message"))
 			return
 		}
 
@@ -720,6 +1379,11 @@ func (h *MessagingHTTPConverter) SubFieldMessage(cb func(ctx context.Context, w
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -730,6 +1394,11 @@ func (h *MessagingHTTPConverter) SubFieldMessage(cb func(ctx context.Context, w
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -744,18 +1413,35 @@ func (h *MessagingHTTPConverter) SubFieldMessage(cb func(ctx context.Context, w
 	})
 }
 
-// SubFieldMessageWithName returns Service name, Method name and MessagingHTTPService interface's SubFieldMessage converted to http.HandlerFunc.
-func (h *MessagingHTTPConverter) SubFieldMessageWithName(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
-	return "Messaging", "SubFieldMessage", h.SubFieldMessage(cb, interceptors...)
+// PatchMessageWithName returns Service name, Method name and MessagingHTTPService interface's PatchMessage converted to http.HandlerFunc.
+func (h *MessagingHTTPConverter) PatchMessageWithName(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	return "Messaging", "PatchMessage", h.PatchMessage(cb, interceptors...)
 }
 
-// SubFieldMessageHTTPRule returns HTTP method, path and MessagingHTTPService interface's SubFieldMessage converted to http.HandlerFunc.
-func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+// PatchMessageHTTPRule returns HTTP method, path and MessagingHTTPService interface's PatchMessage converted to http.HandlerFunc.
+func (h *MessagingHTTPConverter) PatchMessageHTTPRule(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -778,8 +1464,35 @@ func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Con
 			}
 		}
 	}
-	return http.MethodPost, "/v1/messages/{message_id}/{sub.subfield}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
+	return http.MethodPatch, "/v1/messages/{message_id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "PatchMessage", "/v1/messages/{message_id}", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/PatchMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -795,8 +1508,32 @@ func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Con
 
 		w.Header().Set("Content-Type", accept)
 
-		arg := &SubFieldMessageRequest{}
+		if !h.skipMethodCheck && r.Method != http.MethodPatch {
+			w.Header().Set("Allow", "PATCH")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, err := fmt.Fprintf(w, "Method Not Allowed: %s", r.Method)
+			cb(ctx, w, r, nil, nil, err)
+			return
+		}
+
+		arg := &UpdateMessageRequest{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_httprule_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -805,11 +1542,15 @@ func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Con
 
 			switch contentType {
 			case "application/protobuf", "application/x-protobuf":
+				// The body is the field message, which is decoded as the field of the request message.
+				body = protowire.AppendBytes(protowire.AppendTag(nil, 2, protowire.BytesType), body)
 				if err := proto.Unmarshal(body, arg); err != nil {
 					cb(ctx, w, r, nil, nil, err)
 					return
 				}
 			case "application/json":
+				// The body is the field message, which is decoded as the field of the request message.
+				body = append(append([]byte(`{"message":`), body...), '}')
 				if err := protojson.Unmarshal(body, arg); err != nil {
 					cb(ctx, w, r, nil, nil, err)
 					return
@@ -823,9 +1564,11 @@ func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Con
 		}
 
 		p := strings.Split(r.URL.Path, "/")
-		arg.MessageId = p[3]
-		reflect.ValueOf(&arg.Sub).Elem().Set(reflect.ValueOf(reflect.New(reflect.TypeOf(arg.Sub).Elem()).Interface()))
-		arg.Sub.Subfield = p[4]
+		if v := r.PathValue("message_id"); v != "" {
+			arg.MessageId = v
+		} else {
+			arg.MessageId = p[3]
+		}
 
 		n := len(interceptors)
 		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -844,14 +1587,16 @@ func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Con
 
 		info := &grpc.UnaryServerInfo{
 			Server:     h.srv,
-			FullMethod: "/httprule.Messaging/SubFieldMessage",
+			FullMethod: "/httprule.Messaging/PatchMessage",
 		}
 
 		handler := func(c context.Context, req interface{}) (interface{}, error) {
-			return h.srv.SubFieldMessage(c, req.(*SubFieldMessageRequest))
+			return h.srv.PatchMessage(c, req.(*UpdateMessageRequest))
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -859,7 +1604,7 @@ func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Con
 
 		ret, ok := iret.(*Message)
 		if !ok {
-			cb(ctx, w, r, arg, nil, fmt.Errorf("/httprule.Messaging/SubFieldMessage: interceptors have not return Message"))
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/httprule.Messaging/PatchMessage: interceptors have not return Message"))
 			return
 		}
 
@@ -870,6 +1615,11 @@ func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Con
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -880,6 +1630,11 @@ func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Con
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -893,3 +1648,1907 @@ func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Con
 		cb(ctx, w, r, arg, ret, nil)
 	})
 }
+
+// CancelMessage returns MessagingHTTPService interface's CancelMessage converted to http.HandlerFunc.
+func (h *MessagingHTTPConverter) CancelMessage(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
+	if cb == nil {
+		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			if err != nil {
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
+				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
+				case "application/protobuf", "application/x-protobuf":
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				case "application/json":
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				default:
+				}
+			}
+		}
+	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "CancelMessage", "", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/CancelMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		accepts := strings.Split(r.Header.Get("Accept"), ",")
+		accept := accepts[0]
+		if accept == "*/*" || accept == "" {
+			if contentType != "" {
+				accept = contentType
+			} else {
+				accept = "application/json"
+			}
+		}
+
+		w.Header().Set("Content-Type", accept)
+
+		arg := &GetMessageRequest{}
+		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_httprule_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+
+			switch contentType {
+			case "application/protobuf", "application/x-protobuf":
+				if err := proto.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			case "application/json":
+				if err := protojson.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Type: %s", contentType)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+		}
+
+		n := len(interceptors)
+		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			chainer := func(currentInter grpc.UnaryServerInterceptor, currentHandler grpc.UnaryHandler) grpc.UnaryHandler {
+				return func(currentCtx context.Context, currentReq interface{}) (interface{}, error) {
+					return currentInter(currentCtx, currentReq, info, currentHandler)
+				}
+			}
+
+			chainedHandler := handler
+			for i := n - 1; i >= 0; i-- {
+				chainedHandler = chainer(interceptors[i], chainedHandler)
+			}
+			return chainedHandler(ctx, arg)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     h.srv,
+			FullMethod: "/httprule.Messaging/CancelMessage",
+		}
+
+		handler := func(c context.Context, req interface{}) (interface{}, error) {
+			return h.srv.CancelMessage(c, req.(*GetMessageRequest))
+		}
+
+		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
+		if err != nil {
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+
+		ret, ok := iret.(*Message)
+		if !ok {
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/httprule.Messaging/CancelMessage: interceptors have not return Message"))
+			return
+		}
+
+		switch accept {
+		case "application/protobuf", "application/x-protobuf":
+			buf, err := proto.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		case "application/json":
+			buf, err := protojson.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err := fmt.Fprintf(w, "Unsupported Accept: %s", accept)
+			cb(ctx, w, r, arg, ret, err)
+			return
+		}
+		cb(ctx, w, r, arg, ret, nil)
+	})
+}
+
+// CancelMessageWithName returns Service name, Method name and MessagingHTTPService interface's CancelMessage converted to http.HandlerFunc.
+func (h *MessagingHTTPConverter) CancelMessageWithName(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	return "Messaging", "CancelMessage", h.CancelMessage(cb, interceptors...)
+}
+
+// CancelMessageHTTPRule returns HTTP method, path and MessagingHTTPService interface's CancelMessage converted to http.HandlerFunc.
+func (h *MessagingHTTPConverter) CancelMessageHTTPRule(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	if cb == nil {
+		cb = h.cb
+	}
+	if cb == nil {
+		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			if err != nil {
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
+				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
+				case "application/protobuf", "application/x-protobuf":
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				case "application/json":
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				default:
+				}
+			}
+		}
+	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
+	return http.MethodPost, "/v1/messages/{message_id}:cancel", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "CancelMessage", "/v1/messages/{message_id}:cancel", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/CancelMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		accepts := strings.Split(r.Header.Get("Accept"), ",")
+		accept := accepts[0]
+		if accept == "*/*" || accept == "" {
+			if contentType != "" {
+				accept = contentType
+			} else {
+				accept = "application/json"
+			}
+		}
+
+		w.Header().Set("Content-Type", accept)
+
+		if !h.skipMethodCheck && r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, err := fmt.Fprintf(w, "Method Not Allowed: %s", r.Method)
+			cb(ctx, w, r, nil, nil, err)
+			return
+		}
+
+		arg := &GetMessageRequest{}
+		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_httprule_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+
+			switch contentType {
+			case "application/protobuf", "application/x-protobuf":
+				if err := proto.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			case "application/json":
+				if err := protojson.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Type: %s", contentType)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+		}
+
+		p := strings.Split(strings.TrimSuffix(r.URL.Path, ":cancel"), "/")
+		if v := r.PathValue("message_id"); v != "" {
+			arg.MessageId = v
+		} else {
+			arg.MessageId = p[3]
+		}
+
+		n := len(interceptors)
+		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			chainer := func(currentInter grpc.UnaryServerInterceptor, currentHandler grpc.UnaryHandler) grpc.UnaryHandler {
+				return func(currentCtx context.Context, currentReq interface{}) (interface{}, error) {
+					return currentInter(currentCtx, currentReq, info, currentHandler)
+				}
+			}
+
+			chainedHandler := handler
+			for i := n - 1; i >= 0; i-- {
+				chainedHandler = chainer(interceptors[i], chainedHandler)
+			}
+			return chainedHandler(ctx, arg)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     h.srv,
+			FullMethod: "/httprule.Messaging/CancelMessage",
+		}
+
+		handler := func(c context.Context, req interface{}) (interface{}, error) {
+			return h.srv.CancelMessage(c, req.(*GetMessageRequest))
+		}
+
+		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
+		if err != nil {
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+
+		ret, ok := iret.(*Message)
+		if !ok {
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/httprule.Messaging/CancelMessage: interceptors have not return Message"))
+			return
+		}
+
+		switch accept {
+		case "application/protobuf", "application/x-protobuf":
+			buf, err := proto.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		case "application/json":
+			buf, err := protojson.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err := fmt.Fprintf(w, "Unsupported Accept: %s", accept)
+			cb(ctx, w, r, arg, ret, err)
+			return
+		}
+		cb(ctx, w, r, arg, ret, nil)
+	})
+}
+
+// SubFieldMessage returns MessagingHTTPService interface's SubFieldMessage converted to http.HandlerFunc.
+func (h *MessagingHTTPConverter) SubFieldMessage(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
+	if cb == nil {
+		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			if err != nil {
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
+				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
+				case "application/protobuf", "application/x-protobuf":
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				case "application/json":
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				default:
+				}
+			}
+		}
+	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "SubFieldMessage", "", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/SubFieldMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		accepts := strings.Split(r.Header.Get("Accept"), ",")
+		accept := accepts[0]
+		if accept == "*/*" || accept == "" {
+			if contentType != "" {
+				accept = contentType
+			} else {
+				accept = "application/json"
+			}
+		}
+
+		w.Header().Set("Content-Type", accept)
+
+		arg := &SubFieldMessageRequest{}
+		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_httprule_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+
+			switch contentType {
+			case "application/protobuf", "application/x-protobuf":
+				if err := proto.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			case "application/json":
+				if err := protojson.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Type: %s", contentType)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+		}
+
+		n := len(interceptors)
+		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			chainer := func(currentInter grpc.UnaryServerInterceptor, currentHandler grpc.UnaryHandler) grpc.UnaryHandler {
+				return func(currentCtx context.Context, currentReq interface{}) (interface{}, error) {
+					return currentInter(currentCtx, currentReq, info, currentHandler)
+				}
+			}
+
+			chainedHandler := handler
+			for i := n - 1; i >= 0; i-- {
+				chainedHandler = chainer(interceptors[i], chainedHandler)
+			}
+			return chainedHandler(ctx, arg)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     h.srv,
+			FullMethod: "/httprule.Messaging/SubFieldMessage",
+		}
+
+		handler := func(c context.Context, req interface{}) (interface{}, error) {
+			return h.srv.SubFieldMessage(c, req.(*SubFieldMessageRequest))
+		}
+
+		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
+		if err != nil {
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+
+		ret, ok := iret.(*Message)
+		if !ok {
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/httprule.Messaging/SubFieldMessage: interceptors have not return Message"))
+			return
+		}
+
+		switch accept {
+		case "application/protobuf", "application/x-protobuf":
+			buf, err := proto.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		case "application/json":
+			buf, err := protojson.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err := fmt.Fprintf(w, "Unsupported Accept: %s", accept)
+			cb(ctx, w, r, arg, ret, err)
+			return
+		}
+		cb(ctx, w, r, arg, ret, nil)
+	})
+}
+
+// SubFieldMessageWithName returns Service name, Method name and MessagingHTTPService interface's SubFieldMessage converted to http.HandlerFunc.
+func (h *MessagingHTTPConverter) SubFieldMessageWithName(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	return "Messaging", "SubFieldMessage", h.SubFieldMessage(cb, interceptors...)
+}
+
+// SubFieldMessageHTTPRule returns HTTP method, path and MessagingHTTPService interface's SubFieldMessage converted to http.HandlerFunc.
+func (h *MessagingHTTPConverter) SubFieldMessageHTTPRule(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
+	if cb == nil {
+		cb = h.cb
+	}
+	if cb == nil {
+		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+			if err != nil {
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
+				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
+				case "application/protobuf", "application/x-protobuf":
+					buf, err := proto.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				case "application/json":
+					buf, err := protojson.Marshal(p)
+					if err != nil {
+						return
+					}
+					if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+						return
+					}
+				default:
+				}
+			}
+		}
+	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
+	return http.MethodPost, "/v1/messages/{message_id}/{sub.subfield}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if md := file_httprule_httprule_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_httprule_httprule_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_httprule_httprule_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "SubFieldMessage", "/v1/messages/{message_id}/{sub.subfield}", cb)
+		}
+		ts := &file_httprule_httprule_proto_httpTransportStream{
+			method:        "/httprule.Messaging/SubFieldMessage",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
+
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		accepts := strings.Split(r.Header.Get("Accept"), ",")
+		accept := accepts[0]
+		if accept == "*/*" || accept == "" {
+			if contentType != "" {
+				accept = contentType
+			} else {
+				accept = "application/json"
+			}
+		}
+
+		w.Header().Set("Content-Type", accept)
+
+		if !h.skipMethodCheck && r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, err := fmt.Fprintf(w, "Method Not Allowed: %s", r.Method)
+			cb(ctx, w, r, nil, nil, err)
+			return
+		}
+
+		arg := &SubFieldMessageRequest{}
+		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_httprule_httprule_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+
+			switch contentType {
+			case "application/protobuf", "application/x-protobuf":
+				if err := proto.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			case "application/json":
+				if err := protojson.Unmarshal(body, arg); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Type: %s", contentType)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+		}
+
+		p := strings.Split(r.URL.Path, "/")
+		if v := r.PathValue("message_id"); v != "" {
+			arg.MessageId = v
+		} else {
+			arg.MessageId = p[3]
+		}
+		if arg.Sub == nil {
+			arg.Sub = &SubFieldMessageRequest_SubMessage{}
+		}
+		if v := r.PathValue("sub_subfield"); v != "" {
+			arg.Sub.Subfield = v
+		} else {
+			arg.Sub.Subfield = p[4]
+		}
+
+		n := len(interceptors)
+		chained := func(ctx context.Context, arg interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			chainer := func(currentInter grpc.UnaryServerInterceptor, currentHandler grpc.UnaryHandler) grpc.UnaryHandler {
+				return func(currentCtx context.Context, currentReq interface{}) (interface{}, error) {
+					return currentInter(currentCtx, currentReq, info, currentHandler)
+				}
+			}
+
+			chainedHandler := handler
+			for i := n - 1; i >= 0; i-- {
+				chainedHandler = chainer(interceptors[i], chainedHandler)
+			}
+			return chainedHandler(ctx, arg)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     h.srv,
+			FullMethod: "/httprule.Messaging/SubFieldMessage",
+		}
+
+		handler := func(c context.Context, req interface{}) (interface{}, error) {
+			return h.srv.SubFieldMessage(c, req.(*SubFieldMessageRequest))
+		}
+
+		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
+		if err != nil {
+			cb(ctx, w, r, arg, nil, err)
+			return
+		}
+
+		ret, ok := iret.(*Message)
+		if !ok {
+			cb(ctx, w, r, arg, nil, fmt.Errorf("/httprule.Messaging/SubFieldMessage: interceptors have not return Message"))
+			return
+		}
+
+		switch accept {
+		case "application/protobuf", "application/x-protobuf":
+			buf, err := proto.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		case "application/json":
+			buf, err := protojson.Marshal(ret)
+			if err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+			buf = file_httprule_httprule_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
+			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
+				cb(ctx, w, r, arg, ret, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err := fmt.Fprintf(w, "Unsupported Accept: %s", accept)
+			cb(ctx, w, r, arg, ret, err)
+			return
+		}
+		cb(ctx, w, r, arg, ret, nil)
+	})
+}
+
+// RegisterMessagingHTTPHandlers registers all methods of MessagingHTTPService on mux.
+// Methods with google.api.http option are registered with its HTTP method and path,
+// other methods are registered with POST /{package}.{Service}/{Method}.
+func RegisterMessagingHTTPHandlers(mux *http.ServeMux, conv *MessagingHTTPConverter) {
+	var hf http.HandlerFunc
+	_, _, hf = conv.GetMessageHTTPRule(nil)
+	mux.Handle("GET /v1/messages/{message_id}", hf)
+	_, _, hf = conv.UpdateMessageHTTPRule(nil)
+	mux.Handle("PUT /v1/messages/{message_id}", hf)
+	mux.Handle("POST /v1/messages/{message_id}", hf)
+	_, _, hf = conv.PatchMessageHTTPRule(nil)
+	mux.Handle("PATCH /v1/messages/{message_id}", hf)
+	// CancelMessage is not registered: verb after variable is not supported: /v1/messages/{message_id}:cancel
+	_, _, hf = conv.SubFieldMessageHTTPRule(nil)
+	mux.Handle("POST /v1/messages/{message_id}/{sub_subfield}", hf)
+}
+
+// MessagingHTTPRoute is a route of MessagingHTTPService method.
+type MessagingHTTPRoute struct {
+	// Method is HTTP method of the route.
+	Method string
+	// Pattern is path template of google.api.http option, or /{package}.{Service}/{Method} if the option is not defined.
+	Pattern string
+	// Handler is MessagingHTTPService method converted to http.HandlerFunc.
+	Handler http.HandlerFunc
+	// FullMethod is the full RPC method string, i.e., /package.service/method.
+	FullMethod string
+}
+
+// MessagingHTTPRouter is the interface of HTTP routers that MessagingHTTPRoute is registered on.
+type MessagingHTTPRouter interface {
+	Handle(method, pattern string, handler http.Handler)
+}
+
+// MessagingHTTPRouterFunc is an adapter to use a function as MessagingHTTPRouter.
+type MessagingHTTPRouterFunc func(method, pattern string, handler http.Handler)
+
+// Handle calls f(method, pattern, handler).
+func (f MessagingHTTPRouterFunc) Handle(method, pattern string, handler http.Handler) {
+	f(method, pattern, handler)
+}
+
+// MessagingHTTPRoutes returns routes of all methods of MessagingHTTPService converted by conv.
+func MessagingHTTPRoutes(conv *MessagingHTTPConverter) []MessagingHTTPRoute {
+	routes := make([]MessagingHTTPRoute, 0)
+	{
+		method, pattern, handler := conv.GetMessageHTTPRule(nil)
+		routes = append(routes, MessagingHTTPRoute{
+			Method:     method,
+			Pattern:    pattern,
+			Handler:    handler,
+			FullMethod: "/httprule.Messaging/GetMessage",
+		})
+	}
+	{
+		method, pattern, handler := conv.UpdateMessageHTTPRule(nil)
+		routes = append(routes, MessagingHTTPRoute{
+			Method:     method,
+			Pattern:    pattern,
+			Handler:    handler,
+			FullMethod: "/httprule.Messaging/UpdateMessage",
+		})
+		routes = append(routes, MessagingHTTPRoute{
+			Method:     http.MethodPost,
+			Pattern:    pattern,
+			Handler:    handler,
+			FullMethod: "/httprule.Messaging/UpdateMessage",
+		})
+	}
+	{
+		method, pattern, handler := conv.PatchMessageHTTPRule(nil)
+		routes = append(routes, MessagingHTTPRoute{
+			Method:     method,
+			Pattern:    pattern,
+			Handler:    handler,
+			FullMethod: "/httprule.Messaging/PatchMessage",
+		})
+	}
+	{
+		method, pattern, handler := conv.CancelMessageHTTPRule(nil)
+		routes = append(routes, MessagingHTTPRoute{
+			Method:     method,
+			Pattern:    pattern,
+			Handler:    handler,
+			FullMethod: "/httprule.Messaging/CancelMessage",
+		})
+	}
+	{
+		method, pattern, handler := conv.SubFieldMessageHTTPRule(nil)
+		routes = append(routes, MessagingHTTPRoute{
+			Method:     method,
+			Pattern:    pattern,
+			Handler:    handler,
+			FullMethod: "/httprule.Messaging/SubFieldMessage",
+		})
+	}
+	return routes
+}
+
+// RegisterMessagingHTTPRoutes registers all routes of MessagingHTTPService on router.
+func RegisterMessagingHTTPRoutes(router MessagingHTTPRouter, conv *MessagingHTTPConverter) {
+	for _, route := range MessagingHTTPRoutes(conv) {
+		router.Handle(route.Method, route.Pattern, route.Handler)
+	}
+}
+
+// MessagingHTTPRoutesHandler returns http.Handler writing HTTP method, pattern and full method name of all routes
+// of conv as JSON.
+func MessagingHTTPRoutesHandler(conv *MessagingHTTPConverter) http.Handler {
+	type route struct {
+		Method     string `json:"method"`
+		Pattern    string `json:"pattern"`
+		FullMethod string `json:"fullMethod"`
+	}
+	routes := MessagingHTTPRoutes(conv)
+	rs := make([]route, 0, len(routes))
+	for _, r := range routes {
+		rs = append(rs, route{Method: r.Method, Pattern: r.Pattern, FullMethod: r.FullMethod})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(rs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// MessagingHTTPOpenAPIHandler returns http.Handler serving OpenAPI document of httprule/httprule.proto.
+func MessagingHTTPOpenAPIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(file_httprule_httprule_proto_openAPI)
+	})
+}
+
+// MessagingHTTPClient is the client API for Messaging service over HTTP.
+// Only unary methods are implemented.
+type MessagingHTTPClient struct {
+	baseURL       string
+	client        *http.Client
+	contentType   string
+	interceptors  []grpc.UnaryClientInterceptor
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+}
+
+// MessagingHTTPClientOption configures MessagingHTTPClient.
+type MessagingHTTPClientOption func(*MessagingHTTPClient)
+
+// WithMessagingHTTPClient sets http.Client used to send requests. http.DefaultClient is used by default.
+func WithMessagingHTTPClient(client *http.Client) MessagingHTTPClientOption {
+	return func(c *MessagingHTTPClient) {
+		c.client = client
+	}
+}
+
+// WithMessagingHTTPClientContentType sets the media type of requests and responses.
+// "application/json" (default), "application/protobuf" and "application/x-protobuf" are supported.
+func WithMessagingHTTPClientContentType(contentType string) MessagingHTTPClientOption {
+	return func(c *MessagingHTTPClient) {
+		c.contentType = contentType
+	}
+}
+
+// WithMessagingHTTPClientInterceptors appends interceptors executed in left-to-right order for each call.
+// The method passed to the interceptors is the full method name, e.g., "/httprule.Messaging/Method", and cc is always nil.
+func WithMessagingHTTPClientInterceptors(interceptors ...grpc.UnaryClientInterceptor) MessagingHTTPClientOption {
+	return func(c *MessagingHTTPClient) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithMessagingHTTPClientHeaders sets the outgoing metadata keys sent as the request headers of the same names without the prefix.
+// The response headers of the names are also read as the header metadata.
+// The default is Authorization, the same as the incoming headers of the converter.
+func WithMessagingHTTPClientHeaders(headers ...string) MessagingHTTPClientOption {
+	return func(c *MessagingHTTPClient) {
+		c.headers = headers
+	}
+}
+
+// WithMessagingHTTPClientHeaderPrefix sets the prefix of the request headers sending the other outgoing metadata,
+// and of the response headers read as the header metadata.
+// The default is "Grpc-Metadata-", the same as the incoming and outgoing header prefixes of the converter.
+func WithMessagingHTTPClientHeaderPrefix(prefix string) MessagingHTTPClientOption {
+	return func(c *MessagingHTTPClient) {
+		c.headerPrefix = prefix
+	}
+}
+
+// WithMessagingHTTPClientTrailerPrefix sets the prefix of the response headers read as the trailer metadata.
+// The default is "Grpc-Trailer-", the same as the outgoing trailer prefix of the converter.
+func WithMessagingHTTPClientTrailerPrefix(prefix string) MessagingHTTPClientOption {
+	return func(c *MessagingHTTPClient) {
+		c.trailerPrefix = prefix
+	}
+}
+
+// NewMessagingHTTPClient returns MessagingHTTPClient sending requests to baseURL, e.g., "http://localhost:8080".
+func NewMessagingHTTPClient(baseURL string, opts ...MessagingHTTPClientOption) *MessagingHTTPClient {
+	c := &MessagingHTTPClient{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		client:        http.DefaultClient,
+		contentType:   "application/json",
+		headers:       []string{"Authorization"},
+		headerPrefix:  "Grpc-Metadata-",
+		trailerPrefix: "Grpc-Trailer-",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetMessage calls GetMessage with GET /v1/messages/{message_id}.
+func (c *MessagingHTTPClient) GetMessage(ctx context.Context, in *GetMessageRequest) (*Message, error) {
+	out := &Message{}
+	if err := c.invoke(ctx, "/httprule.Messaging/GetMessage", in, out, c.invokeGetMessage); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeGetMessage is the grpc.UnaryInvoker sending the request of GetMessage.
+func (c *MessagingHTTPClient) invokeGetMessage(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*GetMessageRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	path := "/v1/messages/" + url.PathEscape(in.GetMessageId())
+	query := url.Values{}
+	if v := in.GetRevision(); v != 0 {
+		query.Set("revision", strconv.FormatInt(int64(v), 10))
+	}
+	if v := in.GetSub().GetSubfield(); v != "" {
+		query.Set("sub.subfield", v)
+	}
+	return c.do(ctx, http.MethodGet, path, query, nil, "", out, opts...)
+}
+
+// UpdateMessage calls UpdateMessage with PUT /v1/messages/{message_id}.
+func (c *MessagingHTTPClient) UpdateMessage(ctx context.Context, in *UpdateMessageRequest) (*Message, error) {
+	out := &Message{}
+	if err := c.invoke(ctx, "/httprule.Messaging/UpdateMessage", in, out, c.invokeUpdateMessage); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeUpdateMessage is the grpc.UnaryInvoker sending the request of UpdateMessage.
+func (c *MessagingHTTPClient) invokeUpdateMessage(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*UpdateMessageRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	path := "/v1/messages/" + url.PathEscape(in.GetMessageId())
+	body := proto.Clone(in).(*UpdateMessageRequest)
+	body.MessageId = ""
+	return c.do(ctx, http.MethodPut, path, nil, body, "", out, opts...)
+}
+
+// PatchMessage calls PatchMessage with PATCH /v1/messages/{message_id}.
+func (c *MessagingHTTPClient) PatchMessage(ctx context.Context, in *UpdateMessageRequest) (*Message, error) {
+	out := &Message{}
+	if err := c.invoke(ctx, "/httprule.Messaging/PatchMessage", in, out, c.invokePatchMessage); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokePatchMessage is the grpc.UnaryInvoker sending the request of PatchMessage.
+func (c *MessagingHTTPClient) invokePatchMessage(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*UpdateMessageRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	path := "/v1/messages/" + url.PathEscape(in.GetMessageId())
+	query := url.Values{}
+	return c.do(ctx, http.MethodPatch, path, query, in, "message", out, opts...)
+}
+
+// CancelMessage calls CancelMessage with POST /v1/messages/{message_id}:cancel.
+func (c *MessagingHTTPClient) CancelMessage(ctx context.Context, in *GetMessageRequest) (*Message, error) {
+	out := &Message{}
+	if err := c.invoke(ctx, "/httprule.Messaging/CancelMessage", in, out, c.invokeCancelMessage); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeCancelMessage is the grpc.UnaryInvoker sending the request of CancelMessage.
+func (c *MessagingHTTPClient) invokeCancelMessage(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*GetMessageRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	path := "/v1/messages/" + url.PathEscape(in.GetMessageId()) + ":cancel"
+	body := proto.Clone(in).(*GetMessageRequest)
+	body.MessageId = ""
+	return c.do(ctx, http.MethodPost, path, nil, body, "", out, opts...)
+}
+
+// SubFieldMessage calls SubFieldMessage with POST /v1/messages/{message_id}/{sub.subfield}.
+func (c *MessagingHTTPClient) SubFieldMessage(ctx context.Context, in *SubFieldMessageRequest) (*Message, error) {
+	out := &Message{}
+	if err := c.invoke(ctx, "/httprule.Messaging/SubFieldMessage", in, out, c.invokeSubFieldMessage); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeSubFieldMessage is the grpc.UnaryInvoker sending the request of SubFieldMessage.
+func (c *MessagingHTTPClient) invokeSubFieldMessage(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*SubFieldMessageRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	path := "/v1/messages/" + url.PathEscape(in.GetMessageId()) + "/" + url.PathEscape(in.GetSub().GetSubfield())
+	body := proto.Clone(in).(*SubFieldMessageRequest)
+	body.MessageId = ""
+	if m := body.GetSub(); m != nil {
+		m.Subfield = ""
+	}
+	return c.do(ctx, http.MethodPost, path, nil, body, "", out, opts...)
+}
+
+var _ MessagingHTTPService = (*MessagingHTTPClient)(nil)
+
+// MessagingHTTPClientConn implements grpc.ClientConnInterface by MessagingHTTPClient, so the client can be used as
+// the connection of gRPC clients, e.g., NewMessagingClient(NewMessagingHTTPClientConn(NewMessagingHTTPClient(baseURL))).
+type MessagingHTTPClientConn struct {
+	c *MessagingHTTPClient
+}
+
+// NewMessagingHTTPClientConn returns MessagingHTTPClientConn sending the RPCs by c.
+func NewMessagingHTTPClientConn(c *MessagingHTTPClient) *MessagingHTTPClientConn {
+	return &MessagingHTTPClientConn{c: c}
+}
+
+// Invoke sends the unary RPC of method over HTTP. The HTTP method and path are decided by
+// google.api.http option of the method in the same way as the methods of MessagingHTTPClient.
+// The response metadata is set to grpc.Header and grpc.Trailer of opts, and the other options are ignored.
+func (cc *MessagingHTTPClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	switch method {
+	case "/httprule.Messaging/GetMessage":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeGetMessage, opts...)
+	case "/httprule.Messaging/UpdateMessage":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeUpdateMessage, opts...)
+	case "/httprule.Messaging/PatchMessage":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokePatchMessage, opts...)
+	case "/httprule.Messaging/CancelMessage":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeCancelMessage, opts...)
+	case "/httprule.Messaging/SubFieldMessage":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeSubFieldMessage, opts...)
+	}
+	return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by MessagingHTTPClient.
+func (cc *MessagingHTTPClientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streaming RPC %s is not supported over HTTP", method)
+}
+
+var _ grpc.ClientConnInterface = (*MessagingHTTPClientConn)(nil)
+
+// Invoke is the same as Invoke of MessagingHTTPClientConn. Invoke and NewStream implement grpc.ClientConnInterface,
+// so the client can be used as the connection of gRPC clients, e.g., NewMessagingClient(NewMessagingHTTPClient(baseURL)).
+func (c *MessagingHTTPClient) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return NewMessagingHTTPClientConn(c).Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by MessagingHTTPClient.
+func (c *MessagingHTTPClient) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return NewMessagingHTTPClientConn(c).NewStream(ctx, desc, method, opts...)
+}
+
+var _ grpc.ClientConnInterface = (*MessagingHTTPClient)(nil)
+
+// invoke calls invoker through the interceptors.
+func (c *MessagingHTTPClient) invoke(ctx context.Context, method string, req, reply interface{}, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	chainer := func(currentInter grpc.UnaryClientInterceptor, currentInvoker grpc.UnaryInvoker) grpc.UnaryInvoker {
+		return func(currentCtx context.Context, currentMethod string, currentReq, currentReply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return currentInter(currentCtx, currentMethod, currentReq, currentReply, cc, currentInvoker, opts...)
+		}
+	}
+	chainedInvoker := invoker
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		chainedInvoker = chainer(c.interceptors[i], chainedInvoker)
+	}
+	return chainedInvoker(ctx, method, req, reply, nil, opts...)
+}
+
+// do sends in, or the field of in if field is not empty, as the request body unless in is nil,
+// and unmarshals the response into out. The response metadata is set to grpc.Header and grpc.Trailer of opts.
+func (c *MessagingHTTPClient) do(ctx context.Context, method, path string, query url.Values, in proto.Message, field protoreflect.Name, out proto.Message, opts ...grpc.CallOption) error {
+	u := c.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		buf, err := c.marshal(in, field)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	// Content-Type is also set to GET requests because the converter encodes errors by it.
+	req.Header.Set("Content-Type", c.contentType)
+	req.Header.Set("Accept", c.contentType)
+	// Outgoing metadata set by interceptors is sent as headers which the converter reads as incoming metadata.
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for k, vs := range md {
+		key := c.headerPrefix + k
+		for _, h := range c.headers {
+			if strings.EqualFold(h, k) {
+				key = k
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			req.Header.Add(key, v)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	c.setMetadata(resp.Header, opts)
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return messagingHTTPClientError(resp.StatusCode, contentType, buf)
+	}
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		return proto.Unmarshal(buf, out)
+	default:
+		return protojson.Unmarshal(buf, out)
+	}
+}
+
+// setMetadata sets the metadata read from the response headers to grpc.Header and grpc.Trailer of opts.
+// Values of the keys ending with "-bin" are decoded from base64 as gRPC does. The other options are ignored.
+func (c *MessagingHTTPClient) setMetadata(header http.Header, opts []grpc.CallOption) {
+	headerMD, trailerMD := metadata.MD{}, metadata.MD{}
+	add := func(md metadata.MD, key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+	hasPrefix := func(key, prefix string) bool {
+		return prefix != "" && len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix)
+	}
+
+	for _, key := range c.headers {
+		add(headerMD, key, header.Values(key))
+	}
+	for key, values := range header {
+		switch {
+		case hasPrefix(key, c.trailerPrefix):
+			add(trailerMD, key[len(c.trailerPrefix):], values)
+		case hasPrefix(key, c.headerPrefix):
+			add(headerMD, key[len(c.headerPrefix):], values)
+		}
+	}
+
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			*o.HeaderAddr = headerMD
+		case grpc.TrailerCallOption:
+			*o.TrailerAddr = trailerMD
+		}
+	}
+}
+
+// marshal marshals in, or the field of in if field is not empty, in the content type of the client.
+// Only message fields can be marshaled in protobuf.
+func (c *MessagingHTTPClient) marshal(in proto.Message, field protoreflect.Name) ([]byte, error) {
+	protobuf := c.contentType == "application/protobuf" || c.contentType == "application/x-protobuf"
+	if field == "" {
+		if protobuf {
+			return proto.Marshal(in)
+		}
+		return protojson.Marshal(in)
+	}
+
+	m := in.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName(field)
+	if protobuf {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, status.Errorf(codes.Unimplemented, "field %s cannot be sent in %s", field, c.contentType)
+		}
+		return proto.Marshal(m.Get(fd).Message().Interface())
+	}
+	// The JSON of the field is taken from the message which has only the field.
+	only := m.New()
+	if m.Has(fd) {
+		only.Set(fd, m.Get(fd))
+	}
+	buf, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(only.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return nil, err
+	}
+	return fields[fd.JSONName()], nil
+}
+
+// messagingHTTPClientError converts the error response to the error of grpc/status.
+// If the body is not google.rpc.Status, the code is decided by the HTTP status code as gRPC clients do.
+func messagingHTTPClientError(code int, contentType string, body []byte) error {
+	p := status.New(codes.Unknown, "").Proto()
+	var err error
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		err = proto.Unmarshal(body, p)
+	case "application/json":
+		err = protojson.Unmarshal(body, p)
+	default:
+		err = fmt.Errorf("unexpected Content-Type: %s", contentType)
+	}
+	if err == nil && codes.Code(p.GetCode()) != codes.OK {
+		return status.ErrorProto(p)
+	}
+
+	c := codes.Unknown
+	switch code {
+	case http.StatusBadRequest:
+		c = codes.Internal
+	case http.StatusUnauthorized:
+		c = codes.Unauthenticated
+	case http.StatusForbidden:
+		c = codes.PermissionDenied
+	case http.StatusNotFound:
+		c = codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		c = codes.Unavailable
+	}
+	return status.Errorf(c, "unexpected HTTP status code %d: %s", code, body)
+}
+
+// file_httprule_httprule_proto_incomingMetadata returns the metadata built from the headers listed in headers and the headers with prefix.
+// The empty prefix disables it. Values of the keys ending with "-bin" are decoded from base64 as gRPC does.
+func file_httprule_httprule_proto_incomingMetadata(header http.Header, headers []string, prefix string) metadata.MD {
+	md := metadata.MD{}
+	add := func(key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+
+	for _, key := range headers {
+		add(key, header.Values(key))
+	}
+	if prefix != "" {
+		for key, values := range header {
+			if len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+				add(key[len(prefix):], values)
+			}
+		}
+	}
+	return md
+}
+
+// file_httprule_httprule_proto_httpTransportStream implements grpc.ServerTransportStream to write header and trailer metadata set by the RPC
+// as HTTP headers, so that grpc.SetHeader, grpc.SendHeader and grpc.SetTrailer work in the RPC.
+type file_httprule_httprule_proto_httpTransportStream struct {
+	method        string
+	w             http.ResponseWriter
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+
+	mu         sync.Mutex
+	header     metadata.MD
+	trailer    metadata.MD
+	headerSent bool
+}
+
+func (s *file_httprule_httprule_proto_httpTransportStream) Method() string {
+	return s.method
+}
+
+// SetHeader sets the header metadata written before the response body.
+func (s *file_httprule_httprule_proto_httpTransportStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader sets the header metadata and writes it to the response headers.
+// The headers are sent to the client with the response body.
+func (s *file_httprule_httprule_proto_httpTransportStream) SendHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	s.writeHeaderLocked()
+	return nil
+}
+
+// SetTrailer sets the trailer metadata written after the RPC returns.
+func (s *file_httprule_httprule_proto_httpTransportStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// writeHeader writes the header metadata to the response headers unless it has been written.
+func (s *file_httprule_httprule_proto_httpTransportStream) writeHeader() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeHeaderLocked()
+}
+
+func (s *file_httprule_httprule_proto_httpTransportStream) writeHeaderLocked() {
+	if s.headerSent {
+		return
+	}
+	s.headerSent = true
+	s.add(s.headerPrefix, s.header, s.headers)
+}
+
+// writeTrailer writes the trailer metadata as HTTP trailers if asTrailer is true.
+// Otherwise, it is written to the response headers because the response body has not been written yet.
+func (s *file_httprule_httprule_proto_httpTransportStream) writeTrailer(asTrailer bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if asTrailer {
+		s.add(http.TrailerPrefix+s.trailerPrefix, s.trailer, nil)
+		return
+	}
+	s.add(s.trailerPrefix, s.trailer, nil)
+}
+
+// writeStatusCode writes the status code set by the header metadata "x-http-code" and reports whether
+// the response body is allowed. The status code is ignored unless it is between 200 and 599.
+func (s *file_httprule_httprule_proto_httpTransportStream) writeStatusCode() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vs := s.header.Get("x-http-code")
+	if len(vs) == 0 {
+		return true
+	}
+	code, err := strconv.Atoi(vs[len(vs)-1])
+	if err != nil || code < 200 || code > 599 {
+		return true
+	}
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		s.w.Header().Del("Content-Type")
+		s.w.Header().Del("Content-Encoding")
+		s.w.WriteHeader(code)
+		return false
+	}
+	s.w.WriteHeader(code)
+	return true
+}
+
+// add adds md to the response headers with the prefix. The keys listed in headers are added without the prefix.
+// Values of the keys ending with "-bin" are encoded in base64 as gRPC does.
+func (s *file_httprule_httprule_proto_httpTransportStream) add(prefix string, md metadata.MD, headers []string) {
+	for k, vs := range md {
+		if k == "x-http-code" {
+			continue
+		}
+		name := prefix + k
+		for _, header := range headers {
+			if strings.EqualFold(header, k) {
+				name = header
+				break
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			s.w.Header().Add(name, v)
+		}
+	}
+}
+
+// file_httprule_httprule_proto_timeout returns the timeout of the RPC set by Grpc-Timeout header or the header named name,
+// whose value is parsed by time.ParseDuration. The shorter one is used if both are set. Invalid values are ignored.
+func file_httprule_httprule_proto_timeout(header http.Header, name string) (time.Duration, bool) {
+	timeout, ok := file_httprule_httprule_proto_parseGRPCTimeout(header.Get("Grpc-Timeout"))
+	if name == "" {
+		return timeout, ok
+	}
+	v := header.Get(name)
+	if v == "" {
+		return timeout, ok
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return timeout, ok
+	}
+	if !ok || d < timeout {
+		return d, true
+	}
+	return timeout, true
+}
+
+// file_httprule_httprule_proto_parseGRPCTimeout parses v in the format of Grpc-Timeout header, that is at most 8 digits followed by the unit,
+// H (hours), M (minutes), S (seconds), m (milliseconds), u (microseconds) or n (nanoseconds).
+func file_httprule_httprule_proto_parseGRPCTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 || len(v) > 9 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch v[len(v)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	n, err := strconv.ParseUint(v[:len(v)-1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	if time.Duration(n) > math.MaxInt64/unit {
+		return math.MaxInt64, true
+	}
+	return time.Duration(n) * unit, true
+}
+
+// file_httprule_httprule_proto_requestPeer returns the peer of the RPC built from the remote address and the TLS connection state of r.
+// It returns nil if the remote address is not an IP address and port.
+func file_httprule_httprule_proto_requestPeer(r *http.Request) *peer.Peer {
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return nil
+	}
+	p := &peer.Peer{
+		Addr: &net.TCPAddr{IP: ip, Port: n},
+	}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{
+			State: *r.TLS,
+			CommonAuthInfo: credentials.CommonAuthInfo{
+				SecurityLevel: credentials.PrivacyAndIntegrity,
+			},
+		}
+	}
+	return p
+}
+
+// file_httprule_httprule_proto_httpRequestBody counts the bytes read from the request body.
+type file_httprule_httprule_proto_httpRequestBody struct {
+	io.ReadCloser
+	size int64
+}
+
+func (b *file_httprule_httprule_proto_httpRequestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+// file_httprule_httprule_proto_httpResponseWriter records the status code and the size of the response written to http.ResponseWriter.
+type file_httprule_httprule_proto_httpResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int64
+}
+
+func (w *file_httprule_httprule_proto_httpResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *file_httprule_httprule_proto_httpResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher to flush streaming responses.
+func (w *file_httprule_httprule_proto_httpResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter for http.ResponseController.
+func (w *file_httprule_httprule_proto_httpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// status returns the status code of the response. It is 200 if nothing has been written as net/http does.
+func (w *file_httprule_httprule_proto_httpResponseWriter) status() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+	return w.statusCode
+}
+
+// file_httprule_httprule_proto_redact returns m marshaled in JSON without the fields marked with debug_redact option.
+func file_httprule_httprule_proto_redact(m proto.Message) string {
+	buf, err := protojson.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(buf)
+}
+
+// file_httprule_httprule_proto_decodeBody replaces the body of r with the reader decoding encoding, which is gzip, x-gzip or deflate.
+// A malformed body fails with codes.InvalidArgument.
+func file_httprule_httprule_proto_decodeBody(r *http.Request, encoding string) error {
+	var zr io.ReadCloser
+	var err error
+	if encoding == "deflate" {
+		zr, err = zlib.NewReader(r.Body)
+	} else {
+		zr, err = gzip.NewReader(r.Body)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", encoding, err)
+	}
+	r.Body = &file_httprule_httprule_proto_decodedBody{ReadCloser: zr, encoding: encoding}
+	return nil
+}
+
+// file_httprule_httprule_proto_decodedBody is the request body decoded by Content-Encoding header.
+// Reading it fails with codes.InvalidArgument if the rest of the body turns out to be malformed.
+type file_httprule_httprule_proto_decodedBody struct {
+	io.ReadCloser
+	encoding string
+}
+
+func (b *file_httprule_httprule_proto_decodedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", b.encoding, err)
+	}
+	return n, err
+}
+
+// file_httprule_httprule_proto_compress returns buf compressed by the content coding negotiated by Accept-Encoding header of r
+// if buf is at least minSize bytes, or identity is not accepted. A negative minSize disables the compression.
+// Otherwise, it returns buf as is.
+func file_httprule_httprule_proto_compress(w http.ResponseWriter, r *http.Request, buf []byte, minSize int) []byte {
+	if minSize < 0 {
+		return buf
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	encoding, identity := file_httprule_httprule_proto_acceptEncoding(r)
+	if identity && len(buf) < minSize {
+		return buf
+	}
+	var b bytes.Buffer
+	var zw io.WriteCloser
+	switch encoding {
+	case "gzip":
+		zw = gzip.NewWriter(&b)
+	case "deflate":
+		zw = zlib.NewWriter(&b)
+	default:
+		return buf
+	}
+	if _, err := zw.Write(buf); err != nil {
+		return buf
+	}
+	if err := zw.Close(); err != nil {
+		return buf
+	}
+	w.Header().Set("Content-Encoding", encoding)
+	return b.Bytes()
+}
+
+// file_httprule_httprule_proto_acceptEncoding returns gzip or deflate accepted by Accept-Encoding header of r. gzip is preferred to deflate.
+// It returns the empty string if neither is accepted. Codings with q=0 are not accepted, and * applies to the codings
+// not listed in the header. identity reports whether the response may be sent without any coding.
+func file_httprule_httprule_proto_acceptEncoding(r *http.Request) (encoding string, identity bool) {
+	qs := make(map[string]float64)
+	for _, v := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(v, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		if coding == "x-gzip" {
+			coding = "gzip"
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		qs[coding] = q
+	}
+	accepted := func(coding string) bool {
+		if q, ok := qs[coding]; ok {
+			return q > 0
+		}
+		if q, ok := qs["*"]; ok {
+			return q > 0
+		}
+		// identity is acceptable unless it is excluded explicitly.
+		return coding == "identity"
+	}
+
+	switch {
+	case accepted("gzip"):
+		encoding = "gzip"
+	case accepted("deflate"):
+		encoding = "deflate"
+	}
+	return encoding, accepted("identity")
+}
+
+//go:embed httprule.openapi.json
+var file_httprule_httprule_proto_openAPI []byte
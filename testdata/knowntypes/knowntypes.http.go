@@ -5,13 +5,23 @@ package knowntypespb
 
 import (
 	bytes "bytes"
+	gzip "compress/gzip"
+	zlib "compress/zlib"
 	context "context"
+	_ "embed"
+	base64 "encoding/base64"
+	json "encoding/json"
+	errors "errors"
 	fmt "fmt"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
+	credentials "google.golang.org/grpc/credentials"
+	metadata "google.golang.org/grpc/metadata"
+	peer "google.golang.org/grpc/peer"
 	status "google.golang.org/grpc/status"
 	protojson "google.golang.org/protobuf/encoding/protojson"
 	proto "google.golang.org/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	anypb "google.golang.org/protobuf/types/known/anypb"
 	apipb "google.golang.org/protobuf/types/known/apipb"
 	durationpb "google.golang.org/protobuf/types/known/durationpb"
@@ -23,9 +33,17 @@ import (
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	io "io"
 	ioutil "io/ioutil"
+	slog "log/slog"
+	math "math"
 	mime "mime"
+	net "net"
 	http "net/http"
+	url "net/url"
+	debug "runtime/debug"
+	strconv "strconv"
 	strings "strings"
+	sync "sync"
+	time "time"
 )
 
 // KnownTypesServiceHTTPService is the server API for KnownTypesService service.
@@ -42,25 +60,324 @@ type KnownTypesServiceHTTPService interface {
 	Wrappers(context.Context, *wrapperspb.BoolValue) (*wrapperspb.BoolValue, error)
 }
 
+// KnownTypesServiceHTTPTracer is called at the start and the finish of every request handled by KnownTypesServiceHTTPConverter,
+// e.g. to start and end a span of a tracing library without depending on it.
+type KnownTypesServiceHTTPTracer interface {
+	// OnStart is called when the handler of method starts handling r. route is the path template of google.api.http option,
+	// or empty if the handler is not returned by the HTTPRule method. The returned context is passed to the RPC and OnFinish.
+	OnStart(ctx context.Context, method protoreflect.MethodDescriptor, route string, r *http.Request) context.Context
+	// OnFinish is called after the callback with the arguments passed to the callback, the status code of the response
+	// and the time elapsed since OnStart. The gRPC code of the RPC is status.Code(err).
+	OnFinish(ctx context.Context, method protoreflect.MethodDescriptor, arg, ret proto.Message, statusCode int, err error, elapsed time.Duration)
+}
+
+// KnownTypesServiceHTTPObserver observes the metrics of every request handled by KnownTypesServiceHTTPConverter,
+// e.g. to export the latency, the sizes and the status of RPCs to a metrics library without depending on it.
+type KnownTypesServiceHTTPObserver interface {
+	// Observe is called after the callback with the method, the path template of google.api.http option
+	// (empty if the handler is not returned by the HTTPRule method), the status code of the response,
+	// the gRPC code of the RPC, the bytes read from the request body and written to the response body, and the latency.
+	Observe(ctx context.Context, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, requestSize, responseSize int64, elapsed time.Duration)
+}
+
 // KnownTypesServiceHTTPConverter has a function to convert KnownTypesServiceHTTPService interface to http.HandlerFunc.
 type KnownTypesServiceHTTPConverter struct {
-	srv KnownTypesServiceHTTPService
+	srv                   KnownTypesServiceHTTPService
+	cb                    func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)
+	interceptors          []grpc.UnaryServerInterceptor
+	maxBodySize           int64
+	skipMethodCheck       bool
+	incomingHeaders       []string
+	incomingHeaderPrefix  string
+	outgoingHeaders       []string
+	outgoingHeaderPrefix  string
+	outgoingTrailerPrefix string
+	timeoutHeader         string
+	tracer                KnownTypesServiceHTTPTracer
+	observer              KnownTypesServiceHTTPObserver
+	accessLogger          *slog.Logger
+	accessLogPayloads     bool
+	recovery              bool
+	panicHandler          func(ctx context.Context, fullMethod string, p interface{}, stack []byte)
+	compressionMinSize    int
+}
+
+// KnownTypesServiceHTTPConverterOption configures KnownTypesServiceHTTPConverter.
+type KnownTypesServiceHTTPConverterOption func(*KnownTypesServiceHTTPConverter)
+
+// WithKnownTypesServiceHTTPCallback sets the callback used when nil is passed to a convert method.
+func WithKnownTypesServiceHTTPCallback(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.cb = cb
+	}
+}
+
+// WithKnownTypesServiceHTTPInterceptors appends interceptors executed before the interceptors passed to a convert method.
+func WithKnownTypesServiceHTTPInterceptors(interceptors ...grpc.UnaryServerInterceptor) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.interceptors = append(h.interceptors, interceptors...)
+	}
+}
+
+// WithKnownTypesServiceHTTPMaxBodySize sets the maximum size of request bodies in bytes.
+// The default is 4194304 bytes set by max_body_size option of protoc-gen-gohttp. Zero or a negative value means no limit.
+// Requests with larger bodies fail with *http.MaxBytesError, and the default callback responds 413 Request Entity Too Large.
+func WithKnownTypesServiceHTTPMaxBodySize(n int64) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.maxBodySize = n
+	}
+}
+
+// WithKnownTypesServiceHTTPSkipMethodCheck sets whether the handlers returned by HTTPRule methods skip checking the request method.
+// By default, the handlers respond 405 Method Not Allowed with Allow header if the request method does not match google.api.http option.
+// Routers that already route requests by the method can skip the check.
+func WithKnownTypesServiceHTTPSkipMethodCheck(skip bool) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.skipMethodCheck = skip
+	}
+}
+
+// WithKnownTypesServiceHTTPIncomingHeaders sets the request headers passed to the RPC as incoming metadata.
+// The default is Authorization. The metadata keys are the lowercase header names.
+func WithKnownTypesServiceHTTPIncomingHeaders(headers ...string) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.incomingHeaders = headers
+	}
+}
+
+// WithKnownTypesServiceHTTPIncomingHeaderPrefix sets the prefix of the request headers passed to the RPC as incoming metadata.
+// The metadata keys are the lowercase header names without the prefix. The default is "Grpc-Metadata-",
+// and the empty prefix disables it.
+func WithKnownTypesServiceHTTPIncomingHeaderPrefix(prefix string) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.incomingHeaderPrefix = prefix
+	}
+}
+
+// WithKnownTypesServiceHTTPOutgoingHeaders sets the response headers written from the header metadata with the same
+// lowercase names, e.g. "location" for Location. They are written without the prefix. The default is Location.
+func WithKnownTypesServiceHTTPOutgoingHeaders(headers ...string) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.outgoingHeaders = headers
+	}
+}
+
+// WithKnownTypesServiceHTTPOutgoingHeaderPrefix sets the prefix of the response headers written from the header metadata
+// set by grpc.SetHeader or grpc.SendHeader. The default is "Grpc-Metadata-".
+func WithKnownTypesServiceHTTPOutgoingHeaderPrefix(prefix string) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.outgoingHeaderPrefix = prefix
+	}
+}
+
+// WithKnownTypesServiceHTTPOutgoingTrailerPrefix sets the prefix of the response headers or trailers written from the trailer metadata
+// set by grpc.SetTrailer. The default is "Grpc-Trailer-".
+func WithKnownTypesServiceHTTPOutgoingTrailerPrefix(prefix string) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.outgoingTrailerPrefix = prefix
+	}
+}
+
+// WithKnownTypesServiceHTTPTimeoutHeader sets the request header of the timeout of the RPC in addition to Grpc-Timeout header.
+// The value is parsed by time.ParseDuration, e.g. "1.5s". The empty name disables it, which is the default.
+func WithKnownTypesServiceHTTPTimeoutHeader(name string) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.timeoutHeader = name
+	}
+}
+
+// WithKnownTypesServiceHTTPTracer sets the tracer called at the start and the finish of every request.
+func WithKnownTypesServiceHTTPTracer(tracer KnownTypesServiceHTTPTracer) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.tracer = tracer
+	}
+}
+
+// WithKnownTypesServiceHTTPObserver sets the observer called at the finish of every request.
+func WithKnownTypesServiceHTTPObserver(observer KnownTypesServiceHTTPObserver) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.observer = observer
+	}
+}
+
+// WithKnownTypesServiceHTTPAccessLog sets the logger emitting an access log record of every request with the full method,
+// the HTTP method, the route, the status code, the gRPC code and the latency. Requests responded with 5xx are logged
+// at error level, and the others are logged at info level. The access log is disabled by default.
+func WithKnownTypesServiceHTTPAccessLog(logger *slog.Logger) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.accessLogger = logger
+	}
+}
+
+// WithKnownTypesServiceHTTPAccessLogPayloads sets whether the access log contains the request and response messages in JSON.
+// Fields marked with debug_redact option are removed from the messages.
+func WithKnownTypesServiceHTTPAccessLogPayloads(payloads bool) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.accessLogPayloads = payloads
+	}
+}
+
+// WithKnownTypesServiceHTTPRecovery enables the recovery of panics in the RPC and the interceptors. The panic is converted to
+// *KnownTypesServiceHTTPPanicError of codes.Internal passed to the callback, and reported to handler with the stack trace
+// unless handler is nil. Panics are not recovered by default.
+func WithKnownTypesServiceHTTPRecovery(handler func(ctx context.Context, fullMethod string, p interface{}, stack []byte)) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.recovery = true
+		h.panicHandler = handler
+	}
+}
+
+// WithKnownTypesServiceHTTPCompression enables the compression of response bodies of unary and client-side streaming RPCs
+// with gzip or deflate negotiated by Accept-Encoding header. Only bodies of at least minSize bytes are compressed.
+// A negative value disables it, which is the default.
+func WithKnownTypesServiceHTTPCompression(minSize int) KnownTypesServiceHTTPConverterOption {
+	return func(h *KnownTypesServiceHTTPConverter) {
+		h.compressionMinSize = minSize
+	}
 }
 
 // NewKnownTypesServiceHTTPConverter returns KnownTypesServiceHTTPConverter.
-func NewKnownTypesServiceHTTPConverter(srv KnownTypesServiceHTTPService) *KnownTypesServiceHTTPConverter {
-	return &KnownTypesServiceHTTPConverter{
-		srv: srv,
+func NewKnownTypesServiceHTTPConverter(srv KnownTypesServiceHTTPService, opts ...KnownTypesServiceHTTPConverterOption) *KnownTypesServiceHTTPConverter {
+	h := &KnownTypesServiceHTTPConverter{
+		srv:                   srv,
+		maxBodySize:           4194304,
+		incomingHeaders:       []string{"Authorization"},
+		incomingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingHeaders:       []string{"Location"},
+		outgoingHeaderPrefix:  "Grpc-Metadata-",
+		outgoingTrailerPrefix: "Grpc-Trailer-",
+		compressionMinSize:    -1,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// trace calls OnStart of the tracer, and returns the context returned by OnStart, w recording the response
+// and cb calling OnFinish of the tracer, Observe of the observer and the access logger after cb.
+// The body of r is replaced to count the bytes read.
+func (h *KnownTypesServiceHTTPConverter) trace(ctx context.Context, w http.ResponseWriter, r *http.Request, name protoreflect.Name, route string, cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) (context.Context, http.ResponseWriter, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error)) {
+	method := File_knowntypes_knowntypes_proto.Services().ByName("KnownTypesService").Methods().ByName(name)
+	start := time.Now()
+	if h.tracer != nil {
+		ctx = h.tracer.OnStart(ctx, method, route, r)
+	}
+	rw := &file_knowntypes_knowntypes_proto_httpResponseWriter{ResponseWriter: w}
+	body := &file_knowntypes_knowntypes_proto_httpRequestBody{ReadCloser: r.Body}
+	r.Body = body
+	return ctx, rw, func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
+		cb(ctx, w, r, arg, ret, err)
+		elapsed := time.Since(start)
+		if h.tracer != nil {
+			h.tracer.OnFinish(ctx, method, arg, ret, rw.status(), err, elapsed)
+		}
+		code := status.Code(err)
+		switch {
+		case code != codes.Unknown:
+		case errors.Is(err, context.DeadlineExceeded):
+			code = codes.DeadlineExceeded
+		case errors.Is(err, context.Canceled):
+			code = codes.Canceled
+		}
+		if h.observer != nil {
+			h.observer.Observe(ctx, method, route, rw.status(), code, body.size, rw.size, elapsed)
+		}
+		if h.accessLogger != nil {
+			h.logAccess(ctx, r, method, route, rw.status(), code, err, elapsed, arg, ret)
+		}
+	}
+}
+
+// logAccess emits the access log record of the request.
+func (h *KnownTypesServiceHTTPConverter) logAccess(ctx context.Context, r *http.Request, method protoreflect.MethodDescriptor, route string, statusCode int, code codes.Code, err error, elapsed time.Duration, arg, ret proto.Message) {
+	level := slog.LevelInfo
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	attrs := []slog.Attr{
+		slog.String("method", fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())),
+		slog.String("http_method", r.Method),
+		slog.String("route", route),
+		slog.Int("status", statusCode),
+		slog.String("code", code.String()),
+		slog.Duration("latency", elapsed),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
 	}
+	if h.accessLogPayloads {
+		if arg != nil {
+			attrs = append(attrs, slog.String("request", file_knowntypes_knowntypes_proto_redact(arg)))
+		}
+		if ret != nil {
+			attrs = append(attrs, slog.String("response", file_knowntypes_knowntypes_proto_redact(ret)))
+		}
+	}
+	h.accessLogger.LogAttrs(ctx, level, "access", attrs...)
+}
+
+// KnownTypesServiceHTTPPanicError is the error of a panic recovered by KnownTypesServiceHTTPConverter.
+type KnownTypesServiceHTTPPanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine panicked.
+	Stack []byte
+}
+
+func (e *KnownTypesServiceHTTPPanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// GRPCStatus returns the status of codes.Internal, so that status.Code returns codes.Internal for the error.
+// The message of the status does not contain the panic value, which must not be sent to clients.
+func (e *KnownTypesServiceHTTPPanicError) GRPCStatus() *status.Status {
+	return status.New(codes.Internal, "internal error")
+}
+
+// recovered reports the panic p to the panic handler and returns it as *KnownTypesServiceHTTPPanicError.
+func (h *KnownTypesServiceHTTPConverter) recovered(ctx context.Context, fullMethod string, p interface{}) error {
+	err := &KnownTypesServiceHTTPPanicError{Value: p, Stack: debug.Stack()}
+	if h.panicHandler != nil {
+		h.panicHandler(ctx, fullMethod, p, err.Stack)
+	}
+	return err
+}
+
+// recoverUnary is the outermost interceptor recovering panics in the RPC and the interceptors.
+func (h *KnownTypesServiceHTTPConverter) recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = h.recovered(ctx, info.FullMethod, p)
+		}
+	}()
+	return handler(ctx, req)
 }
 
 // Any returns KnownTypesServiceHTTPService interface's Any converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) Any(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -83,8 +400,35 @@ func (h *KnownTypesServiceHTTPConverter) Any(cb func(ctx context.Context, w http
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Any", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/Any",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -102,6 +446,22 @@ func (h *KnownTypesServiceHTTPConverter) Any(cb func(ctx context.Context, w http
 
 		arg := &anypb.Any{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -152,6 +512,8 @@ func (h *KnownTypesServiceHTTPConverter) Any(cb func(ctx context.Context, w http
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -170,6 +532,11 @@ func (h *KnownTypesServiceHTTPConverter) Any(cb func(ctx context.Context, w http
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -180,6 +547,11 @@ func (h *KnownTypesServiceHTTPConverter) Any(cb func(ctx context.Context, w http
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -201,11 +573,28 @@ func (h *KnownTypesServiceHTTPConverter) AnyWithName(cb func(ctx context.Context
 
 // Api returns KnownTypesServiceHTTPService interface's Api converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) Api(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -228,8 +617,35 @@ func (h *KnownTypesServiceHTTPConverter) Api(cb func(ctx context.Context, w http
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Api", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/Api",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -247,6 +663,22 @@ func (h *KnownTypesServiceHTTPConverter) Api(cb func(ctx context.Context, w http
 
 		arg := &apipb.Api{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -297,6 +729,8 @@ func (h *KnownTypesServiceHTTPConverter) Api(cb func(ctx context.Context, w http
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -315,6 +749,11 @@ func (h *KnownTypesServiceHTTPConverter) Api(cb func(ctx context.Context, w http
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -325,6 +764,11 @@ func (h *KnownTypesServiceHTTPConverter) Api(cb func(ctx context.Context, w http
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -346,11 +790,28 @@ func (h *KnownTypesServiceHTTPConverter) ApiWithName(cb func(ctx context.Context
 
 // Duration returns KnownTypesServiceHTTPService interface's Duration converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) Duration(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -373,8 +834,35 @@ func (h *KnownTypesServiceHTTPConverter) Duration(cb func(ctx context.Context, w
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Duration", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/Duration",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -392,6 +880,22 @@ func (h *KnownTypesServiceHTTPConverter) Duration(cb func(ctx context.Context, w
 
 		arg := &durationpb.Duration{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -442,6 +946,8 @@ func (h *KnownTypesServiceHTTPConverter) Duration(cb func(ctx context.Context, w
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -460,6 +966,11 @@ func (h *KnownTypesServiceHTTPConverter) Duration(cb func(ctx context.Context, w
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -470,6 +981,11 @@ func (h *KnownTypesServiceHTTPConverter) Duration(cb func(ctx context.Context, w
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -491,11 +1007,28 @@ func (h *KnownTypesServiceHTTPConverter) DurationWithName(cb func(ctx context.Co
 
 // Empty returns KnownTypesServiceHTTPService interface's Empty converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) Empty(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -518,8 +1051,35 @@ func (h *KnownTypesServiceHTTPConverter) Empty(cb func(ctx context.Context, w ht
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Empty", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/Empty",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -537,6 +1097,22 @@ func (h *KnownTypesServiceHTTPConverter) Empty(cb func(ctx context.Context, w ht
 
 		arg := &emptypb.Empty{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -587,6 +1163,8 @@ func (h *KnownTypesServiceHTTPConverter) Empty(cb func(ctx context.Context, w ht
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -605,6 +1183,11 @@ func (h *KnownTypesServiceHTTPConverter) Empty(cb func(ctx context.Context, w ht
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -615,6 +1198,11 @@ func (h *KnownTypesServiceHTTPConverter) Empty(cb func(ctx context.Context, w ht
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -636,11 +1224,28 @@ func (h *KnownTypesServiceHTTPConverter) EmptyWithName(cb func(ctx context.Conte
 
 // FieldMask returns KnownTypesServiceHTTPService interface's FieldMask converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) FieldMask(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -663,8 +1268,35 @@ func (h *KnownTypesServiceHTTPConverter) FieldMask(cb func(ctx context.Context,
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "FieldMask", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/FieldMask",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -682,6 +1314,22 @@ func (h *KnownTypesServiceHTTPConverter) FieldMask(cb func(ctx context.Context,
 
 		arg := &fieldmaskpb.FieldMask{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -732,6 +1380,8 @@ func (h *KnownTypesServiceHTTPConverter) FieldMask(cb func(ctx context.Context,
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -750,6 +1400,11 @@ func (h *KnownTypesServiceHTTPConverter) FieldMask(cb func(ctx context.Context,
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -760,6 +1415,11 @@ func (h *KnownTypesServiceHTTPConverter) FieldMask(cb func(ctx context.Context,
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -781,11 +1441,28 @@ func (h *KnownTypesServiceHTTPConverter) FieldMaskWithName(cb func(ctx context.C
 
 // SourceContext returns KnownTypesServiceHTTPService interface's SourceContext converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) SourceContext(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -808,8 +1485,35 @@ func (h *KnownTypesServiceHTTPConverter) SourceContext(cb func(ctx context.Conte
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "SourceContext", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/SourceContext",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -827,6 +1531,22 @@ func (h *KnownTypesServiceHTTPConverter) SourceContext(cb func(ctx context.Conte
 
 		arg := &sourcecontextpb.SourceContext{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -877,6 +1597,8 @@ func (h *KnownTypesServiceHTTPConverter) SourceContext(cb func(ctx context.Conte
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -895,6 +1617,11 @@ func (h *KnownTypesServiceHTTPConverter) SourceContext(cb func(ctx context.Conte
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -905,6 +1632,11 @@ func (h *KnownTypesServiceHTTPConverter) SourceContext(cb func(ctx context.Conte
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -926,11 +1658,28 @@ func (h *KnownTypesServiceHTTPConverter) SourceContextWithName(cb func(ctx conte
 
 // Struct returns KnownTypesServiceHTTPService interface's Struct converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) Struct(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -953,8 +1702,35 @@ func (h *KnownTypesServiceHTTPConverter) Struct(cb func(ctx context.Context, w h
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Struct", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/Struct",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -972,6 +1748,22 @@ func (h *KnownTypesServiceHTTPConverter) Struct(cb func(ctx context.Context, w h
 
 		arg := &status.Struct{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -1022,6 +1814,8 @@ func (h *KnownTypesServiceHTTPConverter) Struct(cb func(ctx context.Context, w h
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -1040,6 +1834,11 @@ func (h *KnownTypesServiceHTTPConverter) Struct(cb func(ctx context.Context, w h
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -1050,6 +1849,11 @@ func (h *KnownTypesServiceHTTPConverter) Struct(cb func(ctx context.Context, w h
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -1071,11 +1875,28 @@ func (h *KnownTypesServiceHTTPConverter) StructWithName(cb func(ctx context.Cont
 
 // Timestamp returns KnownTypesServiceHTTPService interface's Timestamp converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) Timestamp(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -1098,8 +1919,35 @@ func (h *KnownTypesServiceHTTPConverter) Timestamp(cb func(ctx context.Context,
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Timestamp", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/Timestamp",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -1117,12 +1965,28 @@ func (h *KnownTypesServiceHTTPConverter) Timestamp(cb func(ctx context.Context,
 
 		arg := &timestamppb.Timestamp{}
 		if r.Method != http.MethodGet {
-			body, err := ioutil.ReadAll(r.Body)
-			if err != nil {
-				cb(ctx, w, r, nil, nil, err)
-				return
-			}
-
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+
 			switch contentType {
 			case "application/protobuf", "application/x-protobuf":
 				if err := proto.Unmarshal(body, arg); err != nil {
@@ -1167,6 +2031,8 @@ func (h *KnownTypesServiceHTTPConverter) Timestamp(cb func(ctx context.Context,
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -1185,6 +2051,11 @@ func (h *KnownTypesServiceHTTPConverter) Timestamp(cb func(ctx context.Context,
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -1195,6 +2066,11 @@ func (h *KnownTypesServiceHTTPConverter) Timestamp(cb func(ctx context.Context,
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -1216,11 +2092,28 @@ func (h *KnownTypesServiceHTTPConverter) TimestampWithName(cb func(ctx context.C
 
 // Type returns KnownTypesServiceHTTPService interface's Type converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) Type(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -1243,8 +2136,35 @@ func (h *KnownTypesServiceHTTPConverter) Type(cb func(ctx context.Context, w htt
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Type", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/Type",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -1262,6 +2182,22 @@ func (h *KnownTypesServiceHTTPConverter) Type(cb func(ctx context.Context, w htt
 
 		arg := &typepb.Type{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -1312,6 +2248,8 @@ func (h *KnownTypesServiceHTTPConverter) Type(cb func(ctx context.Context, w htt
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -1330,6 +2268,11 @@ func (h *KnownTypesServiceHTTPConverter) Type(cb func(ctx context.Context, w htt
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -1340,6 +2283,11 @@ func (h *KnownTypesServiceHTTPConverter) Type(cb func(ctx context.Context, w htt
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -1361,11 +2309,28 @@ func (h *KnownTypesServiceHTTPConverter) TypeWithName(cb func(ctx context.Contex
 
 // Wrappers returns KnownTypesServiceHTTPService interface's Wrappers converted to http.HandlerFunc.
 func (h *KnownTypesServiceHTTPConverter) Wrappers(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) http.HandlerFunc {
+	if cb == nil {
+		cb = h.cb
+	}
 	if cb == nil {
 		cb = func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error) {
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				p := status.New(codes.Unknown, err.Error()).Proto()
+				st := status.Convert(err)
+				code := st.Code()
+				var maxBytesErr *http.MaxBytesError
+				switch {
+				case errors.As(err, &maxBytesErr):
+					code = codes.ResourceExhausted
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				case errors.Is(err, context.DeadlineExceeded), code == codes.DeadlineExceeded:
+					code = codes.DeadlineExceeded
+					w.WriteHeader(http.StatusGatewayTimeout)
+				case code == codes.InvalidArgument:
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				p := status.New(code, st.Message()).Proto()
 				switch contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType {
 				case "application/protobuf", "application/x-protobuf":
 					buf, err := proto.Marshal(p)
@@ -1388,8 +2353,35 @@ func (h *KnownTypesServiceHTTPConverter) Wrappers(cb func(ctx context.Context, w
 			}
 		}
 	}
+	interceptors = append(append([]grpc.UnaryServerInterceptor{}, h.interceptors...), interceptors...)
+	if h.recovery {
+		interceptors = append([]grpc.UnaryServerInterceptor{h.recoverUnary}, interceptors...)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		if md := file_knowntypes_knowntypes_proto_incomingMetadata(r.Header, h.incomingHeaders, h.incomingHeaderPrefix); len(md) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		if p := file_knowntypes_knowntypes_proto_requestPeer(r); p != nil {
+			ctx = peer.NewContext(ctx, p)
+		}
+		if timeout, ok := file_knowntypes_knowntypes_proto_timeout(r.Header, h.timeoutHeader); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		cb := cb
+		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {
+			ctx, w, cb = h.trace(ctx, w, r, "Wrappers", "", cb)
+		}
+		ts := &file_knowntypes_knowntypes_proto_httpTransportStream{
+			method:        "/knowntypes.KnownTypesService/Wrappers",
+			w:             w,
+			headers:       h.outgoingHeaders,
+			headerPrefix:  h.outgoingHeaderPrefix,
+			trailerPrefix: h.outgoingTrailerPrefix,
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, ts)
 
 		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 
@@ -1407,6 +2399,22 @@ func (h *KnownTypesServiceHTTPConverter) Wrappers(cb func(ctx context.Context, w
 
 		arg := &wrapperspb.BoolValue{}
 		if r.Method != http.MethodGet {
+			switch encoding := strings.ToLower(r.Header.Get("Content-Encoding")); encoding {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate":
+				if err := file_knowntypes_knowntypes_proto_decodeBody(r, encoding); err != nil {
+					cb(ctx, w, r, nil, nil, err)
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, err := fmt.Fprintf(w, "Unsupported Content-Encoding: %s", encoding)
+				cb(ctx, w, r, nil, nil, err)
+				return
+			}
+			if h.maxBodySize > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+			}
 			body, err := ioutil.ReadAll(r.Body)
 			if err != nil {
 				cb(ctx, w, r, nil, nil, err)
@@ -1457,6 +2465,8 @@ func (h *KnownTypesServiceHTTPConverter) Wrappers(cb func(ctx context.Context, w
 		}
 
 		iret, err := chained(ctx, arg, info, handler)
+		ts.writeHeader()
+		ts.writeTrailer(false)
 		if err != nil {
 			cb(ctx, w, r, arg, nil, err)
 			return
@@ -1475,6 +2485,11 @@ func (h *KnownTypesServiceHTTPConverter) Wrappers(cb func(ctx context.Context, w
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -1485,6 +2500,11 @@ func (h *KnownTypesServiceHTTPConverter) Wrappers(cb func(ctx context.Context, w
 				cb(ctx, w, r, arg, ret, err)
 				return
 			}
+			buf = file_knowntypes_knowntypes_proto_compress(w, r, buf, h.compressionMinSize)
+			if !ts.writeStatusCode() {
+				cb(ctx, w, r, arg, ret, nil)
+				return
+			}
 			if _, err := io.Copy(w, bytes.NewBuffer(buf)); err != nil {
 				cb(ctx, w, r, arg, ret, err)
 				return
@@ -1503,3 +2523,1117 @@ func (h *KnownTypesServiceHTTPConverter) Wrappers(cb func(ctx context.Context, w
 func (h *KnownTypesServiceHTTPConverter) WrappersWithName(cb func(ctx context.Context, w http.ResponseWriter, r *http.Request, arg, ret proto.Message, err error), interceptors ...grpc.UnaryServerInterceptor) (string, string, http.HandlerFunc) {
 	return "KnownTypesService", "Wrappers", h.Wrappers(cb, interceptors...)
 }
+
+// RegisterKnownTypesServiceHTTPHandlers registers all methods of KnownTypesServiceHTTPService on mux.
+// Methods with google.api.http option are registered with its HTTP method and path,
+// other methods are registered with POST /{package}.{Service}/{Method}.
+func RegisterKnownTypesServiceHTTPHandlers(mux *http.ServeMux, conv *KnownTypesServiceHTTPConverter) {
+	mux.Handle("POST /knowntypes.KnownTypesService/Any", conv.Any(nil))
+	mux.Handle("POST /knowntypes.KnownTypesService/Api", conv.Api(nil))
+	mux.Handle("POST /knowntypes.KnownTypesService/Duration", conv.Duration(nil))
+	mux.Handle("POST /knowntypes.KnownTypesService/Empty", conv.Empty(nil))
+	mux.Handle("POST /knowntypes.KnownTypesService/FieldMask", conv.FieldMask(nil))
+	mux.Handle("POST /knowntypes.KnownTypesService/SourceContext", conv.SourceContext(nil))
+	mux.Handle("POST /knowntypes.KnownTypesService/Struct", conv.Struct(nil))
+	mux.Handle("POST /knowntypes.KnownTypesService/Timestamp", conv.Timestamp(nil))
+	mux.Handle("POST /knowntypes.KnownTypesService/Type", conv.Type(nil))
+	mux.Handle("POST /knowntypes.KnownTypesService/Wrappers", conv.Wrappers(nil))
+}
+
+// KnownTypesServiceHTTPRoute is a route of KnownTypesServiceHTTPService method.
+type KnownTypesServiceHTTPRoute struct {
+	// Method is HTTP method of the route.
+	Method string
+	// Pattern is path template of google.api.http option, or /{package}.{Service}/{Method} if the option is not defined.
+	Pattern string
+	// Handler is KnownTypesServiceHTTPService method converted to http.HandlerFunc.
+	Handler http.HandlerFunc
+	// FullMethod is the full RPC method string, i.e., /package.service/method.
+	FullMethod string
+}
+
+// KnownTypesServiceHTTPRouter is the interface of HTTP routers that KnownTypesServiceHTTPRoute is registered on.
+type KnownTypesServiceHTTPRouter interface {
+	Handle(method, pattern string, handler http.Handler)
+}
+
+// KnownTypesServiceHTTPRouterFunc is an adapter to use a function as KnownTypesServiceHTTPRouter.
+type KnownTypesServiceHTTPRouterFunc func(method, pattern string, handler http.Handler)
+
+// Handle calls f(method, pattern, handler).
+func (f KnownTypesServiceHTTPRouterFunc) Handle(method, pattern string, handler http.Handler) {
+	f(method, pattern, handler)
+}
+
+// KnownTypesServiceHTTPRoutes returns routes of all methods of KnownTypesServiceHTTPService converted by conv.
+func KnownTypesServiceHTTPRoutes(conv *KnownTypesServiceHTTPConverter) []KnownTypesServiceHTTPRoute {
+	routes := make([]KnownTypesServiceHTTPRoute, 0)
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/Any",
+		Handler:    conv.Any(nil),
+		FullMethod: "/knowntypes.KnownTypesService/Any",
+	})
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/Api",
+		Handler:    conv.Api(nil),
+		FullMethod: "/knowntypes.KnownTypesService/Api",
+	})
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/Duration",
+		Handler:    conv.Duration(nil),
+		FullMethod: "/knowntypes.KnownTypesService/Duration",
+	})
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/Empty",
+		Handler:    conv.Empty(nil),
+		FullMethod: "/knowntypes.KnownTypesService/Empty",
+	})
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/FieldMask",
+		Handler:    conv.FieldMask(nil),
+		FullMethod: "/knowntypes.KnownTypesService/FieldMask",
+	})
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/SourceContext",
+		Handler:    conv.SourceContext(nil),
+		FullMethod: "/knowntypes.KnownTypesService/SourceContext",
+	})
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/Struct",
+		Handler:    conv.Struct(nil),
+		FullMethod: "/knowntypes.KnownTypesService/Struct",
+	})
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/Timestamp",
+		Handler:    conv.Timestamp(nil),
+		FullMethod: "/knowntypes.KnownTypesService/Timestamp",
+	})
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/Type",
+		Handler:    conv.Type(nil),
+		FullMethod: "/knowntypes.KnownTypesService/Type",
+	})
+	routes = append(routes, KnownTypesServiceHTTPRoute{
+		Method:     http.MethodPost,
+		Pattern:    "/knowntypes.KnownTypesService/Wrappers",
+		Handler:    conv.Wrappers(nil),
+		FullMethod: "/knowntypes.KnownTypesService/Wrappers",
+	})
+	return routes
+}
+
+// RegisterKnownTypesServiceHTTPRoutes registers all routes of KnownTypesServiceHTTPService on router.
+func RegisterKnownTypesServiceHTTPRoutes(router KnownTypesServiceHTTPRouter, conv *KnownTypesServiceHTTPConverter) {
+	for _, route := range KnownTypesServiceHTTPRoutes(conv) {
+		router.Handle(route.Method, route.Pattern, route.Handler)
+	}
+}
+
+// KnownTypesServiceHTTPRoutesHandler returns http.Handler writing HTTP method, pattern and full method name of all routes
+// of conv as JSON.
+func KnownTypesServiceHTTPRoutesHandler(conv *KnownTypesServiceHTTPConverter) http.Handler {
+	type route struct {
+		Method     string `json:"method"`
+		Pattern    string `json:"pattern"`
+		FullMethod string `json:"fullMethod"`
+	}
+	routes := KnownTypesServiceHTTPRoutes(conv)
+	rs := make([]route, 0, len(routes))
+	for _, r := range routes {
+		rs = append(rs, route{Method: r.Method, Pattern: r.Pattern, FullMethod: r.FullMethod})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(rs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// KnownTypesServiceHTTPOpenAPIHandler returns http.Handler serving OpenAPI document of knowntypes/knowntypes.proto.
+func KnownTypesServiceHTTPOpenAPIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(file_knowntypes_knowntypes_proto_openAPI)
+	})
+}
+
+// KnownTypesServiceHTTPClient is the client API for KnownTypesService service over HTTP.
+// Only unary methods are implemented.
+type KnownTypesServiceHTTPClient struct {
+	baseURL       string
+	client        *http.Client
+	contentType   string
+	interceptors  []grpc.UnaryClientInterceptor
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+}
+
+// KnownTypesServiceHTTPClientOption configures KnownTypesServiceHTTPClient.
+type KnownTypesServiceHTTPClientOption func(*KnownTypesServiceHTTPClient)
+
+// WithKnownTypesServiceHTTPClient sets http.Client used to send requests. http.DefaultClient is used by default.
+func WithKnownTypesServiceHTTPClient(client *http.Client) KnownTypesServiceHTTPClientOption {
+	return func(c *KnownTypesServiceHTTPClient) {
+		c.client = client
+	}
+}
+
+// WithKnownTypesServiceHTTPClientContentType sets the media type of requests and responses.
+// "application/json" (default), "application/protobuf" and "application/x-protobuf" are supported.
+func WithKnownTypesServiceHTTPClientContentType(contentType string) KnownTypesServiceHTTPClientOption {
+	return func(c *KnownTypesServiceHTTPClient) {
+		c.contentType = contentType
+	}
+}
+
+// WithKnownTypesServiceHTTPClientInterceptors appends interceptors executed in left-to-right order for each call.
+// The method passed to the interceptors is the full method name, e.g., "/knowntypes.KnownTypesService/Method", and cc is always nil.
+func WithKnownTypesServiceHTTPClientInterceptors(interceptors ...grpc.UnaryClientInterceptor) KnownTypesServiceHTTPClientOption {
+	return func(c *KnownTypesServiceHTTPClient) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithKnownTypesServiceHTTPClientHeaders sets the outgoing metadata keys sent as the request headers of the same names without the prefix.
+// The response headers of the names are also read as the header metadata.
+// The default is Authorization, the same as the incoming headers of the converter.
+func WithKnownTypesServiceHTTPClientHeaders(headers ...string) KnownTypesServiceHTTPClientOption {
+	return func(c *KnownTypesServiceHTTPClient) {
+		c.headers = headers
+	}
+}
+
+// WithKnownTypesServiceHTTPClientHeaderPrefix sets the prefix of the request headers sending the other outgoing metadata,
+// and of the response headers read as the header metadata.
+// The default is "Grpc-Metadata-", the same as the incoming and outgoing header prefixes of the converter.
+func WithKnownTypesServiceHTTPClientHeaderPrefix(prefix string) KnownTypesServiceHTTPClientOption {
+	return func(c *KnownTypesServiceHTTPClient) {
+		c.headerPrefix = prefix
+	}
+}
+
+// WithKnownTypesServiceHTTPClientTrailerPrefix sets the prefix of the response headers read as the trailer metadata.
+// The default is "Grpc-Trailer-", the same as the outgoing trailer prefix of the converter.
+func WithKnownTypesServiceHTTPClientTrailerPrefix(prefix string) KnownTypesServiceHTTPClientOption {
+	return func(c *KnownTypesServiceHTTPClient) {
+		c.trailerPrefix = prefix
+	}
+}
+
+// NewKnownTypesServiceHTTPClient returns KnownTypesServiceHTTPClient sending requests to baseURL, e.g., "http://localhost:8080".
+func NewKnownTypesServiceHTTPClient(baseURL string, opts ...KnownTypesServiceHTTPClientOption) *KnownTypesServiceHTTPClient {
+	c := &KnownTypesServiceHTTPClient{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		client:        http.DefaultClient,
+		contentType:   "application/json",
+		headers:       []string{"Authorization"},
+		headerPrefix:  "Grpc-Metadata-",
+		trailerPrefix: "Grpc-Trailer-",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Any calls Any with POST /knowntypes.KnownTypesService/Any.
+func (c *KnownTypesServiceHTTPClient) Any(ctx context.Context, in *anypb.Any) (*anypb.Any, error) {
+	out := &anypb.Any{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/Any", in, out, c.invokeAny); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeAny is the grpc.UnaryInvoker sending the request of Any.
+func (c *KnownTypesServiceHTTPClient) invokeAny(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*anypb.Any)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*anypb.Any)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/Any", nil, in, "", out, opts...)
+}
+
+// Api calls Api with POST /knowntypes.KnownTypesService/Api.
+func (c *KnownTypesServiceHTTPClient) Api(ctx context.Context, in *apipb.Api) (*apipb.Api, error) {
+	out := &apipb.Api{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/Api", in, out, c.invokeApi); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeApi is the grpc.UnaryInvoker sending the request of Api.
+func (c *KnownTypesServiceHTTPClient) invokeApi(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*apipb.Api)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*apipb.Api)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/Api", nil, in, "", out, opts...)
+}
+
+// Duration calls Duration with POST /knowntypes.KnownTypesService/Duration.
+func (c *KnownTypesServiceHTTPClient) Duration(ctx context.Context, in *durationpb.Duration) (*durationpb.Duration, error) {
+	out := &durationpb.Duration{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/Duration", in, out, c.invokeDuration); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeDuration is the grpc.UnaryInvoker sending the request of Duration.
+func (c *KnownTypesServiceHTTPClient) invokeDuration(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*durationpb.Duration)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*durationpb.Duration)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/Duration", nil, in, "", out, opts...)
+}
+
+// Empty calls Empty with POST /knowntypes.KnownTypesService/Empty.
+func (c *KnownTypesServiceHTTPClient) Empty(ctx context.Context, in *emptypb.Empty) (*emptypb.Empty, error) {
+	out := &emptypb.Empty{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/Empty", in, out, c.invokeEmpty); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeEmpty is the grpc.UnaryInvoker sending the request of Empty.
+func (c *KnownTypesServiceHTTPClient) invokeEmpty(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*emptypb.Empty)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*emptypb.Empty)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/Empty", nil, in, "", out, opts...)
+}
+
+// FieldMask calls FieldMask with POST /knowntypes.KnownTypesService/FieldMask.
+func (c *KnownTypesServiceHTTPClient) FieldMask(ctx context.Context, in *fieldmaskpb.FieldMask) (*fieldmaskpb.FieldMask, error) {
+	out := &fieldmaskpb.FieldMask{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/FieldMask", in, out, c.invokeFieldMask); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeFieldMask is the grpc.UnaryInvoker sending the request of FieldMask.
+func (c *KnownTypesServiceHTTPClient) invokeFieldMask(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*fieldmaskpb.FieldMask)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*fieldmaskpb.FieldMask)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/FieldMask", nil, in, "", out, opts...)
+}
+
+// SourceContext calls SourceContext with POST /knowntypes.KnownTypesService/SourceContext.
+func (c *KnownTypesServiceHTTPClient) SourceContext(ctx context.Context, in *sourcecontextpb.SourceContext) (*sourcecontextpb.SourceContext, error) {
+	out := &sourcecontextpb.SourceContext{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/SourceContext", in, out, c.invokeSourceContext); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeSourceContext is the grpc.UnaryInvoker sending the request of SourceContext.
+func (c *KnownTypesServiceHTTPClient) invokeSourceContext(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*sourcecontextpb.SourceContext)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*sourcecontextpb.SourceContext)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/SourceContext", nil, in, "", out, opts...)
+}
+
+// Struct calls Struct with POST /knowntypes.KnownTypesService/Struct.
+func (c *KnownTypesServiceHTTPClient) Struct(ctx context.Context, in *status.Struct) (*status.Struct, error) {
+	out := &status.Struct{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/Struct", in, out, c.invokeStruct); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeStruct is the grpc.UnaryInvoker sending the request of Struct.
+func (c *KnownTypesServiceHTTPClient) invokeStruct(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*status.Struct)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*status.Struct)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/Struct", nil, in, "", out, opts...)
+}
+
+// Timestamp calls Timestamp with POST /knowntypes.KnownTypesService/Timestamp.
+func (c *KnownTypesServiceHTTPClient) Timestamp(ctx context.Context, in *timestamppb.Timestamp) (*timestamppb.Timestamp, error) {
+	out := &timestamppb.Timestamp{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/Timestamp", in, out, c.invokeTimestamp); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeTimestamp is the grpc.UnaryInvoker sending the request of Timestamp.
+func (c *KnownTypesServiceHTTPClient) invokeTimestamp(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*timestamppb.Timestamp)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*timestamppb.Timestamp)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/Timestamp", nil, in, "", out, opts...)
+}
+
+// Type calls Type with POST /knowntypes.KnownTypesService/Type.
+func (c *KnownTypesServiceHTTPClient) Type(ctx context.Context, in *typepb.Type) (*typepb.Type, error) {
+	out := &typepb.Type{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/Type", in, out, c.invokeType); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeType is the grpc.UnaryInvoker sending the request of Type.
+func (c *KnownTypesServiceHTTPClient) invokeType(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*typepb.Type)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*typepb.Type)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/Type", nil, in, "", out, opts...)
+}
+
+// Wrappers calls Wrappers with POST /knowntypes.KnownTypesService/Wrappers.
+func (c *KnownTypesServiceHTTPClient) Wrappers(ctx context.Context, in *wrapperspb.BoolValue) (*wrapperspb.BoolValue, error) {
+	out := &wrapperspb.BoolValue{}
+	if err := c.invoke(ctx, "/knowntypes.KnownTypesService/Wrappers", in, out, c.invokeWrappers); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// invokeWrappers is the grpc.UnaryInvoker sending the request of Wrappers.
+func (c *KnownTypesServiceHTTPClient) invokeWrappers(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	in, ok := req.(*wrapperspb.BoolValue)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type: %T", req)
+	}
+	out, ok := reply.(*wrapperspb.BoolValue)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type: %T", reply)
+	}
+	return c.do(ctx, http.MethodPost, "/knowntypes.KnownTypesService/Wrappers", nil, in, "", out, opts...)
+}
+
+var _ KnownTypesServiceHTTPService = (*KnownTypesServiceHTTPClient)(nil)
+
+// KnownTypesServiceHTTPClientConn implements grpc.ClientConnInterface by KnownTypesServiceHTTPClient, so the client can be used as
+// the connection of gRPC clients, e.g., NewKnownTypesServiceClient(NewKnownTypesServiceHTTPClientConn(NewKnownTypesServiceHTTPClient(baseURL))).
+type KnownTypesServiceHTTPClientConn struct {
+	c *KnownTypesServiceHTTPClient
+}
+
+// NewKnownTypesServiceHTTPClientConn returns KnownTypesServiceHTTPClientConn sending the RPCs by c.
+func NewKnownTypesServiceHTTPClientConn(c *KnownTypesServiceHTTPClient) *KnownTypesServiceHTTPClientConn {
+	return &KnownTypesServiceHTTPClientConn{c: c}
+}
+
+// Invoke sends the unary RPC of method over HTTP. The HTTP method and path are decided by
+// google.api.http option of the method in the same way as the methods of KnownTypesServiceHTTPClient.
+// The response metadata is set to grpc.Header and grpc.Trailer of opts, and the other options are ignored.
+func (cc *KnownTypesServiceHTTPClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	switch method {
+	case "/knowntypes.KnownTypesService/Any":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeAny, opts...)
+	case "/knowntypes.KnownTypesService/Api":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeApi, opts...)
+	case "/knowntypes.KnownTypesService/Duration":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeDuration, opts...)
+	case "/knowntypes.KnownTypesService/Empty":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeEmpty, opts...)
+	case "/knowntypes.KnownTypesService/FieldMask":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeFieldMask, opts...)
+	case "/knowntypes.KnownTypesService/SourceContext":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeSourceContext, opts...)
+	case "/knowntypes.KnownTypesService/Struct":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeStruct, opts...)
+	case "/knowntypes.KnownTypesService/Timestamp":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeTimestamp, opts...)
+	case "/knowntypes.KnownTypesService/Type":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeType, opts...)
+	case "/knowntypes.KnownTypesService/Wrappers":
+		return cc.c.invoke(ctx, method, args, reply, cc.c.invokeWrappers, opts...)
+	}
+	return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by KnownTypesServiceHTTPClient.
+func (cc *KnownTypesServiceHTTPClientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streaming RPC %s is not supported over HTTP", method)
+}
+
+var _ grpc.ClientConnInterface = (*KnownTypesServiceHTTPClientConn)(nil)
+
+// Invoke is the same as Invoke of KnownTypesServiceHTTPClientConn. Invoke and NewStream implement grpc.ClientConnInterface,
+// so the client can be used as the connection of gRPC clients, e.g., NewKnownTypesServiceClient(NewKnownTypesServiceHTTPClient(baseURL)).
+func (c *KnownTypesServiceHTTPClient) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return NewKnownTypesServiceHTTPClientConn(c).Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream always returns an error because streaming RPCs are not supported by KnownTypesServiceHTTPClient.
+func (c *KnownTypesServiceHTTPClient) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return NewKnownTypesServiceHTTPClientConn(c).NewStream(ctx, desc, method, opts...)
+}
+
+var _ grpc.ClientConnInterface = (*KnownTypesServiceHTTPClient)(nil)
+
+// invoke calls invoker through the interceptors.
+func (c *KnownTypesServiceHTTPClient) invoke(ctx context.Context, method string, req, reply interface{}, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	chainer := func(currentInter grpc.UnaryClientInterceptor, currentInvoker grpc.UnaryInvoker) grpc.UnaryInvoker {
+		return func(currentCtx context.Context, currentMethod string, currentReq, currentReply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return currentInter(currentCtx, currentMethod, currentReq, currentReply, cc, currentInvoker, opts...)
+		}
+	}
+	chainedInvoker := invoker
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		chainedInvoker = chainer(c.interceptors[i], chainedInvoker)
+	}
+	return chainedInvoker(ctx, method, req, reply, nil, opts...)
+}
+
+// do sends in, or the field of in if field is not empty, as the request body unless in is nil,
+// and unmarshals the response into out. The response metadata is set to grpc.Header and grpc.Trailer of opts.
+func (c *KnownTypesServiceHTTPClient) do(ctx context.Context, method, path string, query url.Values, in proto.Message, field protoreflect.Name, out proto.Message, opts ...grpc.CallOption) error {
+	u := c.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		buf, err := c.marshal(in, field)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	// Content-Type is also set to GET requests because the converter encodes errors by it.
+	req.Header.Set("Content-Type", c.contentType)
+	req.Header.Set("Accept", c.contentType)
+	// Outgoing metadata set by interceptors is sent as headers which the converter reads as incoming metadata.
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for k, vs := range md {
+		key := c.headerPrefix + k
+		for _, h := range c.headers {
+			if strings.EqualFold(h, k) {
+				key = k
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			req.Header.Add(key, v)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	c.setMetadata(resp.Header, opts)
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return knownTypesServiceHTTPClientError(resp.StatusCode, contentType, buf)
+	}
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		return proto.Unmarshal(buf, out)
+	default:
+		return protojson.Unmarshal(buf, out)
+	}
+}
+
+// setMetadata sets the metadata read from the response headers to grpc.Header and grpc.Trailer of opts.
+// Values of the keys ending with "-bin" are decoded from base64 as gRPC does. The other options are ignored.
+func (c *KnownTypesServiceHTTPClient) setMetadata(header http.Header, opts []grpc.CallOption) {
+	headerMD, trailerMD := metadata.MD{}, metadata.MD{}
+	add := func(md metadata.MD, key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+	hasPrefix := func(key, prefix string) bool {
+		return prefix != "" && len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix)
+	}
+
+	for _, key := range c.headers {
+		add(headerMD, key, header.Values(key))
+	}
+	for key, values := range header {
+		switch {
+		case hasPrefix(key, c.trailerPrefix):
+			add(trailerMD, key[len(c.trailerPrefix):], values)
+		case hasPrefix(key, c.headerPrefix):
+			add(headerMD, key[len(c.headerPrefix):], values)
+		}
+	}
+
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			*o.HeaderAddr = headerMD
+		case grpc.TrailerCallOption:
+			*o.TrailerAddr = trailerMD
+		}
+	}
+}
+
+// marshal marshals in, or the field of in if field is not empty, in the content type of the client.
+// Only message fields can be marshaled in protobuf.
+func (c *KnownTypesServiceHTTPClient) marshal(in proto.Message, field protoreflect.Name) ([]byte, error) {
+	protobuf := c.contentType == "application/protobuf" || c.contentType == "application/x-protobuf"
+	if field == "" {
+		if protobuf {
+			return proto.Marshal(in)
+		}
+		return protojson.Marshal(in)
+	}
+
+	m := in.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName(field)
+	if protobuf {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, status.Errorf(codes.Unimplemented, "field %s cannot be sent in %s", field, c.contentType)
+		}
+		return proto.Marshal(m.Get(fd).Message().Interface())
+	}
+	// The JSON of the field is taken from the message which has only the field.
+	only := m.New()
+	if m.Has(fd) {
+		only.Set(fd, m.Get(fd))
+	}
+	buf, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(only.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return nil, err
+	}
+	return fields[fd.JSONName()], nil
+}
+
+// knownTypesServiceHTTPClientError converts the error response to the error of grpc/status.
+// If the body is not google.rpc.Status, the code is decided by the HTTP status code as gRPC clients do.
+func knownTypesServiceHTTPClientError(code int, contentType string, body []byte) error {
+	p := status.New(codes.Unknown, "").Proto()
+	var err error
+	switch contentType {
+	case "application/protobuf", "application/x-protobuf":
+		err = proto.Unmarshal(body, p)
+	case "application/json":
+		err = protojson.Unmarshal(body, p)
+	default:
+		err = fmt.Errorf("unexpected Content-Type: %s", contentType)
+	}
+	if err == nil && codes.Code(p.GetCode()) != codes.OK {
+		return status.ErrorProto(p)
+	}
+
+	c := codes.Unknown
+	switch code {
+	case http.StatusBadRequest:
+		c = codes.Internal
+	case http.StatusUnauthorized:
+		c = codes.Unauthenticated
+	case http.StatusForbidden:
+		c = codes.PermissionDenied
+	case http.StatusNotFound:
+		c = codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		c = codes.Unavailable
+	}
+	return status.Errorf(c, "unexpected HTTP status code %d: %s", code, body)
+}
+
+// file_knowntypes_knowntypes_proto_incomingMetadata returns the metadata built from the headers listed in headers and the headers with prefix.
+// The empty prefix disables it. Values of the keys ending with "-bin" are decoded from base64 as gRPC does.
+func file_knowntypes_knowntypes_proto_incomingMetadata(header http.Header, headers []string, prefix string) metadata.MD {
+	md := metadata.MD{}
+	add := func(key string, values []string) {
+		key = strings.ToLower(key)
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				enc := base64.StdEncoding
+				if len(v)%4 != 0 {
+					enc = base64.RawStdEncoding
+				}
+				b, err := enc.DecodeString(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			md.Append(key, v)
+		}
+	}
+
+	for _, key := range headers {
+		add(key, header.Values(key))
+	}
+	if prefix != "" {
+		for key, values := range header {
+			if len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+				add(key[len(prefix):], values)
+			}
+		}
+	}
+	return md
+}
+
+// file_knowntypes_knowntypes_proto_httpTransportStream implements grpc.ServerTransportStream to write header and trailer metadata set by the RPC
+// as HTTP headers, so that grpc.SetHeader, grpc.SendHeader and grpc.SetTrailer work in the RPC.
+type file_knowntypes_knowntypes_proto_httpTransportStream struct {
+	method        string
+	w             http.ResponseWriter
+	headers       []string
+	headerPrefix  string
+	trailerPrefix string
+
+	mu         sync.Mutex
+	header     metadata.MD
+	trailer    metadata.MD
+	headerSent bool
+}
+
+func (s *file_knowntypes_knowntypes_proto_httpTransportStream) Method() string {
+	return s.method
+}
+
+// SetHeader sets the header metadata written before the response body.
+func (s *file_knowntypes_knowntypes_proto_httpTransportStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader sets the header metadata and writes it to the response headers.
+// The headers are sent to the client with the response body.
+func (s *file_knowntypes_knowntypes_proto_httpTransportStream) SendHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.headerSent {
+		return status.Error(codes.Internal, "the header has already been sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	s.writeHeaderLocked()
+	return nil
+}
+
+// SetTrailer sets the trailer metadata written after the RPC returns.
+func (s *file_knowntypes_knowntypes_proto_httpTransportStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// writeHeader writes the header metadata to the response headers unless it has been written.
+func (s *file_knowntypes_knowntypes_proto_httpTransportStream) writeHeader() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeHeaderLocked()
+}
+
+func (s *file_knowntypes_knowntypes_proto_httpTransportStream) writeHeaderLocked() {
+	if s.headerSent {
+		return
+	}
+	s.headerSent = true
+	s.add(s.headerPrefix, s.header, s.headers)
+}
+
+// writeTrailer writes the trailer metadata as HTTP trailers if asTrailer is true.
+// Otherwise, it is written to the response headers because the response body has not been written yet.
+func (s *file_knowntypes_knowntypes_proto_httpTransportStream) writeTrailer(asTrailer bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if asTrailer {
+		s.add(http.TrailerPrefix+s.trailerPrefix, s.trailer, nil)
+		return
+	}
+	s.add(s.trailerPrefix, s.trailer, nil)
+}
+
+// writeStatusCode writes the status code set by the header metadata "x-http-code" and reports whether
+// the response body is allowed. The status code is ignored unless it is between 200 and 599.
+func (s *file_knowntypes_knowntypes_proto_httpTransportStream) writeStatusCode() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vs := s.header.Get("x-http-code")
+	if len(vs) == 0 {
+		return true
+	}
+	code, err := strconv.Atoi(vs[len(vs)-1])
+	if err != nil || code < 200 || code > 599 {
+		return true
+	}
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		s.w.Header().Del("Content-Type")
+		s.w.Header().Del("Content-Encoding")
+		s.w.WriteHeader(code)
+		return false
+	}
+	s.w.WriteHeader(code)
+	return true
+}
+
+// add adds md to the response headers with the prefix. The keys listed in headers are added without the prefix.
+// Values of the keys ending with "-bin" are encoded in base64 as gRPC does.
+func (s *file_knowntypes_knowntypes_proto_httpTransportStream) add(prefix string, md metadata.MD, headers []string) {
+	for k, vs := range md {
+		if k == "x-http-code" {
+			continue
+		}
+		name := prefix + k
+		for _, header := range headers {
+			if strings.EqualFold(header, k) {
+				name = header
+				break
+			}
+		}
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			s.w.Header().Add(name, v)
+		}
+	}
+}
+
+// file_knowntypes_knowntypes_proto_timeout returns the timeout of the RPC set by Grpc-Timeout header or the header named name,
+// whose value is parsed by time.ParseDuration. The shorter one is used if both are set. Invalid values are ignored.
+func file_knowntypes_knowntypes_proto_timeout(header http.Header, name string) (time.Duration, bool) {
+	timeout, ok := file_knowntypes_knowntypes_proto_parseGRPCTimeout(header.Get("Grpc-Timeout"))
+	if name == "" {
+		return timeout, ok
+	}
+	v := header.Get(name)
+	if v == "" {
+		return timeout, ok
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return timeout, ok
+	}
+	if !ok || d < timeout {
+		return d, true
+	}
+	return timeout, true
+}
+
+// file_knowntypes_knowntypes_proto_parseGRPCTimeout parses v in the format of Grpc-Timeout header, that is at most 8 digits followed by the unit,
+// H (hours), M (minutes), S (seconds), m (milliseconds), u (microseconds) or n (nanoseconds).
+func file_knowntypes_knowntypes_proto_parseGRPCTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 || len(v) > 9 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch v[len(v)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	n, err := strconv.ParseUint(v[:len(v)-1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	if time.Duration(n) > math.MaxInt64/unit {
+		return math.MaxInt64, true
+	}
+	return time.Duration(n) * unit, true
+}
+
+// file_knowntypes_knowntypes_proto_requestPeer returns the peer of the RPC built from the remote address and the TLS connection state of r.
+// It returns nil if the remote address is not an IP address and port.
+func file_knowntypes_knowntypes_proto_requestPeer(r *http.Request) *peer.Peer {
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return nil
+	}
+	p := &peer.Peer{
+		Addr: &net.TCPAddr{IP: ip, Port: n},
+	}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{
+			State: *r.TLS,
+			CommonAuthInfo: credentials.CommonAuthInfo{
+				SecurityLevel: credentials.PrivacyAndIntegrity,
+			},
+		}
+	}
+	return p
+}
+
+// file_knowntypes_knowntypes_proto_httpRequestBody counts the bytes read from the request body.
+type file_knowntypes_knowntypes_proto_httpRequestBody struct {
+	io.ReadCloser
+	size int64
+}
+
+func (b *file_knowntypes_knowntypes_proto_httpRequestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+// file_knowntypes_knowntypes_proto_httpResponseWriter records the status code and the size of the response written to http.ResponseWriter.
+type file_knowntypes_knowntypes_proto_httpResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int64
+}
+
+func (w *file_knowntypes_knowntypes_proto_httpResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *file_knowntypes_knowntypes_proto_httpResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher to flush streaming responses.
+func (w *file_knowntypes_knowntypes_proto_httpResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter for http.ResponseController.
+func (w *file_knowntypes_knowntypes_proto_httpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// status returns the status code of the response. It is 200 if nothing has been written as net/http does.
+func (w *file_knowntypes_knowntypes_proto_httpResponseWriter) status() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+	return w.statusCode
+}
+
+// file_knowntypes_knowntypes_proto_redact returns m marshaled in JSON without the fields marked with debug_redact option.
+func file_knowntypes_knowntypes_proto_redact(m proto.Message) string {
+	buf, err := protojson.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(buf)
+}
+
+// file_knowntypes_knowntypes_proto_decodeBody replaces the body of r with the reader decoding encoding, which is gzip, x-gzip or deflate.
+// A malformed body fails with codes.InvalidArgument.
+func file_knowntypes_knowntypes_proto_decodeBody(r *http.Request, encoding string) error {
+	var zr io.ReadCloser
+	var err error
+	if encoding == "deflate" {
+		zr, err = zlib.NewReader(r.Body)
+	} else {
+		zr, err = gzip.NewReader(r.Body)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", encoding, err)
+	}
+	r.Body = &file_knowntypes_knowntypes_proto_decodedBody{ReadCloser: zr, encoding: encoding}
+	return nil
+}
+
+// file_knowntypes_knowntypes_proto_decodedBody is the request body decoded by Content-Encoding header.
+// Reading it fails with codes.InvalidArgument if the rest of the body turns out to be malformed.
+type file_knowntypes_knowntypes_proto_decodedBody struct {
+	io.ReadCloser
+	encoding string
+}
+
+func (b *file_knowntypes_knowntypes_proto_decodedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = status.Errorf(codes.InvalidArgument, "invalid %s request body: %v", b.encoding, err)
+	}
+	return n, err
+}
+
+// file_knowntypes_knowntypes_proto_compress returns buf compressed by the content coding negotiated by Accept-Encoding header of r
+// if buf is at least minSize bytes, or identity is not accepted. A negative minSize disables the compression.
+// Otherwise, it returns buf as is.
+func file_knowntypes_knowntypes_proto_compress(w http.ResponseWriter, r *http.Request, buf []byte, minSize int) []byte {
+	if minSize < 0 {
+		return buf
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	encoding, identity := file_knowntypes_knowntypes_proto_acceptEncoding(r)
+	if identity && len(buf) < minSize {
+		return buf
+	}
+	var b bytes.Buffer
+	var zw io.WriteCloser
+	switch encoding {
+	case "gzip":
+		zw = gzip.NewWriter(&b)
+	case "deflate":
+		zw = zlib.NewWriter(&b)
+	default:
+		return buf
+	}
+	if _, err := zw.Write(buf); err != nil {
+		return buf
+	}
+	if err := zw.Close(); err != nil {
+		return buf
+	}
+	w.Header().Set("Content-Encoding", encoding)
+	return b.Bytes()
+}
+
+// file_knowntypes_knowntypes_proto_acceptEncoding returns gzip or deflate accepted by Accept-Encoding header of r. gzip is preferred to deflate.
+// It returns the empty string if neither is accepted. Codings with q=0 are not accepted, and * applies to the codings
+// not listed in the header. identity reports whether the response may be sent without any coding.
+func file_knowntypes_knowntypes_proto_acceptEncoding(r *http.Request) (encoding string, identity bool) {
+	qs := make(map[string]float64)
+	for _, v := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(v, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		if coding == "x-gzip" {
+			coding = "gzip"
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		qs[coding] = q
+	}
+	accepted := func(coding string) bool {
+		if q, ok := qs[coding]; ok {
+			return q > 0
+		}
+		if q, ok := qs["*"]; ok {
+			return q > 0
+		}
+		// identity is acceptable unless it is excluded explicitly.
+		return coding == "identity"
+	}
+
+	switch {
+	case accepted("gzip"):
+		encoding = "gzip"
+	case accepted("deflate"):
+		encoding = "deflate"
+	}
+	return encoding, accepted("identity")
+}
+
+//go:embed knowntypes.openapi.json
+var file_knowntypes_knowntypes_proto_openAPI []byte
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// genRequestTimeout generates the code bounding ctx by the timeout sent by the client.
+func genRequestTimeout(g *protogen.GeneratedFile, file protoreflect.FileDescriptor) {
+	g.P("		if timeout, ok := ", runtimeName(file, "timeout"), "(r.Header, h.timeoutHeader); ok {")
+	g.P("			var cancel ", contextPackage.Ident("CancelFunc"))
+	g.P("			ctx, cancel = ", contextPackage.Ident("WithTimeout"), "(ctx, timeout)")
+	g.P("			defer cancel()")
+	g.P("		}")
+}
+
+// genTimeoutOptions generates the option of the request header of the timeout.
+func genTimeoutOptions(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// With", srv.GoName, "HTTPTimeoutHeader sets the request header of the timeout of the RPC in addition to Grpc-Timeout header.")
+	g.P("// The value is parsed by time.ParseDuration, e.g. \"1.5s\". The empty name disables it, which is the default.")
+	g.P("func With", srv.GoName, "HTTPTimeoutHeader(name string) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.timeoutHeader = name")
+	g.P("	}")
+	g.P("}")
+}
+
+// genTimeout generates the functions parsing the timeout of the RPC from the request headers.
+func genTimeout(g *protogen.GeneratedFile, file *protogen.File) {
+	name := runtimeName(file.Desc, "timeout")
+	parse := runtimeName(file.Desc, "parseGRPCTimeout")
+	g.P("// ", name, " returns the timeout of the RPC set by Grpc-Timeout header or the header named name,")
+	g.P("// whose value is parsed by time.ParseDuration. The shorter one is used if both are set. Invalid values are ignored.")
+	g.P("func ", name, "(header ", httpPackage.Ident("Header"), ", name string) (", timePackage.Ident("Duration"), ", bool) {")
+	g.P("	timeout, ok := ", parse, "(header.Get(\"Grpc-Timeout\"))")
+	g.P("	if name == \"\" {")
+	g.P("		return timeout, ok")
+	g.P("	}")
+	g.P("	v := header.Get(name)")
+	g.P("	if v == \"\" {")
+	g.P("		return timeout, ok")
+	g.P("	}")
+	g.P("	d, err := ", timePackage.Ident("ParseDuration"), "(v)")
+	g.P("	if err != nil || d < 0 {")
+	g.P("		return timeout, ok")
+	g.P("	}")
+	g.P("	if !ok || d < timeout {")
+	g.P("		return d, true")
+	g.P("	}")
+	g.P("	return timeout, true")
+	g.P("}")
+	g.P()
+	g.P("// ", parse, " parses v in the format of Grpc-Timeout header, that is at most 8 digits followed by the unit,")
+	g.P("// H (hours), M (minutes), S (seconds), m (milliseconds), u (microseconds) or n (nanoseconds).")
+	g.P("func ", parse, "(v string) (", timePackage.Ident("Duration"), ", bool) {")
+	g.P("	if len(v) < 2 || len(v) > 9 {")
+	g.P("		return 0, false")
+	g.P("	}")
+	g.P("	var unit ", timePackage.Ident("Duration"))
+	g.P("	switch v[len(v)-1] {")
+	g.P("	case 'H':")
+	g.P("		unit = ", timePackage.Ident("Hour"))
+	g.P("	case 'M':")
+	g.P("		unit = ", timePackage.Ident("Minute"))
+	g.P("	case 'S':")
+	g.P("		unit = ", timePackage.Ident("Second"))
+	g.P("	case 'm':")
+	g.P("		unit = ", timePackage.Ident("Millisecond"))
+	g.P("	case 'u':")
+	g.P("		unit = ", timePackage.Ident("Microsecond"))
+	g.P("	case 'n':")
+	g.P("		unit = ", timePackage.Ident("Nanosecond"))
+	g.P("	default:")
+	g.P("		return 0, false")
+	g.P("	}")
+	g.P("	n, err := ", strconvPackage.Ident("ParseUint"), "(v[:len(v)-1], 10, 32)")
+	g.P("	if err != nil {")
+	g.P("		return 0, false")
+	g.P("	}")
+	g.P("	if ", timePackage.Ident("Duration"), "(n) > ", mathPackage.Ident("MaxInt64"), "/unit {")
+	g.P("		return ", mathPackage.Ident("MaxInt64"), ", true")
+	g.P("	}")
+	g.P("	return ", timePackage.Ident("Duration"), "(n) * unit, true")
+	g.P("}")
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// requestBodyName returns the name of the request body wrapper counting the bytes read shared by the services of the file.
+func requestBodyName(file protoreflect.FileDescriptor) string {
+	return runtimeName(file, "httpRequestBody")
+}
+
+// responseWriterName returns the name of http.ResponseWriter wrapper recording the response shared by the services of the file.
+func responseWriterName(file protoreflect.FileDescriptor) string {
+	return runtimeName(file, "httpResponseWriter")
+}
+
+// genTracerInterface generates the hook interface called at the start and the finish of every request.
+// The parameters consist of the types of the standard library, protobuf and grpc only,
+// so a single adapter of a tracing library implements the interfaces of all services.
+func genTracerInterface(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// ", srv.GoName, "HTTPTracer is called at the start and the finish of every request handled by ", srv.GoName, "HTTPConverter,")
+	g.P("// e.g. to start and end a span of a tracing library without depending on it.")
+	g.P("type ", srv.GoName, "HTTPTracer interface {")
+	g.P("	// OnStart is called when the handler of method starts handling r. route is the path template of google.api.http option,")
+	g.P("	// or empty if the handler is not returned by the HTTPRule method. The returned context is passed to the RPC and OnFinish.")
+	g.P("	OnStart(ctx ", contextPackage.Ident("Context"), ", method ", protoreflectPackage.Ident("MethodDescriptor"), ", route string, r *", httpPackage.Ident("Request"), ") ", contextPackage.Ident("Context"))
+	g.P("	// OnFinish is called after the callback with the arguments passed to the callback, the status code of the response")
+	g.P("	// and the time elapsed since OnStart. The gRPC code of the RPC is status.Code(err).")
+	g.P("	OnFinish(ctx ", contextPackage.Ident("Context"), ", method ", protoreflectPackage.Ident("MethodDescriptor"), ", arg, ret ", protoPackage.Ident("Message"), ", statusCode int, err error, elapsed ", timePackage.Ident("Duration"), ")")
+	g.P("}")
+}
+
+// genObserverInterface generates the metrics hook interface called at the finish of every request.
+func genObserverInterface(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// ", srv.GoName, "HTTPObserver observes the metrics of every request handled by ", srv.GoName, "HTTPConverter,")
+	g.P("// e.g. to export the latency, the sizes and the status of RPCs to a metrics library without depending on it.")
+	g.P("type ", srv.GoName, "HTTPObserver interface {")
+	g.P("	// Observe is called after the callback with the method, the path template of google.api.http option")
+	g.P("	// (empty if the handler is not returned by the HTTPRule method), the status code of the response,")
+	g.P("	// the gRPC code of the RPC, the bytes read from the request body and written to the response body, and the latency.")
+	g.P("	Observe(ctx ", contextPackage.Ident("Context"), ", method ", protoreflectPackage.Ident("MethodDescriptor"), ", route string, statusCode int, code ", codesPackage.Ident("Code"), ", requestSize, responseSize int64, elapsed ", timePackage.Ident("Duration"), ")")
+	g.P("}")
+}
+
+// genTracerOptions generates the options setting the tracer and the observer.
+func genTracerOptions(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// With", srv.GoName, "HTTPTracer sets the tracer called at the start and the finish of every request.")
+	g.P("func With", srv.GoName, "HTTPTracer(tracer ", srv.GoName, "HTTPTracer) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.tracer = tracer")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// With", srv.GoName, "HTTPObserver sets the observer called at the finish of every request.")
+	g.P("func With", srv.GoName, "HTTPObserver(observer ", srv.GoName, "HTTPObserver) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.observer = observer")
+	g.P("	}")
+	g.P("}")
+}
+
+// genRequestTrace generates the code starting the trace of the request if the tracer, the observer or the access logger is set.
+// ctx, w and cb are replaced with the ones returned by OnStart, recording the response and calling the hooks.
+func genRequestTrace(g *protogen.GeneratedFile, method *protogen.Method, route string) {
+	g.P("		cb := cb")
+	g.P("		if h.tracer != nil || h.observer != nil || h.accessLogger != nil {")
+	g.P("			ctx, w, cb = h.trace(ctx, w, r, \"", method.Desc.Name(), "\", \"", route, "\", cb)")
+	g.P("		}")
+}
+
+// genTracer generates the method starting the trace.
+func genTracer(g *protogen.GeneratedFile, file *protogen.File, srv *protogen.Service) {
+	g.P("// trace calls OnStart of the tracer, and returns the context returned by OnStart, w recording the response")
+	g.P("// and cb calling OnFinish of the tracer, Observe of the observer and the access logger after cb.")
+	g.P("// The body of r is replaced to count the bytes read.")
+	g.P("func (h *", srv.GoName, "HTTPConverter) trace(ctx ", contextPackage.Ident("Context"), ", w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ", name ", protoreflectPackage.Ident("Name"), ", route string, cb ", callbackSignature(g), ") (", contextPackage.Ident("Context"), ", ", httpPackage.Ident("ResponseWriter"), ", ", callbackSignature(g), ") {")
+	g.P("	method := ", file.GoDescriptorIdent, ".Services().ByName(\"", srv.Desc.Name(), "\").Methods().ByName(name)")
+	g.P("	start := ", timePackage.Ident("Now"), "()")
+	g.P("	if h.tracer != nil {")
+	g.P("		ctx = h.tracer.OnStart(ctx, method, route, r)")
+	g.P("	}")
+	g.P("	rw := &", responseWriterName(file.Desc), "{ResponseWriter: w}")
+	g.P("	body := &", requestBodyName(file.Desc), "{ReadCloser: r.Body}")
+	g.P("	r.Body = body")
+	g.P("	return ctx, rw, ", callbackSignature(g), " {")
+	g.P("		cb(ctx, w, r, arg, ret, err)")
+	g.P("		elapsed := ", timePackage.Ident("Since"), "(start)")
+	g.P("		if h.tracer != nil {")
+	g.P("			h.tracer.OnFinish(ctx, method, arg, ret, rw.status(), err, elapsed)")
+	g.P("		}")
+	g.P("		code := ", statusPackage.Ident("Code"), "(err)")
+	g.P("		switch {")
+	g.P("		case code != ", codesPackage.Ident("Unknown"), ":")
+	g.P("		case ", errorsPackage.Ident("Is"), "(err, ", contextPackage.Ident("DeadlineExceeded"), "):")
+	g.P("			code = ", codesPackage.Ident("DeadlineExceeded"))
+	g.P("		case ", errorsPackage.Ident("Is"), "(err, ", contextPackage.Ident("Canceled"), "):")
+	g.P("			code = ", codesPackage.Ident("Canceled"))
+	g.P("		}")
+	g.P("		if h.observer != nil {")
+	g.P("			h.observer.Observe(ctx, method, route, rw.status(), code, body.size, rw.size, elapsed)")
+	g.P("		}")
+	g.P("		if h.accessLogger != nil {")
+	g.P("			h.logAccess(ctx, r, method, route, rw.status(), code, err, elapsed, arg, ret)")
+	g.P("		}")
+	g.P("	}")
+	g.P("}")
+}
+
+// genRequestBody generates the request body wrapper counting the bytes read for the observer.
+func genRequestBody(g *protogen.GeneratedFile, file *protogen.File) {
+	body := requestBodyName(file.Desc)
+	g.P("// ", body, " counts the bytes read from the request body.")
+	g.P("type ", body, " struct {")
+	g.P("	", ioPackage.Ident("ReadCloser"))
+	g.P("	size int64")
+	g.P("}")
+	g.P()
+	g.P("func (b *", body, ") Read(p []byte) (int, error) {")
+	g.P("	n, err := b.ReadCloser.Read(p)")
+	g.P("	b.size += int64(n)")
+	g.P("	return n, err")
+	g.P("}")
+}
+
+// genResponseWriter generates http.ResponseWriter wrapper recording the response.
+// It implements http.Hijacker only if the file has WebSocket handlers.
+func genResponseWriter(g *protogen.GeneratedFile, file *protogen.File) {
+	rw := responseWriterName(file.Desc)
+	g.P("// ", rw, " records the status code and the size of the response written to http.ResponseWriter.")
+	g.P("type ", rw, " struct {")
+	g.P("	", httpPackage.Ident("ResponseWriter"))
+	g.P("	statusCode int")
+	g.P("	size       int64")
+	g.P("}")
+	g.P()
+	g.P("func (w *", rw, ") WriteHeader(statusCode int) {")
+	g.P("	if w.statusCode == 0 {")
+	g.P("		w.statusCode = statusCode")
+	g.P("	}")
+	g.P("	w.ResponseWriter.WriteHeader(statusCode)")
+	g.P("}")
+	g.P()
+	g.P("func (w *", rw, ") Write(b []byte) (int, error) {")
+	g.P("	if w.statusCode == 0 {")
+	g.P("		w.statusCode = ", httpPackage.Ident("StatusOK"))
+	g.P("	}")
+	g.P("	n, err := w.ResponseWriter.Write(b)")
+	g.P("	w.size += int64(n)")
+	g.P("	return n, err")
+	g.P("}")
+	g.P()
+	g.P("// Flush implements http.Flusher to flush streaming responses.")
+	g.P("func (w *", rw, ") Flush() {")
+	g.P("	if f, ok := w.ResponseWriter.(", httpPackage.Ident("Flusher"), "); ok {")
+	g.P("		f.Flush()")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	if hasWebSocketFile(file) {
+		g.P("// Hijack implements http.Hijacker for WebSocket.")
+		g.P("func (w *", rw, ") Hijack() (", netPackage.Ident("Conn"), ", *", bufioPackage.Ident("ReadWriter"), ", error) {")
+		g.P("	hj, ok := w.ResponseWriter.(", httpPackage.Ident("Hijacker"), ")")
+		g.P("	if !ok {")
+		g.P("		return nil, nil, ", fmtPackage.Ident("Errorf"), "(\"%T does not implement http.Hijacker\", w.ResponseWriter)")
+		g.P("	}")
+		g.P("	conn, brw, err := hj.Hijack()")
+		g.P("	if err == nil && w.statusCode == 0 {")
+		g.P("		w.statusCode = ", httpPackage.Ident("StatusSwitchingProtocols"))
+		g.P("	}")
+		g.P("	return conn, brw, err")
+		g.P("}")
+		g.P()
+	}
+	g.P("// Unwrap returns the original http.ResponseWriter for http.ResponseController.")
+	g.P("func (w *", rw, ") Unwrap() ", httpPackage.Ident("ResponseWriter"), " {")
+	g.P("	return w.ResponseWriter")
+	g.P("}")
+	g.P()
+	g.P("// status returns the status code of the response. It is 200 if nothing has been written as net/http does.")
+	g.P("func (w *", rw, ") status() int {")
+	g.P("	if w.statusCode == 0 {")
+	g.P("		return ", httpPackage.Ident("StatusOK"))
+	g.P("	}")
+	g.P("	return w.statusCode")
+	g.P("}")
+}
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// webSocketGUID is the GUID used to compute Sec-WebSocket-Accept defined in RFC 6455.
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// hasWebSocketMethod reports whether the service has bidirectional streaming methods converted to WebSocket handlers.
+func hasWebSocketMethod(srv *protogen.Service) bool {
+	for _, method := range srv.Methods {
+		if isGeneratedMethod(method) && isBidiStreaming(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWebSocketFile reports whether the file has bidirectional streaming methods converted to WebSocket handlers.
+func hasWebSocketFile(file *protogen.File) bool {
+	for _, srv := range file.Services {
+		if hasWebSocketMethod(srv) {
+			return true
+		}
+	}
+	return false
+}
+
+// webSocketStreamName returns the name of grpc.ServerStream implementation on WebSocket shared by the services of the file.
+func webSocketStreamName(file protoreflect.FileDescriptor) string {
+	return runtimeName(file, "httpWebSocketStream")
+}
+
+// genWebSocketStream generates a minimal WebSocket server implementation (RFC 6455) as grpc.ServerStream,
+// so that the generated code does not depend on packages other than the standard library, protobuf and grpc.
+func genWebSocketStream(g *protogen.GeneratedFile, file *protogen.File) {
+	name := webSocketStreamName(file.Desc)
+	upgrade := runtimeName(file.Desc, "newHTTPWebSocketStream")
+	g.P("// ", name, " implements grpc.ServerStream on WebSocket.")
+	g.P("// Messages are received from JSON text frames or protobuf binary frames,")
+	g.P("// and sent as protobuf binary frames if \"protobuf\" subprotocol is negotiated, otherwise as JSON text frames.")
+	g.P("type ", name, " struct {")
+	g.P("	ctx      ", contextPackage.Ident("Context"))
+	g.P("	cancel   ", contextPackage.Ident("CancelFunc"))
+	g.P("	conn     ", netPackage.Ident("Conn"))
+	g.P("	rw       *", bufioPackage.Ident("ReadWriter"))
+	g.P("	protobuf bool")
+	g.P()
+	g.P("	// maxMessageSize is the maximum size of a message received from the client in bytes. Zero or a negative value means no limit.")
+	g.P("	maxMessageSize int64")
+	g.P()
+	g.P("	// mu guards writing frames.")
+	g.P("	mu ", syncPackage.Ident("Mutex"))
+	g.P("	// closeOnce ensures that a close frame is sent once.")
+	g.P("	closeOnce ", syncPackage.Ident("Once"))
+	g.P("}")
+	g.P()
+	g.P("// ", upgrade, " upgrades the connection to WebSocket.")
+	g.P("// If the request is not a valid WebSocket handshake or comes from an origin other than the request host and origins,")
+	g.P("// the error response is written to w.")
+	g.P("func ", upgrade, "(ctx ", contextPackage.Ident("Context"), ", w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ", origins []string, maxMessageSize int64) (*", name, ", error) {")
+	g.P("	upgrade := false")
+	g.P("	for _, v := range r.Header.Values(\"Connection\") {")
+	g.P("		for _, token := range ", stringsPackage.Ident("Split"), "(v, \",\") {")
+	g.P("			if ", stringsPackage.Ident("EqualFold"), "(", stringsPackage.Ident("TrimSpace"), "(token), \"upgrade\") {")
+	g.P("				upgrade = true")
+	g.P("			}")
+	g.P("		}")
+	g.P("	}")
+	g.P("	key := r.Header.Get(\"Sec-WebSocket-Key\")")
+	g.P("	if r.Method != ", httpPackage.Ident("MethodGet"), " || !upgrade || !", stringsPackage.Ident("EqualFold"), "(r.Header.Get(\"Upgrade\"), \"websocket\") || key == \"\" {")
+	g.P("		w.WriteHeader(", httpPackage.Ident("StatusBadRequest"), ")")
+	g.P("		_, _ = ", fmtPackage.Ident("Fprintf"), "(w, \"Bad WebSocket handshake\")")
+	g.P("		return nil, ", fmtPackage.Ident("Errorf"), "(\"bad WebSocket handshake\")")
+	g.P("	}")
+	g.P("	if version := r.Header.Get(\"Sec-WebSocket-Version\"); version != \"13\" {")
+	g.P("		w.Header().Set(\"Sec-WebSocket-Version\", \"13\")")
+	g.P("		w.WriteHeader(", httpPackage.Ident("StatusUpgradeRequired"), ")")
+	g.P("		_, _ = ", fmtPackage.Ident("Fprintf"), "(w, \"Unsupported Sec-WebSocket-Version: %s\", version)")
+	g.P("		return nil, ", fmtPackage.Ident("Errorf"), "(\"unsupported Sec-WebSocket-Version: %s\", version)")
+	g.P("	}")
+	g.P("	// Browsers send cookies with WebSocket handshakes of any origin, so only the same origin is allowed by default.")
+	g.P("	// Requests without Origin header are not sent by browsers.")
+	g.P("	if origin := r.Header.Get(\"Origin\"); origin != \"\" {")
+	g.P("		allowed := false")
+	g.P("		if u, err := ", urlPackage.Ident("Parse"), "(origin); err == nil && ", stringsPackage.Ident("EqualFold"), "(u.Host, r.Host) {")
+	g.P("			allowed = true")
+	g.P("		}")
+	g.P("		for _, o := range origins {")
+	g.P("			if o == \"*\" || ", stringsPackage.Ident("EqualFold"), "(o, origin) {")
+	g.P("				allowed = true")
+	g.P("			}")
+	g.P("		}")
+	g.P("		if !allowed {")
+	g.P("			w.WriteHeader(", httpPackage.Ident("StatusForbidden"), ")")
+	g.P("			_, _ = ", fmtPackage.Ident("Fprintf"), "(w, \"Origin not allowed: %s\", origin)")
+	g.P("			return nil, ", fmtPackage.Ident("Errorf"), "(\"WebSocket origin not allowed: %s\", origin)")
+	g.P("		}")
+	g.P("	}")
+	g.P()
+	g.P("	protocol := \"\"")
+	g.P("	for _, v := range r.Header.Values(\"Sec-WebSocket-Protocol\") {")
+	g.P("		for _, token := range ", stringsPackage.Ident("Split"), "(v, \",\") {")
+	g.P("			if token = ", stringsPackage.Ident("TrimSpace"), "(token); protocol == \"\" && (token == \"json\" || token == \"protobuf\") {")
+	g.P("				protocol = token")
+	g.P("			}")
+	g.P("		}")
+	g.P("	}")
+	g.P()
+	g.P("	hj, ok := w.(", httpPackage.Ident("Hijacker"), ")")
+	g.P("	if !ok {")
+	g.P("		w.WriteHeader(", httpPackage.Ident("StatusInternalServerError"), ")")
+	g.P("		return nil, ", fmtPackage.Ident("Errorf"), "(\"%T does not implement http.Hijacker\", w)")
+	g.P("	}")
+	g.P("	conn, rw, err := hj.Hijack()")
+	g.P("	if err != nil {")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	// Deadlines set by http.Server are not applied to WebSocket.")
+	g.P("	if err := conn.SetDeadline(", timePackage.Ident("Time"), "{}); err != nil {")
+	g.P("		conn.Close()")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P()
+	g.P("	sum := ", sha1Package.Ident("Sum"), "([]byte(key + \"", webSocketGUID, "\"))")
+	g.P("	resp := \"HTTP/1.1 101 Switching Protocols\\r\\nUpgrade: websocket\\r\\nConnection: Upgrade\\r\\n\" +")
+	g.P("		\"Sec-WebSocket-Accept: \" + ", base64Package.Ident("StdEncoding"), ".EncodeToString(sum[:]) + \"\\r\\n\"")
+	g.P("	if protocol != \"\" {")
+	g.P("		resp += \"Sec-WebSocket-Protocol: \" + protocol + \"\\r\\n\"")
+	g.P("	}")
+	g.P("	if _, err := rw.WriteString(resp + \"\\r\\n\"); err != nil {")
+	g.P("		conn.Close()")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P("	if err := rw.Flush(); err != nil {")
+	g.P("		conn.Close()")
+	g.P("		return nil, err")
+	g.P("	}")
+	g.P()
+	g.P("	ctx, cancel := ", contextPackage.Ident("WithCancel"), "(ctx)")
+	g.P("	return &", name, "{")
+	g.P("		ctx:            ctx,")
+	g.P("		cancel:         cancel,")
+	g.P("		conn:           conn,")
+	g.P("		rw:             rw,")
+	g.P("		protobuf:       protocol == \"protobuf\",")
+	g.P("		maxMessageSize: maxMessageSize,")
+	g.P("	}, nil")
+	g.P("}")
+	g.P()
+	g.P("// SetHeader is ignored.")
+	g.P("func (s *", name, ") SetHeader(", metadataPackage.Ident("MD"), ") error {")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// SendHeader is ignored.")
+	g.P("func (s *", name, ") SendHeader(", metadataPackage.Ident("MD"), ") error {")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// SetTrailer is ignored.")
+	g.P("func (s *", name, ") SetTrailer(", metadataPackage.Ident("MD"), ") {")
+	g.P("}")
+	g.P()
+	g.P("func (s *", name, ") Context() ", contextPackage.Ident("Context"), " {")
+	g.P("	return s.ctx")
+	g.P("}")
+	g.P()
+	g.P("func (s *", name, ") SendMsg(m interface{}) error {")
+	g.P("	msg, ok := m.(", protoPackage.Ident("Message"), ")")
+	g.P("	if !ok {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), "(\"%T is not proto.Message\", m)")
+	g.P("	}")
+	g.P()
+	g.P("	if s.protobuf {")
+	g.P("		buf, err := ", protoPackage.Ident("Marshal"), "(msg)")
+	g.P("		if err != nil {")
+	g.P("			return err")
+	g.P("		}")
+	g.P("		return s.writeFrame(0x2, buf)")
+	g.P("	}")
+	g.P("	buf, err := ", protojsonPackage.Ident("Marshal"), "(msg)")
+	g.P("	if err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	return s.writeFrame(0x1, buf)")
+	g.P("}")
+	g.P()
+	g.P("// RecvMsg reads the next message. It returns io.EOF when the client sends a close frame.")
+	g.P("func (s *", name, ") RecvMsg(m interface{}) error {")
+	g.P("	msg, ok := m.(", protoPackage.Ident("Message"), ")")
+	g.P("	if !ok {")
+	g.P("		return ", fmtPackage.Ident("Errorf"), "(\"%T is not proto.Message\", m)")
+	g.P("	}")
+	g.P()
+	g.P("	opcode, payload, err := s.readMessage()")
+	g.P("	if err != nil {")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	if opcode == 0x2 {")
+	g.P("		return ", protoPackage.Ident("Unmarshal"), "(payload, msg)")
+	g.P("	}")
+	g.P("	return ", protojsonPackage.Ident("Unmarshal"), "(payload, msg)")
+	g.P("}")
+	g.P()
+	g.P("// readMessage reads frames until a whole text or binary message is read, and replies to ping frames.")
+	g.P("// The connection is closed with 1009 (Message Too Big) if the message is larger than maxMessageSize,")
+	g.P("// and with 1002 (Protocol Error) if the frames violate RFC 6455.")
+	g.P("func (s *", name, ") readMessage() (byte, []byte, error) {")
+	g.P("	var opcode byte")
+	g.P("	var message []byte")
+	g.P("	for {")
+	g.P("		fin, op, payload, err := s.readFrame(s.maxMessageSize - int64(len(message)))")
+	g.P("		if err != nil {")
+	g.P("			s.cancel()")
+	g.P("			return 0, nil, err")
+	g.P("		}")
+	g.P()
+	g.P("		switch op {")
+	g.P("		case 0x8: // close")
+	g.P("			return 0, nil, ", ioPackage.Ident("EOF"))
+	g.P("		case 0x9: // ping")
+	g.P("			if err := s.writeFrame(0xa, payload); err != nil {")
+	g.P("				return 0, nil, err")
+	g.P("			}")
+	g.P("			continue")
+	g.P("		case 0xa: // pong")
+	g.P("			continue")
+	g.P("		case 0x0: // continuation")
+	g.P("			if opcode == 0 {")
+	g.P("				return 0, nil, s.fail(1002, \"continuation frame without preceding data frame\")")
+	g.P("			}")
+	g.P("		default:")
+	g.P("			if opcode != 0 {")
+	g.P("				return 0, nil, s.fail(1002, \"data frame inside fragmented message\")")
+	g.P("			}")
+	g.P("			opcode = op")
+	g.P("		}")
+	g.P()
+	g.P("		message = append(message, payload...)")
+	g.P("		if fin {")
+	g.P("			return opcode, message, nil")
+	g.P("		}")
+	g.P("	}")
+	g.P("}")
+	g.P()
+	g.P("// readFrame reads a frame sent by the client and returns FIN bit, opcode and unmasked payload.")
+	g.P("// The payload of data frames must not be larger than limit if maxMessageSize is positive.")
+	g.P("func (s *", name, ") readFrame(limit int64) (bool, byte, []byte, error) {")
+	g.P("	var header [2]byte")
+	g.P("	if _, err := ", ioPackage.Ident("ReadFull"), "(s.rw, header[:]); err != nil {")
+	g.P("		return false, 0, nil, err")
+	g.P("	}")
+	g.P("	fin := header[0]&0x80 != 0")
+	g.P("	opcode := header[0] & 0x0f")
+	g.P("	control := opcode&0x8 != 0")
+	g.P("	if header[0]&0x70 != 0 {")
+	g.P("		return false, 0, nil, s.fail(1002, \"reserved bits are set without extension\")")
+	g.P("	}")
+	g.P("	switch opcode {")
+	g.P("	case 0x0, 0x1, 0x2, 0x8, 0x9, 0xa:")
+	g.P("	default:")
+	g.P("		return false, 0, nil, s.fail(1002, ", fmtPackage.Ident("Sprintf"), "(\"reserved opcode %#x\", opcode))")
+	g.P("	}")
+	g.P("	if header[1]&0x80 == 0 {")
+	g.P("		return false, 0, nil, s.fail(1002, \"frame from client is not masked\")")
+	g.P("	}")
+	g.P()
+	g.P("	size := uint64(header[1] & 0x7f)")
+	g.P("	switch size {")
+	g.P("	case 126:")
+	g.P("		var b [2]byte")
+	g.P("		if _, err := ", ioPackage.Ident("ReadFull"), "(s.rw, b[:]); err != nil {")
+	g.P("			return false, 0, nil, err")
+	g.P("		}")
+	g.P("		size = uint64(", binaryPackage.Ident("BigEndian"), ".Uint16(b[:]))")
+	g.P("	case 127:")
+	g.P("		var b [8]byte")
+	g.P("		if _, err := ", ioPackage.Ident("ReadFull"), "(s.rw, b[:]); err != nil {")
+	g.P("			return false, 0, nil, err")
+	g.P("		}")
+	g.P("		size = ", binaryPackage.Ident("BigEndian"), ".Uint64(b[:])")
+	g.P("	}")
+	g.P("	if control && (!fin || size > 125) {")
+	g.P("		return false, 0, nil, s.fail(1002, \"control frame is fragmented or too large\")")
+	g.P("	}")
+	g.P("	if !control && s.maxMessageSize > 0 && size > uint64(limit) {")
+	g.P("		return false, 0, nil, s.fail(1009, ", fmtPackage.Ident("Sprintf"), "(\"message is larger than %d bytes\", s.maxMessageSize))")
+	g.P("	}")
+	g.P("	if size > ", mathPackage.Ident("MaxInt64"), " {")
+	g.P("		return false, 0, nil, s.fail(1009, \"frame is too large\")")
+	g.P("	}")
+	g.P()
+	g.P("	var mask [4]byte")
+	g.P("	if _, err := ", ioPackage.Ident("ReadFull"), "(s.rw, mask[:]); err != nil {")
+	g.P("		return false, 0, nil, err")
+	g.P("	}")
+	g.P("	payload, err := ", ioPackage.Ident("ReadAll"), "(", ioPackage.Ident("LimitReader"), "(s.rw, int64(size)))")
+	g.P("	if err != nil {")
+	g.P("		return false, 0, nil, err")
+	g.P("	}")
+	g.P("	if uint64(len(payload)) != size {")
+	g.P("		return false, 0, nil, ", ioPackage.Ident("ErrUnexpectedEOF"))
+	g.P("	}")
+	g.P("	for i := range payload {")
+	g.P("		payload[i] ^= mask[i%4]")
+	g.P("	}")
+	g.P("	return fin, opcode, payload, nil")
+	g.P("}")
+	g.P()
+	g.P("// writeFrame writes an unfragmented frame. The context is canceled if the client has gone away.")
+	g.P("func (s *", name, ") writeFrame(opcode byte, payload []byte) error {")
+	g.P("	s.mu.Lock()")
+	g.P("	defer s.mu.Unlock()")
+	g.P()
+	g.P("	buf := []byte{0x80 | opcode}")
+	g.P("	switch n := len(payload); {")
+	g.P("	case n < 126:")
+	g.P("		buf = append(buf, byte(n))")
+	g.P("	case n <= 0xffff:")
+	g.P("		buf = ", binaryPackage.Ident("BigEndian"), ".AppendUint16(append(buf, 126), uint16(n))")
+	g.P("	default:")
+	g.P("		buf = ", binaryPackage.Ident("BigEndian"), ".AppendUint64(append(buf, 127), uint64(n))")
+	g.P("	}")
+	g.P("	if _, err := s.rw.Write(append(buf, payload...)); err != nil {")
+	g.P("		s.cancel()")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	if err := s.rw.Flush(); err != nil {")
+	g.P("		s.cancel()")
+	g.P("		return err")
+	g.P("	}")
+	g.P("	return nil")
+	g.P("}")
+	g.P()
+	g.P("// close writes a close frame carrying the status of the RPC.")
+	g.P("// OK is sent as 1000 (Normal Closure), and other codes are sent as 4000 + code with the status message as the reason.")
+	g.P("func (s *", name, ") close(err error) {")
+	g.P("	st := ", statusPackage.Ident("Convert"), "(err)")
+	g.P("	code := uint16(1000)")
+	g.P("	if st.Code() != ", codesPackage.Ident("OK"), " {")
+	g.P("		code = 4000 + uint16(st.Code())")
+	g.P("	}")
+	g.P("	s.closeWith(code, st.Message())")
+	g.P("}")
+	g.P()
+	g.P("// fail closes the connection with the close code because the client violated the protocol or the size limit,")
+	g.P("// and returns the error of the RPC. Messages are not read any more.")
+	g.P("func (s *", name, ") fail(code uint16, reason string) error {")
+	g.P("	s.closeWith(code, reason)")
+	g.P("	s.cancel()")
+	g.P("	c := ", codesPackage.Ident("InvalidArgument"))
+	g.P("	if code == 1009 {")
+	g.P("		c = ", codesPackage.Ident("ResourceExhausted"))
+	g.P("	}")
+	g.P("	return ", statusPackage.Ident("Errorf"), "(c, \"WebSocket: %s\", reason)")
+	g.P("}")
+	g.P()
+	g.P("// closeWith writes a close frame with the code and the reason once, and closes the connection.")
+	g.P("func (s *", name, ") closeWith(code uint16, reason string) {")
+	g.P("	s.closeOnce.Do(func() {")
+	g.P("		// The payload of control frames must be 125 bytes or less, and the reason must be valid UTF-8.")
+	g.P("		if len(reason) > 123 {")
+	g.P("			reason = reason[:123]")
+	g.P("		}")
+	g.P("		reason = ", stringsPackage.Ident("ToValidUTF8"), "(reason, \"\")")
+	g.P("		_ = s.writeFrame(0x8, append(", binaryPackage.Ident("BigEndian"), ".AppendUint16(nil, code), reason...))")
+	g.P("		s.conn.Close()")
+	g.P("	})")
+	g.P("}")
+}
+
+// genWebSocketOptions generates the converter option of WebSocket handshakes.
+func genWebSocketOptions(g *protogen.GeneratedFile, srv *protogen.Service) {
+	g.P("// With", srv.GoName, "HTTPWebSocketOrigins sets the origins, e.g. https://example.com, allowed to open WebSocket connections")
+	g.P("// in addition to the origin whose host is the request host. \"*\" allows any origin.")
+	g.P("// By default, handshakes with Origin header of other origins are rejected with 403 Forbidden.")
+	g.P("func With", srv.GoName, "HTTPWebSocketOrigins(origins ...string) ", srv.GoName, "HTTPConverterOption {")
+	g.P("	return func(h *", srv.GoName, "HTTPConverter) {")
+	g.P("		h.webSocketOrigins = append(h.webSocketOrigins, origins...)")
+	g.P("	}")
+	g.P("}")
+}
+
+// genWebSocketMethod generates the body of the convert method of bidirectional streaming method.
+// The default callback does nothing because the connection has been hijacked after the handshake
+// and the status of the RPC is sent by the close frame.
+func genWebSocketMethod(g *protogen.GeneratedFile, method *protogen.Method) {
+	g.P("if cb == nil {")
+	g.P("	cb = h.cb")
+	g.P("}")
+	g.P("if cb == nil {")
+	g.P("	cb = ", callbackSignature(g), " {}")
+	g.P("}")
+	genDefaultInterceptors(g, method)
+	g.P("	return ", httpPackage.Ident("HandlerFunc"), "(func(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ") {")
+	genRequestContext(g, method, "")
+	g.P("")
+	g.P("		stream, err := ", runtimeName(method.Parent.Desc.ParentFile(), "newHTTPWebSocketStream"), "(ctx, w, r, h.webSocketOrigins, h.maxBodySize)")
+	g.P("		if err != nil {")
+	g.P("			cb(ctx, w, r, nil, nil, err)")
+	g.P("			return")
+	g.P("		}")
+	g.P("		defer stream.cancel()")
+	g.P("")
+	genStreamChain(g, method, "h.srv."+method.GoName+"(&"+methodStreamName(method)+"{ss})")
+	g.P("		err = chained(h.srv, stream, info, handler)")
+	g.P("		stream.close(err)")
+	g.P("		if err != nil {")
+	g.P("			cb(ctx, w, r, nil, nil, err)")
+	g.P("			return")
+	g.P("		}")
+	g.P("		cb(ctx, w, r, nil, nil, nil)")
+	g.P("	})")
+	g.P("}")
+}